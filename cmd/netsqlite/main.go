@@ -2,32 +2,259 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"flag"
+	"fmt"
 	"log"
+	"log/slog"
+	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/alfredosa/netsqlite/internal/auth"
+	"github.com/alfredosa/netsqlite/internal/config"
 	proto "github.com/alfredosa/netsqlite/internal/grpc"
+	"github.com/alfredosa/netsqlite/pkg/drivers"
 )
 
 var (
+	configFile = flag.String("config", "", "YAML config file (default $"+config.FileEnv+"), the flags below override it")
 	listenAddr = flag.String("addr", ":3541", "Address and port to listen on for gRPC")
 	datadir    = flag.String("dir", "data", "Data directory for all databases")
-	// TODO: Add flags or env vars for loading tokens securely
+	tokensFile = flag.String("tokens", "", "Token file (default $"+auth.TokensFileEnv+"), reloaded on SIGHUP")
+	autoCreate = flag.Bool("auto-create", true, "Create databases on first use, otherwise they must already exist in -dir")
+
+	tlsCert          = flag.String("tls-cert", "", "PEM certificate of the server, enables TLS")
+	tlsKey           = flag.String("tls-key", "", "PEM private key of -tls-cert")
+	tlsClientCA      = flag.String("tls-client-ca", "", "PEM CA verifying client certificates, which can then authenticate instead of tokens")
+	tlsRequireClient = flag.Bool("tls-require-client-cert", false, "Reject clients without a certificate signed by -tls-client-ca (mutual TLS)")
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "hash-token":
+			hashToken(os.Args[2:])
+			return
+		case "config":
+			configCommand(os.Args[2:])
+			return
+		case "backup":
+			backupCommand(os.Args[2:])
+			return
+		}
+	}
+
 	flag.Parse()
-	log.Printf("Starting netsqlite gRPC server on %s, dir %s", *listenAddr, *datadir)
+	cfg, err := config.Load(configPath(*configFile))
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	applyFlags(cfg)
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config (see netsqlite config check):\n%v", err)
+	}
+	slog.SetDefault(cfg.Logging.Logger(os.Stderr))
+	log.Printf("Starting netsqlite gRPC server on %s, dir %s", strings.Join(cfg.Listen, ", "), cfg.DataDir)
+
+	tokens, err := auth.LoadStore(cfg.TokensFile)
+	if err != nil {
+		log.Fatalf("Failed to load tokens: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	tokens.ReloadOnSignal(ctx, syscall.SIGHUP)
+
+	proto.Start(ctx, tokens, cfg.Server())
+}
+
+// configPath falls back to the environment when no config file was given.
+func configPath(path string) string {
+	if path == "" {
+		return os.Getenv(config.FileEnv)
+	}
+	return path
+}
+
+// applyFlags overrides the config with the flags given on the command line.
+func applyFlags(cfg *config.Config) {
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "addr":
+			cfg.Listen = []string{*listenAddr}
+		case "dir":
+			cfg.DataDir = *datadir
+		case "tokens":
+			cfg.TokensFile = *tokensFile
+		case "auto-create":
+			cfg.AutoCreate = *autoCreate
+		case "tls-cert":
+			cfg.TLS.Cert = *tlsCert
+		case "tls-key":
+			cfg.TLS.Key = *tlsKey
+		case "tls-client-ca":
+			cfg.TLS.ClientCA = *tlsClientCA
+		case "tls-require-client-cert":
+			cfg.TLS.RequireClientCert = *tlsRequireClient
+		}
+	})
+}
+
+// configCommand runs the config subcommands.
+func configCommand(args []string) {
+	fs := flag.NewFlagSet("config check", flag.ExitOnError)
+	path := fs.String("config", "", "Config file to check (default $"+config.FileEnv+")")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: netsqlite config check [-config <file>]")
+		fmt.Fprintln(fs.Output(), "Validates the config, with the environment overrides applied, and the token and TLS files it points at.")
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 || args[0] != "check" {
+		fs.Usage()
+		os.Exit(2)
+	}
+	fs.Parse(args[1:])
+
+	name := configPath(*path)
+	if name == "" {
+		name = "default config"
+	}
+	cfg, err := config.Load(configPath(*path))
+	var tokens *auth.Store
+	if err == nil {
+		tokens, err = cfg.Check()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s is invalid:\n", name)
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(os.Stderr, "  - %s\n", line)
+		}
+		os.Exit(1)
+	}
+	fmt.Printf("%s is valid: listening on %s, data in %s, %d token(s)\n", name, strings.Join(cfg.Listen, ", "), cfg.DataDir, tokens.Len())
+}
 
-	// --- Load Tokens (TODO: Replace with secure method) ---
-	validTokens := map[string]bool{
-		"SUPERSECRETTOKEN": true,
-		"ANOTHERVALIDONE":  true,
+// hashToken prints a token file entry for a new (or the given) token.
+func hashToken(args []string) {
+	fs := flag.NewFlagSet("hash-token", flag.ExitOnError)
+	name := fs.String("name", "", "Name of the token, shows up in the server logs (required)")
+	token := fs.String("token", "", "Token to hash, a random one is generated if empty")
+	expiresIn := fs.Duration("expires-in", 0, "How long the token is valid for, forever if 0")
+	role := fs.String("role", "read-write", "Role of the token: read-only, read-write or admin")
+	databases := fs.String("databases", "", "Comma separated database name patterns the token can access, all if empty")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: netsqlite hash-token -name <name> [-token <token>] [-expires-in <duration>] [-role <role>] [-databases <patterns>]")
+		fmt.Fprintln(fs.Output(), "Prints an entry to add under tokens: in the token file.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *name == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if _, err := auth.ParseRole(*role); err != nil {
+		log.Fatal(err)
+	}
+
+	generated := *token == ""
+	if generated {
+		var err error
+		if *token, err = auth.GenerateToken(); err != nil {
+			log.Fatalf("Failed to generate token: %v", err)
+		}
+	}
+	hash, err := auth.HashToken(*token)
+	if err != nil {
+		log.Fatalf("Failed to hash token: %v", err)
+	}
+
+	if generated {
+		fmt.Fprintf(os.Stderr, "Token (shown only once): %s\n", *token)
+	}
+	fmt.Printf("  - name: %s\n    hash: %s\n    role: %s\n", *name, hash, *role)
+	if *databases != "" {
+		var quoted []string
+		for _, pattern := range strings.Split(*databases, ",") {
+			quoted = append(quoted, strconv.Quote(strings.TrimSpace(pattern)))
+		}
+		fmt.Printf("    databases: [%s]\n", strings.Join(quoted, ", "))
+	}
+	if *expiresIn > 0 {
+		fmt.Printf("    expires_at: %s\n", time.Now().Add(*expiresIn).UTC().Format(time.RFC3339))
+	}
+}
+
+// backupCommand saves a backup of a database of a running server to a file.
+func backupCommand(args []string) {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	dsn := fs.String("dsn", os.Getenv("NETSQLITE_DSN"), "DSN of the database to back up, with an admin token (default $NETSQLITE_DSN)")
+	out := fs.String("out", "", "File to write the backup to, <database>-<timestamp>.db if empty")
+	timeout := fs.Duration("timeout", 0, "Give up after this long, never if 0")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: netsqlite backup -dsn netsqlite://host:port/token?database=name [-out <file>] [-timeout <duration>]")
+		fmt.Fprintln(fs.Output(), "Writes a consistent snapshot of the database, taken while it stays in use, to a local file.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *dsn == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+	cfg, err := drivers.ParseDSN(*dsn)
+	if err != nil {
+		log.Fatalf("Invalid DSN: %v", err)
+	}
+	path := *out
+	if path == "" {
+		path = fmt.Sprintf("%s-%s.db", strings.TrimSuffix(cfg.DBName, ".db"), time.Now().UTC().Format("20060102T150405Z"))
 	}
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
 
-	proto.Start(ctx, validTokens, *listenAddr, *datadir)
+	db, err := sql.Open(drivers.DriverName, *dsn)
+	if err != nil {
+		log.Fatalf("Failed to connect: %v", err)
+	}
+	defer db.Close()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		log.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	// Written next to the destination first, so a failed backup never
+	// leaves a partial file behind or replaces a good one
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		log.Fatalf("Failed to create backup file: %v", err)
+	}
+	n, err := drivers.Backup(ctx, conn, tmp)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		log.Fatalf("Backup of %s failed: %v", cfg.DBName, err)
+	}
+	fmt.Printf("Backed up %s to %s (%d bytes)\n", cfg.DBName, path, n)
 }
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"crypto/x509"
+	"strings"
+)
+
+// Prefixes of the client certificate identities, as written in the
+// client_certs of the token file.
+const (
+	certCommonName = "CN="
+	certDNS        = "DNS:"
+	certURI        = "URI:"
+	certEmail      = "EMAIL:"
+)
+
+// CertIdentities lists the identities a verified client certificate can be
+// granted permissions by: its subject common name as "CN=<name>" and its
+// subject alternative names as "DNS:<host>", "URI:<uri>" and
+// "EMAIL:<address>".
+func CertIdentities(cert *x509.Certificate) []string {
+	var ids []string
+	if cert.Subject.CommonName != "" {
+		ids = append(ids, certCommonName+cert.Subject.CommonName)
+	}
+	for _, name := range cert.DNSNames {
+		ids = append(ids, certDNS+name)
+	}
+	for _, uri := range cert.URIs {
+		ids = append(ids, certURI+uri.String())
+	}
+	for _, email := range cert.EmailAddresses {
+		ids = append(ids, certEmail+email)
+	}
+	return ids
+}
+
+func validCertIdentity(id string) bool {
+	for _, prefix := range []string{certCommonName, certDNS, certURI, certEmail} {
+		if strings.HasPrefix(id, prefix) && len(id) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"path"
+)
+
+// Role decides what a token may do on the databases it has access to.
+type Role int
+
+const (
+	// RoleReadOnly can only run statements that don't write
+	RoleReadOnly Role = iota + 1
+	// RoleReadWrite can read and write
+	RoleReadWrite
+	// RoleAdmin can also use administrative RPCs
+	RoleAdmin
+)
+
+// ParseRole parses a role as written in the token file, read-write if empty.
+func ParseRole(s string) (Role, error) {
+	switch s {
+	case "read-only":
+		return RoleReadOnly, nil
+	case "", "read-write":
+		return RoleReadWrite, nil
+	case "admin":
+		return RoleAdmin, nil
+	default:
+		return 0, fmt.Errorf("unknown role %q, must be read-only, read-write or admin", s)
+	}
+}
+
+func (r Role) String() string {
+	switch r {
+	case RoleReadOnly:
+		return "read-only"
+	case RoleReadWrite:
+		return "read-write"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return fmt.Sprintf("Role(%d)", int(r))
+	}
+}
+
+// Principal is the authenticated client behind a request.
+type Principal struct {
+	Name string // Name of the token used
+	Role Role
+	// Databases are the path.Match patterns of the database names the
+	// principal can access
+	Databases []string
+}
+
+// CanAccess reports whether the principal may use the database at all.
+func (p *Principal) CanAccess(dbName string) bool {
+	for _, pattern := range p.Databases {
+		if ok, _ := path.Match(pattern, dbName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CanWrite reports whether the principal may run statements that write.
+func (p *Principal) CanWrite() bool {
+	return p.Role >= RoleReadWrite
+}
+
+// IsAdmin reports whether the principal may use administrative RPCs.
+func (p *Principal) IsAdmin() bool {
+	return p.Role >= RoleAdmin
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying p.
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, if any.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TokensFileEnv names the environment variable pointing at the token file
+// when it isn't given on the command line.
+const TokensFileEnv = "NETSQLITE_TOKENS_FILE"
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token expired")
+	ErrRevokedToken = errors.New("token revoked")
+)
+
+// tokenFile is the layout of the token file:
+//
+//	tokens:
+//	  - name: ci
+//	    hash: sha256:<salt hex>:<digest hex>
+//	    expires_at: 2026-01-01T00:00:00Z
+//	  - name: bi
+//	    hash: sha256:...
+//	    role: read-only
+//	    databases: ["analytics-*", "sales.db"]
+//	  - name: reporting
+//	    client_certs: ["CN=reporting", "DNS:reports.internal"]
+//	    role: read-only
+//	  - name: old-laptop
+//	    hash: sha256:...
+//	    revoked: true
+type tokenFile struct {
+	Tokens []Token `yaml:"tokens"`
+}
+
+// Store validates client tokens against a set of hashed tokens, optionally
+// loaded from a file that can be reloaded while the server runs.
+type Store struct {
+	path string // empty if the tokens weren't loaded from a file
+
+	mu     sync.RWMutex
+	tokens []Token
+}
+
+// NewStore returns a store holding the given tokens.
+func NewStore(tokens ...Token) (*Store, error) {
+	s := &Store{}
+	if err := s.set(tokens); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// LoadStore reads the token file at path.
+func LoadStore(path string) (*Store, error) {
+	s := &Store{path: path}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload reads the token file again. If it can't be read or is invalid the
+// tokens loaded before are kept.
+func (s *Store) Reload() error {
+	if s.path == "" {
+		return errors.New("token store was not loaded from a file")
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("reading token file: %w", err)
+	}
+	var file tokenFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("parsing token file %s: %w", s.path, err)
+	}
+	if err := s.set(file.Tokens); err != nil {
+		return fmt.Errorf("token file %s: %w", s.path, err)
+	}
+	return nil
+}
+
+func (s *Store) set(tokens []Token) error {
+	seen := make(map[string]bool, len(tokens))
+	for i := range tokens {
+		if err := tokens[i].parse(); err != nil {
+			return err
+		}
+		if seen[tokens[i].Name] {
+			return fmt.Errorf("duplicate token name %q", tokens[i].Name)
+		}
+		seen[tokens[i].Name] = true
+	}
+
+	s.mu.Lock()
+	s.tokens = tokens
+	s.mu.Unlock()
+	return nil
+}
+
+// Len returns how many tokens are loaded, revoked and expired ones included.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.tokens)
+}
+
+// Authenticate returns the principal of the token matching token. Every
+// stored token is checked so the time taken doesn't give away which one
+// matched. For revoked and expired tokens the principal is returned along
+// with the error, for logging.
+func (s *Store) Authenticate(token string) (*Principal, error) {
+	if token == "" {
+		return nil, ErrInvalidToken
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var match *Token
+	for i := range s.tokens {
+		if s.tokens[i].matches(token) && match == nil {
+			match = &s.tokens[i]
+		}
+	}
+
+	return match.check()
+}
+
+// AuthenticateCert returns the principal of the first entry granted one of
+// the identities of a verified client certificate, see CertIdentities.
+func (s *Store) AuthenticateCert(ids []string) (*Principal, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := range s.tokens {
+		if s.tokens[i].matchesCert(ids) {
+			return s.tokens[i].check()
+		}
+	}
+	return nil, ErrInvalidToken
+}
+
+// check returns the principal of a matched entry, with an error if it can't
+// be used anymore.
+func (t *Token) check() (*Principal, error) {
+	switch {
+	case t == nil:
+		return nil, ErrInvalidToken
+	case t.Revoked:
+		return t.principal, ErrRevokedToken
+	case t.expired(time.Now()):
+		return t.principal, ErrExpiredToken
+	default:
+		return t.principal, nil
+	}
+}
+
+// ReloadOnSignal reloads the token file every time one of sig is received,
+// until ctx is done.
+func (s *Store) ReloadOnSignal(ctx context.Context, sig ...os.Signal) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig...)
+
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ch:
+				if err := s.Reload(); err != nil {
+					log.Printf("Token reload failed, keeping the previous tokens: %v", err)
+					continue
+				}
+				log.Printf("Reloaded %d token(s) from %s", s.Len(), s.path)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,157 @@
+package auth_test
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alfredosa/netsqlite/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustHash(t *testing.T, token string) string {
+	t.Helper()
+	hash, err := auth.HashToken(token)
+	require.NoError(t, err)
+	return hash
+}
+
+func TestStore_Authenticate(t *testing.T) {
+	store, err := auth.NewStore(
+		auth.Token{Name: "ci", Hash: mustHash(t, "ci-secret")},
+		auth.Token{Name: "old", Hash: mustHash(t, "old-secret"), Revoked: true},
+		auth.Token{Name: "temp", Hash: mustHash(t, "temp-secret"), ExpiresAt: time.Now().Add(-time.Minute)},
+		auth.Token{Name: "later", Hash: mustHash(t, "later-secret"), ExpiresAt: time.Now().Add(time.Hour)},
+	)
+	require.NoError(t, err)
+
+	p, err := store.Authenticate("ci-secret")
+	require.NoError(t, err)
+	assert.Equal(t, "ci", p.Name)
+	assert.Equal(t, auth.RoleReadWrite, p.Role)
+	assert.True(t, p.CanAccess("anything.db"))
+
+	p, err = store.Authenticate("later-secret")
+	require.NoError(t, err)
+	assert.Equal(t, "later", p.Name)
+
+	_, err = store.Authenticate("nope")
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	_, err = store.Authenticate("")
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	p, err = store.Authenticate("old-secret")
+	assert.ErrorIs(t, err, auth.ErrRevokedToken)
+	assert.Equal(t, "old", p.Name)
+
+	_, err = store.Authenticate("temp-secret")
+	assert.ErrorIs(t, err, auth.ErrExpiredToken)
+}
+
+func TestStore_RejectsBadTokens(t *testing.T) {
+	_, err := auth.NewStore(auth.Token{Name: "plain", Hash: "SUPERSECRETTOKEN"})
+	assert.Error(t, err)
+
+	_, err = auth.NewStore(auth.Token{Hash: mustHash(t, "x")})
+	assert.Error(t, err)
+
+	_, err = auth.NewStore(
+		auth.Token{Name: "dup", Hash: mustHash(t, "a")},
+		auth.Token{Name: "dup", Hash: mustHash(t, "b")},
+	)
+	assert.Error(t, err)
+
+	_, err = auth.NewStore(auth.Token{Name: "root", Hash: mustHash(t, "x"), Role: "superuser"})
+	assert.Error(t, err)
+
+	_, err = auth.NewStore(auth.Token{Name: "bad", Hash: mustHash(t, "x"), Databases: []string{"[a-"}})
+	assert.Error(t, err)
+}
+
+func TestStore_Permissions(t *testing.T) {
+	store, err := auth.NewStore(
+		auth.Token{Name: "bi", Hash: mustHash(t, "bi"), Role: "read-only", Databases: []string{"analytics-*", "sales.db"}},
+		auth.Token{Name: "ops", Hash: mustHash(t, "ops"), Role: "admin"},
+	)
+	require.NoError(t, err)
+
+	bi, err := store.Authenticate("bi")
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleReadOnly, bi.Role)
+	assert.False(t, bi.CanWrite())
+	assert.False(t, bi.IsAdmin())
+	assert.True(t, bi.CanAccess("analytics-2024"))
+	assert.True(t, bi.CanAccess("sales.db"))
+	assert.False(t, bi.CanAccess("production.db"))
+
+	ops, err := store.Authenticate("ops")
+	require.NoError(t, err)
+	assert.True(t, ops.CanWrite())
+	assert.True(t, ops.IsAdmin())
+	assert.True(t, ops.CanAccess("production.db"))
+}
+
+func TestStore_AuthenticateCert(t *testing.T) {
+	store, err := auth.NewStore(
+		auth.Token{Name: "reporting", ClientCerts: []string{"CN=reporting", "DNS:reports.internal"}, Role: "read-only"},
+		auth.Token{Name: "old-host", ClientCerts: []string{"DNS:old.internal"}, Revoked: true},
+		auth.Token{Name: "ci", Hash: mustHash(t, "ci")},
+	)
+	require.NoError(t, err)
+
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "someone"}, DNSNames: []string{"reports.internal"}}
+	assert.Equal(t, []string{"CN=someone", "DNS:reports.internal"}, auth.CertIdentities(cert))
+
+	p, err := store.AuthenticateCert(auth.CertIdentities(cert))
+	require.NoError(t, err)
+	assert.Equal(t, "reporting", p.Name)
+	assert.Equal(t, auth.RoleReadOnly, p.Role)
+
+	_, err = store.AuthenticateCert([]string{"DNS:old.internal"})
+	assert.ErrorIs(t, err, auth.ErrRevokedToken)
+	_, err = store.AuthenticateCert([]string{"CN=ci"})
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	// Certificate only entries can't be used as tokens
+	_, err = store.Authenticate("")
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	_, err = store.Authenticate("reporting")
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	_, err = auth.NewStore(auth.Token{Name: "nothing"})
+	assert.Error(t, err)
+	_, err = auth.NewStore(auth.Token{Name: "bad", ClientCerts: []string{"reports.internal"}})
+	assert.Error(t, err)
+}
+
+func TestStore_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.yaml")
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	write("tokens:\n  - name: first\n    hash: " + mustHash(t, "one") + "\n")
+	store, err := auth.LoadStore(path)
+	require.NoError(t, err)
+	_, err = store.Authenticate("one")
+	require.NoError(t, err)
+
+	write("tokens:\n  - name: first\n    hash: " + mustHash(t, "one") + "\n    revoked: true\n" +
+		"  - name: second\n    hash: " + mustHash(t, "two") + "\n    expires_at: 2999-01-01T00:00:00Z\n")
+	require.NoError(t, store.Reload())
+	_, err = store.Authenticate("one")
+	assert.ErrorIs(t, err, auth.ErrRevokedToken)
+	p, err := store.Authenticate("two")
+	require.NoError(t, err)
+	assert.Equal(t, "second", p.Name)
+
+	// A broken file keeps the tokens loaded before
+	write("tokens: [")
+	assert.Error(t, store.Reload())
+	_, err = store.Authenticate("two")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	hashScheme = "sha256"
+	saltSize   = 16
+)
+
+// Token is an API token as stored in the token file. Only a salted hash of
+// the token itself is ever kept.
+type Token struct {
+	Name string `yaml:"name"`
+	// Hash is "sha256:<salt hex>:<digest hex>", see HashToken. It can be
+	// left out for entries only used with client certificates.
+	Hash string `yaml:"hash,omitempty"`
+	// ExpiresAt is when the token stops working, never if zero
+	ExpiresAt time.Time `yaml:"expires_at,omitempty"`
+	Revoked   bool      `yaml:"revoked,omitempty"`
+
+	// Role is read-only, read-write or admin, read-write if empty
+	Role string `yaml:"role,omitempty"`
+	// Databases are path.Match patterns of the databases the token can
+	// access, e.g. "analytics-*". Every database if empty.
+	Databases []string `yaml:"databases,omitempty"`
+	// ClientCerts are client certificate identities granted this entry's
+	// permissions over mutual TLS, see CertIdentities.
+	ClientCerts []string `yaml:"client_certs,omitempty"`
+
+	salt      []byte
+	digest    []byte
+	principal *Principal
+}
+
+// GenerateToken returns a new random token to hand out to a client.
+func GenerateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "nsq_" + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the salted hash of token to put in the token file.
+func HashToken(token string) (string, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%s:%s", hashScheme, hex.EncodeToString(salt), hex.EncodeToString(digest(salt, token))), nil
+}
+
+func digest(salt []byte, token string) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(token))
+	return h.Sum(nil)
+}
+
+// parse decodes the hash, it must be called before matches.
+func (t *Token) parse() error {
+	if t.Name == "" {
+		return fmt.Errorf("token without a name")
+	}
+	if t.Hash == "" && len(t.ClientCerts) == 0 {
+		return fmt.Errorf("token %q: needs a hash or client_certs", t.Name)
+	}
+
+	if t.Hash != "" {
+		parts := strings.Split(t.Hash, ":")
+		if len(parts) != 3 || parts[0] != hashScheme {
+			return fmt.Errorf("token %q: hash must look like %s:<salt>:<digest>", t.Name, hashScheme)
+		}
+		salt, err := hex.DecodeString(parts[1])
+		if err != nil || len(salt) == 0 {
+			return fmt.Errorf("token %q: invalid salt", t.Name)
+		}
+		sum, err := hex.DecodeString(parts[2])
+		if err != nil || len(sum) != sha256.Size {
+			return fmt.Errorf("token %q: invalid digest", t.Name)
+		}
+		t.salt = salt
+		t.digest = sum
+	}
+	for _, id := range t.ClientCerts {
+		if !validCertIdentity(id) {
+			return fmt.Errorf("token %q: invalid client certificate identity %q, want CN=, DNS:, URI: or EMAIL:", t.Name, id)
+		}
+	}
+
+	role, err := ParseRole(t.Role)
+	if err != nil {
+		return fmt.Errorf("token %q: %w", t.Name, err)
+	}
+	databases := t.Databases
+	if len(databases) == 0 {
+		databases = []string{"*"}
+	}
+	for _, pattern := range databases {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("token %q: invalid database pattern %q: %w", t.Name, pattern, err)
+		}
+	}
+
+	t.principal = &Principal{Name: t.Name, Role: role, Databases: databases}
+	return nil
+}
+
+// matches compares token against the stored hash in constant time.
+// Entries without a hash never match.
+func (t *Token) matches(token string) bool {
+	if t.digest == nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(digest(t.salt, token), t.digest) == 1
+}
+
+// matchesCert reports whether any of the certificate identities ids was
+// granted to this entry.
+func (t *Token) matchesCert(ids []string) bool {
+	for _, granted := range t.ClientCerts {
+		for _, id := range ids {
+			if granted == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t *Token) expired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && now.After(t.ExpiresAt)
+}
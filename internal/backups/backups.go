@@ -0,0 +1,410 @@
+// Package backups takes consistent backups of the databases on a schedule,
+// keeps track of them in a manifest and deletes the ones past retention.
+package backups
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	"google.golang.org/grpc/status"
+)
+
+// ManifestFile is the name of the manifest in the backup directory.
+const ManifestFile = "manifest.json"
+
+// Config of scheduled backups, they are off unless Dir is set.
+type Config struct {
+	Dir       string   // Where the backups and their manifest go
+	Schedule  string   // When to take them, see ParseSchedule
+	Databases []string // Only back these up, every database if empty
+
+	// Backups of a database past either limit are deleted, zero is no limit
+	Keep   int // Most recent backups kept
+	MaxAge time.Duration
+
+	Compress bool // gzip the backups
+}
+
+// Enabled reports whether backups are to be taken at all.
+func (c Config) Enabled() bool {
+	return c.Dir != ""
+}
+
+// Validate reports every problem with the configuration at once.
+func (c Config) Validate() error {
+	var errs []error
+	if _, err := ParseSchedule(c.Schedule); err != nil {
+		errs = append(errs, err)
+	}
+	for _, name := range c.Databases {
+		if err := nsqlite.ValidateName(name); err != nil {
+			errs = append(errs, errors.New(status.Convert(err).Message()))
+		}
+	}
+	if c.Keep < 0 {
+		errs = append(errs, fmt.Errorf("keep %d must not be negative", c.Keep))
+	}
+	if c.MaxAge < 0 {
+		errs = append(errs, fmt.Errorf("max age %s must not be negative", c.MaxAge))
+	}
+	return errors.Join(errs...)
+}
+
+// Backup is an entry of the manifest.
+type Backup struct {
+	Database   string    `json:"database"`
+	File       string    `json:"file"` // In the backup directory
+	TakenAt    time.Time `json:"taken_at"`
+	Size       int64     `json:"size_bytes"` // Of the file, compressed or not
+	SHA256     string    `json:"sha256"`     // Hex encoded, of the file
+	Compressed bool      `json:"compressed"`
+}
+
+type manifest struct {
+	Backups []Backup `json:"backups"`
+}
+
+// Scheduler takes the backups of a DBManager.
+type Scheduler struct {
+	manager  *nsqlite.DBManager
+	cfg      Config
+	schedule *Schedule
+
+	running    sync.Mutex // one run at a time
+	manifestMu sync.Mutex // guards the manifest file
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New checks cfg and creates the backup directory, backups are only taken
+// on schedule once Start is called.
+func New(manager *nsqlite.DBManager, cfg Config) (*Scheduler, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	schedule, _ := ParseSchedule(cfg.Schedule)
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating backup directory: %w", err)
+	}
+	return &Scheduler{
+		manager:  manager,
+		cfg:      cfg,
+		schedule: schedule,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// Start takes backups on schedule until Stop.
+func (s *Scheduler) Start() {
+	go s.loop()
+}
+
+// Stop cancels the run in progress, if any, and waits for it to end. It
+// must only be called once, after Start.
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	<-s.done
+}
+
+func (s *Scheduler) loop() {
+	defer close(s.done)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-s.stop
+		cancel()
+	}()
+
+	for {
+		next := s.schedule.Next(time.Now())
+		if next.IsZero() {
+			slog.Warn("Backup schedule never matches, no backups will be taken", "schedule", s.cfg.Schedule)
+			return
+		}
+		slog.Debug("Next scheduled backup", "at", next)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+		if err := s.Run(ctx, next); err != nil {
+			slog.Error("Scheduled backup failed", "error", err)
+		}
+	}
+}
+
+// Run backs up the databases now, records them in the manifest and deletes
+// the backups past retention as of now. A database failing to back up
+// doesn't stop the others, Run returns every error.
+func (s *Scheduler) Run(ctx context.Context, now time.Time) error {
+	s.running.Lock()
+	defer s.running.Unlock()
+
+	names := s.cfg.Databases
+	if len(names) == 0 {
+		infos, err := s.manager.List()
+		if err != nil {
+			return err
+		}
+		for _, info := range infos {
+			names = append(names, info.Name)
+		}
+	}
+
+	var taken []Backup
+	var errs []error
+	for _, name := range names {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+		backup, err := s.backup(ctx, name, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("backing up %s: %w", name, err))
+			continue
+		}
+		slog.Info("Took scheduled backup", "database", name, "file", backup.File, "size", backup.Size)
+		taken = append(taken, backup)
+	}
+
+	if err := s.record(taken, now); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// backup takes a backup of the database named dbName into the backup
+// directory.
+func (s *Scheduler) backup(ctx context.Context, dbName string, now time.Time) (Backup, error) {
+	backup := Backup{
+		Database:   dbName,
+		File:       fileName(dbName, now, s.cfg.Compress),
+		TakenAt:    now.UTC(),
+		Compressed: s.cfg.Compress,
+	}
+	dst := filepath.Join(s.cfg.Dir, backup.File)
+	if _, err := os.Stat(dst); err == nil {
+		return backup, fmt.Errorf("%s already exists", backup.File)
+	}
+
+	// Written next to the backups, so they are only ever seen whole
+	tmp, err := os.CreateTemp(s.cfg.Dir, ".snapshot-*")
+	if err != nil {
+		return backup, err
+	}
+	tmp.Close()
+	src := tmp.Name()
+	defer os.Remove(src)
+	if err := s.manager.Backup(ctx, dbName, src); err != nil {
+		return backup, err
+	}
+
+	if s.cfg.Compress {
+		src, err = compress(src)
+		if err != nil {
+			return backup, fmt.Errorf("compressing: %w", err)
+		}
+		defer os.Remove(src)
+	}
+	if backup.Size, backup.SHA256, err = checksum(src); err != nil {
+		return backup, err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return backup, err
+	}
+	return backup, nil
+}
+
+// fileName names backups after their database and when they were taken,
+// keeping the extension of the database so they sort and open as expected.
+func fileName(dbName string, t time.Time, compressed bool) string {
+	ext := filepath.Ext(dbName)
+	name := strings.TrimSuffix(dbName, ext) + "-" + t.UTC().Format("20060102T150405Z") + ext
+	if compressed {
+		name += ".gz"
+	}
+	return name
+}
+
+// compress gzips the file at path into a new file next to it.
+func compress(path string) (string, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	out, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+"-*.gz")
+	if err != nil {
+		return "", err
+	}
+
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, in)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(out.Name())
+		return "", err
+	}
+	return out.Name(), nil
+}
+
+func checksum(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	n, err := io.Copy(hash, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// record adds the backups just taken to the manifest, then deletes the
+// ones past retention as of now.
+func (s *Scheduler) record(taken []Backup, now time.Time) error {
+	s.manifestMu.Lock()
+	defer s.manifestMu.Unlock()
+
+	m, err := s.readManifest()
+	if err != nil {
+		return err
+	}
+	kept, expired := s.retain(append(m.Backups, taken...), now)
+	m.Backups = kept
+	// The manifest goes first, it must never list a file that's gone
+	if err := s.writeManifest(m); err != nil {
+		return err
+	}
+	for _, backup := range expired {
+		err := os.Remove(filepath.Join(s.cfg.Dir, backup.File))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("Failed to delete expired backup", "file", backup.File, "error", err)
+			continue
+		}
+		slog.Info("Deleted expired backup", "database", backup.Database, "file", backup.File)
+	}
+	return nil
+}
+
+// retain splits backups into the ones to keep, sorted like List, and the
+// ones past retention.
+func (s *Scheduler) retain(backups []Backup, now time.Time) (kept, expired []Backup) {
+	sortBackups(backups)
+	kept = make([]Backup, 0, len(backups))
+	var n int
+	for i, backup := range backups {
+		if i == 0 || backups[i-1].Database != backup.Database {
+			n = 0
+		}
+		n++
+		tooMany := s.cfg.Keep > 0 && n > s.cfg.Keep
+		tooOld := s.cfg.MaxAge > 0 && now.Sub(backup.TakenAt) > s.cfg.MaxAge
+		if tooMany || tooOld {
+			expired = append(expired, backup)
+		} else {
+			kept = append(kept, backup)
+		}
+	}
+	return kept, expired
+}
+
+// sortBackups sorts backups by database, then most recent first.
+func sortBackups(backups []Backup) {
+	slices.SortStableFunc(backups, func(a, b Backup) int {
+		if c := strings.Compare(a.Database, b.Database); c != 0 {
+			return c
+		}
+		return b.TakenAt.Compare(a.TakenAt)
+	})
+}
+
+// List returns the backups of the manifest sorted by database then most
+// recent first, only those of databases if any are given.
+func (s *Scheduler) List(databases ...string) ([]Backup, error) {
+	s.manifestMu.Lock()
+	m, err := s.readManifest()
+	s.manifestMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, backup := range m.Backups {
+		if len(databases) == 0 || slices.Contains(databases, backup.Database) {
+			backups = append(backups, backup)
+		}
+	}
+	sortBackups(backups)
+	return backups, nil
+}
+
+func (s *Scheduler) readManifest() (manifest, error) {
+	var m manifest
+	data, err := os.ReadFile(filepath.Join(s.cfg.Dir, ManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	} else if err != nil {
+		return m, fmt.Errorf("reading backup manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("parsing backup manifest: %w", err)
+	}
+	return m, nil
+}
+
+// writeManifest replaces the manifest with m in one rename, so a crash
+// leaves either the old one or the new one.
+func (s *Scheduler) writeManifest(m manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.cfg.Dir, ".manifest-*")
+	if err != nil {
+		return fmt.Errorf("writing backup manifest: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(append(data, '\n'))
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(s.cfg.Dir, ManifestFile))
+	}
+	if err != nil {
+		return fmt.Errorf("writing backup manifest: %w", err)
+	}
+	return nil
+}
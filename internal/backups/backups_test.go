@@ -0,0 +1,143 @@
+package backups_test
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alfredosa/netsqlite/internal/backups"
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_Run(t *testing.T) {
+	m := nsqlite.NewManager(t.TempDir())
+	defer m.Close(context.Background())
+	ctx := context.Background()
+
+	for _, name := range []string{"shop.db", "users.db"} {
+		lease, err := m.Acquire(ctx, name, nsqlite.Write)
+		require.NoError(t, err)
+		_, err = lease.Conn().ExecContext(ctx, `CREATE TABLE t (n INTEGER); INSERT INTO t VALUES (1), (2)`)
+		require.NoError(t, err)
+		lease.Release()
+	}
+
+	dir := filepath.Join(t.TempDir(), "backups")
+	s, err := backups.New(m, backups.Config{
+		Dir:      dir,
+		Schedule: "@daily",
+		Keep:     2,
+		MaxAge:   48 * time.Hour,
+		Compress: true,
+	})
+	require.NoError(t, err)
+
+	start := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)
+	for day := range 3 {
+		require.NoError(t, s.Run(ctx, start.Add(time.Duration(day)*24*time.Hour)))
+	}
+
+	// Only the two most recent are kept, of every database
+	list, err := s.List()
+	require.NoError(t, err)
+	require.Len(t, list, 4)
+	assert.Equal(t, "shop.db", list[0].Database)
+	assert.Equal(t, "shop-20261019T000000Z.db.gz", list[0].File)
+	assert.Equal(t, "shop-20261018T000000Z.db.gz", list[1].File)
+	assert.Equal(t, "users.db", list[2].Database)
+	assert.NoFileExists(t, filepath.Join(dir, "shop-20261017T000000Z.db.gz"))
+
+	// The manifest matches the files, which are whole databases
+	backup := list[0]
+	assert.True(t, backup.Compressed)
+	data, err := os.ReadFile(filepath.Join(dir, backup.File))
+	require.NoError(t, err)
+	sum := sha256.Sum256(data)
+	assert.Equal(t, hex.EncodeToString(sum[:]), backup.SHA256)
+	assert.Equal(t, int64(len(data)), backup.Size)
+
+	f, err := os.Open(filepath.Join(dir, backup.File))
+	require.NoError(t, err)
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	restored := filepath.Join(t.TempDir(), "shop.db")
+	out, err := os.Create(restored)
+	require.NoError(t, err)
+	_, err = io.Copy(out, zr)
+	require.NoError(t, err)
+	require.NoError(t, out.Close())
+	db, err := sql.Open("sqlite3", restored)
+	require.NoError(t, err)
+	defer db.Close()
+	var n int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT count(*) FROM t`).Scan(&n))
+	assert.Equal(t, 2, n)
+
+	// Past max age, even though fewer than keep
+	require.NoError(t, s.Run(ctx, start.Add(5*24*time.Hour)))
+	list, err = s.List("users.db")
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "users-20261022T000000Z.db.gz", list[0].File)
+
+	// Nothing else is left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{backups.ManifestFile, "shop-20261022T000000Z.db.gz", "users-20261022T000000Z.db.gz"}, names)
+}
+
+func TestScheduler_RunSelected(t *testing.T) {
+	m := nsqlite.NewManager(t.TempDir())
+	defer m.Close(context.Background())
+	ctx := context.Background()
+	require.NoError(t, m.Create("shop.db"))
+	require.NoError(t, m.Create("users.db"))
+
+	dir := t.TempDir()
+	s, err := backups.New(m, backups.Config{
+		Dir:       dir,
+		Schedule:  "@hourly",
+		Databases: []string{"missing.db", "shop.db"},
+	})
+	require.NoError(t, err)
+
+	// A database failing doesn't stop the others
+	now := time.Date(2026, time.October, 17, 10, 0, 0, 0, time.UTC)
+	err = s.Run(ctx, now)
+	assert.ErrorContains(t, err, "missing.db")
+	list, err := s.List()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "shop.db", list[0].Database)
+	assert.False(t, list[0].Compressed)
+	assert.Equal(t, now, list[0].TakenAt)
+
+	// Taking a backup doesn't count as using the database
+	info, err := m.Info(ctx, "shop.db")
+	require.NoError(t, err)
+	assert.True(t, info.LastUsed.IsZero())
+
+	copied, err := sql.Open("sqlite3", filepath.Join(dir, list[0].File))
+	require.NoError(t, err)
+	defer copied.Close()
+	var integrity string
+	require.NoError(t, copied.QueryRowContext(ctx, `PRAGMA integrity_check`).Scan(&integrity))
+	assert.Equal(t, "ok", integrity)
+
+	_, err = backups.New(m, backups.Config{Dir: dir, Schedule: "@sometimes"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,55 @@
+package backups_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alfredosa/netsqlite/internal/backups"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	// A Saturday
+	from := time.Date(2026, time.October, 17, 10, 42, 30, 0, time.UTC)
+	for _, tt := range []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 17, 10, 43, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 10, 17, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2026, 10, 18, 2, 30, 0, 0, time.UTC)},
+		{"*/15 9-17 * * 1-5", time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{"45,50 10 * * *", time.Date(2026, 10, 17, 10, 45, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Either day will do when both are restricted
+		{"0 0 1 * 1", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	} {
+		schedule, err := backups.ParseSchedule(tt.spec)
+		require.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.want, schedule.Next(from), tt.spec)
+	}
+}
+
+func TestParseSchedule_Errors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"@yearly",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		_, err := backups.ParseSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+}
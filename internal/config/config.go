@@ -0,0 +1,449 @@
+// Package config loads the server configuration from a YAML file, with
+// environment variable overrides.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alfredosa/netsqlite/internal/auth"
+	"github.com/alfredosa/netsqlite/internal/backups"
+	proto "github.com/alfredosa/netsqlite/internal/grpc"
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable pointing at the config file when
+// it isn't given on the command line.
+const FileEnv = "NETSQLITE_CONFIG"
+
+// Config is the layout of the config file:
+//
+//	listen: [":3541"]
+//	data_dir: data
+//	auto_create: true
+//	drop_legacy_test_wal: false  # drop the _test_wal table of earlier versions
+//	tokens_file: tokens.yaml
+//	shutdown_timeout: 15s    # for in-flight requests to finish
+//	drain_timeout: 10s       # then for databases to checkpoint and close
+//	tls:
+//	  cert: server.pem
+//	  key: server-key.pem
+//	  client_ca: ca.pem
+//	  require_client_cert: false
+//	database:                # every database
+//	  pool_size: 5           # read-only connections, writes share one
+//	  busy_timeout: 5s
+//	  journal_mode: wal
+//	  pragmas:               # run on every new connection
+//	    foreign_keys: "on"
+//	    synchronous: normal
+//	databases:               # overrides by database name
+//	  analytics.db:
+//	    pool_size: 10
+//	    pragmas:
+//	      cache_size: "-64000"
+//	limits:
+//	  max_message_bytes: 4194304
+//	  max_concurrent_streams: 100
+//	  tx_idle_timeout: 30s
+//	  write_tx_idle_timeout: 10s # for transactions and cursors holding the writer
+//	  cursor_idle_timeout: 2m
+//	  session_idle_timeout: 10m
+//	  max_open_databases: 1000  # unused ones are closed to open more
+//	  database_idle_timeout: 5m # close databases unused for that long
+//	backups:                 # off unless dir is set
+//	  dir: backups           # not the data directory
+//	  schedule: "@daily"     # cron syntax, such as "30 2 * * *", in local time
+//	  databases: [shop.db]   # every database if empty
+//	  keep: 7                # most recent backups kept per database, 0 keeps all
+//	  max_age: 720h          # delete older ones, never if 0
+//	  compress: true         # gzip
+//	logging:
+//	  level: info            # debug, info, warn or error
+//	  format: text           # text or json
+type Config struct {
+	Listen            []string            `yaml:"listen"`
+	DataDir           string              `yaml:"data_dir"`
+	AutoCreate        bool                `yaml:"auto_create"`
+	DropLegacyTestWAL bool                `yaml:"drop_legacy_test_wal"`
+	TokensFile        string              `yaml:"tokens_file"`
+	ShutdownTimeout   time.Duration       `yaml:"shutdown_timeout"`
+	DrainTimeout      time.Duration       `yaml:"drain_timeout"`
+	TLS               TLS                 `yaml:"tls"`
+	Database          Database            `yaml:"database"`
+	Databases         map[string]Database `yaml:"databases"`
+	Limits            Limits              `yaml:"limits"`
+	Backups           Backups             `yaml:"backups"`
+	Logging           Logging             `yaml:"logging"`
+}
+
+type TLS struct {
+	Cert              string `yaml:"cert"`
+	Key               string `yaml:"key"`
+	ClientCA          string `yaml:"client_ca"`
+	RequireClientCert bool   `yaml:"require_client_cert"`
+}
+
+// Database are the connection and pragma settings of a database, zero
+// fields fall back to the database section, then the defaults.
+type Database struct {
+	PoolSize    int32         `yaml:"pool_size"` // read-only connections
+	BusyTimeout time.Duration `yaml:"busy_timeout"`
+	JournalMode string        `yaml:"journal_mode"`
+	// Pragmas are merged over the ones of the database section
+	Pragmas map[string]string `yaml:"pragmas"`
+}
+
+type Limits struct {
+	MaxMessageBytes      int           `yaml:"max_message_bytes"`
+	MaxConcurrentStreams uint32        `yaml:"max_concurrent_streams"`
+	TxIdleTimeout        time.Duration `yaml:"tx_idle_timeout"`
+	WriteTxIdleTimeout   time.Duration `yaml:"write_tx_idle_timeout"`
+	CursorIdleTimeout    time.Duration `yaml:"cursor_idle_timeout"`
+	SessionIdleTimeout   time.Duration `yaml:"session_idle_timeout"`
+	MaxOpenDatabases     int           `yaml:"max_open_databases"`
+	DatabaseIdleTimeout  time.Duration `yaml:"database_idle_timeout"`
+}
+
+type Backups struct {
+	Dir       string        `yaml:"dir"`
+	Schedule  string        `yaml:"schedule"`
+	Databases []string      `yaml:"databases"`
+	Keep      int           `yaml:"keep"`
+	MaxAge    time.Duration `yaml:"max_age"`
+	Compress  bool          `yaml:"compress"`
+}
+
+type Logging struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+// Default returns the configuration used for everything the file and the
+// environment leave out.
+func Default() *Config {
+	db := nsqlite.DefaultSettings()
+	return &Config{
+		Listen:          []string{":3541"},
+		DataDir:         "data",
+		AutoCreate:      true,
+		ShutdownTimeout: 15 * time.Second,
+		DrainTimeout:    10 * time.Second,
+		Database: Database{
+			PoolSize:    db.PoolSize,
+			BusyTimeout: db.BusyTimeout,
+			JournalMode: db.JournalMode,
+		},
+		Backups: Backups{Schedule: "@daily"},
+		Logging: Logging{Level: "info", Format: "text"},
+	}
+}
+
+// Load reads the config file at path over the defaults, then applies the
+// environment overrides. Without a path only the defaults and the
+// environment are used. Load doesn't validate the result, see Validate.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading config file: %w", err)
+		}
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+		}
+	}
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// envOverrides are the environment variables overriding the config file.
+var envOverrides = []struct {
+	name string
+	set  func(c *Config, value string) error
+}{
+	{"NETSQLITE_LISTEN", func(c *Config, v string) error { c.Listen = splitList(v); return nil }},
+	{"NETSQLITE_DATA_DIR", func(c *Config, v string) error { c.DataDir = v; return nil }},
+	{"NETSQLITE_AUTO_CREATE", func(c *Config, v string) error { return parseBool(v, &c.AutoCreate) }},
+	{"NETSQLITE_DROP_LEGACY_TEST_WAL", func(c *Config, v string) error { return parseBool(v, &c.DropLegacyTestWAL) }},
+	{auth.TokensFileEnv, func(c *Config, v string) error { c.TokensFile = v; return nil }},
+	{"NETSQLITE_SHUTDOWN_TIMEOUT", func(c *Config, v string) error { return parseDuration(v, &c.ShutdownTimeout) }},
+	{"NETSQLITE_DRAIN_TIMEOUT", func(c *Config, v string) error { return parseDuration(v, &c.DrainTimeout) }},
+	{"NETSQLITE_TLS_CERT", func(c *Config, v string) error { c.TLS.Cert = v; return nil }},
+	{"NETSQLITE_TLS_KEY", func(c *Config, v string) error { c.TLS.Key = v; return nil }},
+	{"NETSQLITE_TLS_CLIENT_CA", func(c *Config, v string) error { c.TLS.ClientCA = v; return nil }},
+	{"NETSQLITE_TLS_REQUIRE_CLIENT_CERT", func(c *Config, v string) error { return parseBool(v, &c.TLS.RequireClientCert) }},
+	{"NETSQLITE_POOL_SIZE", func(c *Config, v string) error {
+		n, err := strconv.ParseInt(v, 10, 32)
+		c.Database.PoolSize = int32(n)
+		return err
+	}},
+	{"NETSQLITE_BUSY_TIMEOUT", func(c *Config, v string) error { return parseDuration(v, &c.Database.BusyTimeout) }},
+	{"NETSQLITE_JOURNAL_MODE", func(c *Config, v string) error { c.Database.JournalMode = v; return nil }},
+	{"NETSQLITE_PRAGMAS", func(c *Config, v string) error { return parsePragmas(v, &c.Database.Pragmas) }},
+	{"NETSQLITE_MAX_MESSAGE_BYTES", func(c *Config, v string) error {
+		n, err := strconv.Atoi(v)
+		c.Limits.MaxMessageBytes = n
+		return err
+	}},
+	{"NETSQLITE_MAX_OPEN_DATABASES", func(c *Config, v string) error {
+		n, err := strconv.Atoi(v)
+		c.Limits.MaxOpenDatabases = n
+		return err
+	}},
+	{"NETSQLITE_DATABASE_IDLE_TIMEOUT", func(c *Config, v string) error { return parseDuration(v, &c.Limits.DatabaseIdleTimeout) }},
+	{"NETSQLITE_BACKUP_DIR", func(c *Config, v string) error { c.Backups.Dir = v; return nil }},
+	{"NETSQLITE_BACKUP_SCHEDULE", func(c *Config, v string) error { c.Backups.Schedule = v; return nil }},
+	{"NETSQLITE_BACKUP_DATABASES", func(c *Config, v string) error { c.Backups.Databases = splitList(v); return nil }},
+	{"NETSQLITE_BACKUP_KEEP", func(c *Config, v string) error {
+		n, err := strconv.Atoi(v)
+		c.Backups.Keep = n
+		return err
+	}},
+	{"NETSQLITE_BACKUP_MAX_AGE", func(c *Config, v string) error { return parseDuration(v, &c.Backups.MaxAge) }},
+	{"NETSQLITE_BACKUP_COMPRESS", func(c *Config, v string) error { return parseBool(v, &c.Backups.Compress) }},
+	{"NETSQLITE_LOG_LEVEL", func(c *Config, v string) error { c.Logging.Level = v; return nil }},
+	{"NETSQLITE_LOG_FORMAT", func(c *Config, v string) error { c.Logging.Format = v; return nil }},
+}
+
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	for _, env := range envOverrides {
+		value, ok := lookup(env.name)
+		if !ok {
+			continue
+		}
+		if err := env.set(c, value); err != nil {
+			return fmt.Errorf("invalid %s %q: %w", env.name, value, err)
+		}
+	}
+	return nil
+}
+
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parsePragmas reads name=value pairs separated by commas.
+func parsePragmas(v string, dst *map[string]string) error {
+	pragmas := make(map[string]string)
+	for _, item := range splitList(v) {
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return fmt.Errorf("%q is not name=value", item)
+		}
+		pragmas[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	*dst = pragmas
+	return nil
+}
+
+func parseBool(v string, dst *bool) (err error) {
+	*dst, err = strconv.ParseBool(v)
+	return err
+}
+
+func parseDuration(v string, dst *time.Duration) (err error) {
+	*dst, err = time.ParseDuration(v)
+	return err
+}
+
+// Validate reports every problem with the configuration at once, without
+// touching the files it points at.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if len(c.Listen) == 0 {
+		fail("listen: at least one address is needed")
+	}
+	for _, addr := range c.Listen {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			fail("listen: invalid address %q: %v", addr, err)
+		}
+	}
+	if c.DataDir == "" {
+		fail("data_dir: is empty")
+	}
+	if c.TokensFile == "" {
+		fail("tokens_file: is not set (or use $%s)", auth.TokensFileEnv)
+	}
+	if c.ShutdownTimeout < 0 {
+		fail("shutdown_timeout: %s must not be negative", c.ShutdownTimeout)
+	}
+	if c.DrainTimeout < 0 {
+		fail("drain_timeout: %s must not be negative", c.DrainTimeout)
+	}
+
+	if (c.TLS.Cert == "") != (c.TLS.Key == "") {
+		fail("tls: cert and key must be set together")
+	}
+	if c.TLS.ClientCA != "" && c.TLS.Cert == "" {
+		fail("tls.client_ca: needs cert and key")
+	}
+	if c.TLS.RequireClientCert && c.TLS.ClientCA == "" {
+		fail("tls.require_client_cert: needs client_ca")
+	}
+
+	if err := c.Database.settings().Validate(); err != nil {
+		fail("database: %v", err)
+	}
+	for name, db := range c.Databases {
+		if err := nsqlite.ValidateName(name); err != nil {
+			fail("databases[%q]: %s", name, status.Convert(err).Message())
+		}
+		if err := db.settings().Validate(); err != nil {
+			fail("databases[%q]: %v", name, err)
+		}
+	}
+
+	if c.Limits.MaxMessageBytes < 0 {
+		fail("limits.max_message_bytes: %d must not be negative", c.Limits.MaxMessageBytes)
+	}
+	if c.Limits.MaxOpenDatabases < 0 {
+		fail("limits.max_open_databases: %d must not be negative", c.Limits.MaxOpenDatabases)
+	}
+	for _, timeout := range []struct {
+		name string
+		d    time.Duration
+	}{
+		{"tx_idle_timeout", c.Limits.TxIdleTimeout},
+		{"write_tx_idle_timeout", c.Limits.WriteTxIdleTimeout},
+		{"cursor_idle_timeout", c.Limits.CursorIdleTimeout},
+		{"session_idle_timeout", c.Limits.SessionIdleTimeout},
+		{"database_idle_timeout", c.Limits.DatabaseIdleTimeout},
+	} {
+		if timeout.d < 0 {
+			fail("limits.%s: %s must not be negative", timeout.name, timeout.d)
+		}
+	}
+
+	if c.Backups.Dir != "" {
+		if err := c.Backups.config().Validate(); err != nil {
+			fail("backups: %v", err)
+		}
+		// Uncompressed backups would be taken for databases
+		if filepath.Clean(c.Backups.Dir) == filepath.Clean(c.DataDir) {
+			fail("backups.dir: must not be the data directory")
+		}
+	}
+
+	if _, err := c.Logging.level(); err != nil {
+		fail("logging.level: %v", err)
+	}
+	if c.Logging.Format != "text" && c.Logging.Format != "json" {
+		fail("logging.format: %q is neither text nor json", c.Logging.Format)
+	}
+	return errors.Join(errs...)
+}
+
+// Check validates c, then loads the token file and TLS certificates it
+// points at.
+func (c *Config) Check() (*auth.Store, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	tokens, err := auth.LoadStore(c.TokensFile)
+	if err != nil {
+		return nil, fmt.Errorf("tokens_file: %w", err)
+	}
+	if c.Server().TLS.Enabled() {
+		if _, err := c.Server().TLS.Credentials(); err != nil {
+			return nil, fmt.Errorf("tls: %w", err)
+		}
+	}
+	return tokens, nil
+}
+
+func (d Database) settings() nsqlite.Settings {
+	return nsqlite.Settings{
+		PoolSize:    d.PoolSize,
+		BusyTimeout: d.BusyTimeout,
+		JournalMode: d.JournalMode,
+		Pragmas:     d.Pragmas,
+	}
+}
+
+func (b Backups) config() backups.Config {
+	return backups.Config{
+		Dir:       b.Dir,
+		Schedule:  b.Schedule,
+		Databases: b.Databases,
+		Keep:      b.Keep,
+		MaxAge:    b.MaxAge,
+		Compress:  b.Compress,
+	}
+}
+
+// Server returns the configuration to start the server with.
+func (c *Config) Server() proto.Config {
+	databases := make(map[string]nsqlite.Settings, len(c.Databases))
+	for name, db := range c.Databases {
+		databases[name] = db.settings()
+	}
+	return proto.Config{
+		Addrs:             c.Listen,
+		DataDir:           c.DataDir,
+		AutoCreate:        c.AutoCreate,
+		DropLegacyTestWAL: c.DropLegacyTestWAL,
+		Database:          c.Database.settings(),
+		Databases:         databases,
+		ShutdownTimeout:   c.ShutdownTimeout,
+		DrainTimeout:      c.DrainTimeout,
+		Limits: proto.Limits{
+			MaxMessageBytes:      c.Limits.MaxMessageBytes,
+			MaxConcurrentStreams: c.Limits.MaxConcurrentStreams,
+			TxIdleTimeout:        c.Limits.TxIdleTimeout,
+			WriteTxIdleTimeout:   c.Limits.WriteTxIdleTimeout,
+			CursorIdleTimeout:    c.Limits.CursorIdleTimeout,
+			SessionIdleTimeout:   c.Limits.SessionIdleTimeout,
+			MaxOpenDatabases:     c.Limits.MaxOpenDatabases,
+			DatabaseIdleTimeout:  c.Limits.DatabaseIdleTimeout,
+		},
+		TLS: proto.TLSConfig{
+			CertFile:          c.TLS.Cert,
+			KeyFile:           c.TLS.Key,
+			ClientCAFile:      c.TLS.ClientCA,
+			RequireClientCert: c.TLS.RequireClientCert,
+		},
+		Backups: c.Backups.config(),
+	}
+}
+
+func (l Logging) level() (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(l.Level))
+	return level, err
+}
+
+// Logger returns the logger configured by l, writing to w.
+func (l Logging) Logger(w io.Writer) *slog.Logger {
+	level, _ := l.level()
+	opts := &slog.HandlerOptions{Level: level}
+	if l.Format == "json" {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
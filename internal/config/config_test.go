@@ -0,0 +1,163 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alfredosa/netsqlite/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "netsqlite.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+listen: ["127.0.0.1:3541", "[::1]:3541"]
+tokens_file: tokens.yaml
+shutdown_timeout: 30s
+drain_timeout: 5s
+database:
+  pool_size: 8
+  pragmas:
+    foreign_keys: on
+databases:
+  analytics.db:
+    journal_mode: delete
+    pragmas:
+      cache_size: -64000
+limits:
+  tx_idle_timeout: 1m
+  max_open_databases: 500
+backups:
+  dir: /var/backups/netsqlite
+  keep: 7
+logging:
+  format: json
+`)
+	t.Setenv("NETSQLITE_DATA_DIR", "/var/lib/netsqlite")
+	t.Setenv("NETSQLITE_BUSY_TIMEOUT", "2s")
+	t.Setenv("NETSQLITE_BACKUP_DATABASES", "shop.db, users.db")
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+
+	// From the file
+	assert.Equal(t, []string{"127.0.0.1:3541", "[::1]:3541"}, cfg.Listen)
+	assert.Equal(t, 30*time.Second, cfg.ShutdownTimeout)
+	assert.Equal(t, int32(8), cfg.Database.PoolSize)
+	assert.Equal(t, "json", cfg.Logging.Format)
+	// From the environment
+	assert.Equal(t, "/var/lib/netsqlite", cfg.DataDir)
+	assert.Equal(t, 2*time.Second, cfg.Database.BusyTimeout)
+	// Defaults
+	assert.True(t, cfg.AutoCreate)
+	assert.Equal(t, "WAL", cfg.Database.JournalMode)
+	assert.Equal(t, "info", cfg.Logging.Level)
+
+	server := cfg.Server()
+	assert.Equal(t, cfg.Listen, server.Addrs)
+	assert.Equal(t, time.Minute, server.Limits.TxIdleTimeout)
+	assert.Equal(t, 500, server.Limits.MaxOpenDatabases)
+	assert.Equal(t, 5*time.Second, server.DrainTimeout)
+	assert.Equal(t, "delete", server.Databases["analytics.db"].JournalMode)
+	assert.Equal(t, map[string]string{"foreign_keys": "on"}, server.Database.Pragmas)
+	assert.Equal(t, map[string]string{"cache_size": "-64000"}, server.Databases["analytics.db"].Pragmas)
+	assert.Equal(t, "/var/backups/netsqlite", server.Backups.Dir)
+	assert.Equal(t, "@daily", server.Backups.Schedule)
+	assert.Equal(t, []string{"shop.db", "users.db"}, server.Backups.Databases)
+	assert.Equal(t, 7, server.Backups.Keep)
+
+	t.Setenv("NETSQLITE_PRAGMAS", "foreign_keys=off, synchronous=normal")
+	cfg, err = config.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"foreign_keys": "off", "synchronous": "normal"}, cfg.Database.Pragmas)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	_, err := config.Load(writeConfig(t, "tokenz: tokens.yaml\n"))
+	assert.ErrorContains(t, err, "tokenz")
+
+	_, err = config.Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+
+	t.Setenv("NETSQLITE_AUTO_CREATE", "maybe")
+	_, err = config.Load("")
+	assert.ErrorContains(t, err, "NETSQLITE_AUTO_CREATE")
+}
+
+func TestValidate(t *testing.T) {
+	cfg, err := config.Load(writeConfig(t, `
+listen: ["nowhere"]
+drain_timeout: -1s
+tls:
+  cert: server.pem
+  require_client_cert: true
+database:
+  journal_mode: sideways
+  pragmas:
+    journal_mode: wal
+databases:
+  ../escape.db:
+    pool_size: -1
+limits:
+  max_open_databases: -1
+backups:
+  dir: data
+  schedule: "61 * * * *"
+  keep: -1
+logging:
+  level: chatty
+`))
+	require.NoError(t, err)
+
+	// Every problem is reported at once
+	err = cfg.Validate()
+	require.Error(t, err)
+	for _, want := range []string{
+		"listen:",
+		"tokens_file:",
+		"drain_timeout:",
+		"tls: cert and key",
+		"tls.require_client_cert:",
+		"database: unknown journal mode",
+		"pragma journal_mode can't be set",
+		`databases["../escape.db"]: invalid database name`,
+		`databases["../escape.db"]: pool size`,
+		"limits.max_open_databases:",
+		"backups: schedule",
+		"keep -1",
+		"backups.dir:",
+		"logging.level:",
+	} {
+		assert.ErrorContains(t, err, want)
+	}
+
+	ok := config.Default()
+	ok.TokensFile = "tokens.yaml"
+	assert.NoError(t, ok.Validate())
+}
+
+func TestCheck(t *testing.T) {
+	cfg := config.Default()
+	cfg.TokensFile = filepath.Join(t.TempDir(), "tokens.yaml")
+	_, err := cfg.Check()
+	assert.ErrorContains(t, err, "tokens_file:")
+
+	require.NoError(t, os.WriteFile(cfg.TokensFile, []byte("tokens:\n  - name: ci\n    client_certs: [\"CN=ci\"]\n"), 0o600))
+	tokens, err := cfg.Check()
+	require.NoError(t, err)
+	assert.Equal(t, 1, tokens.Len())
+
+	cfg.TLS.Cert, cfg.TLS.Key = "missing.pem", "missing-key.pem"
+	_, err = cfg.Check()
+	assert.ErrorContains(t, err, "tls:")
+}
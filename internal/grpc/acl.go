@@ -0,0 +1,61 @@
+package proto
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alfredosa/netsqlite/internal/auth"
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// requireWrite rejects requests from principals that may only read.
+func requireWrite(ctx context.Context) error {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "request is not authenticated")
+	}
+	if !p.CanWrite() {
+		return status.Errorf(codes.PermissionDenied, "token %q is read-only", p.Name)
+	}
+	return nil
+}
+
+// requireAdmin rejects requests from principals that aren't admins.
+func requireAdmin(ctx context.Context) error {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "request is not authenticated")
+	}
+	if !p.IsAdmin() {
+		return status.Errorf(codes.PermissionDenied, "token %q is not an admin", p.Name)
+	}
+	return nil
+}
+
+// adminMethodPrefix is shared by every AdminService method.
+var adminMethodPrefix = "/" + pb.AdminService_ServiceDesc.ServiceName + "/"
+
+// serverWideMethods don't act on a single database, so they go without the
+// database header.
+var serverWideMethods = map[string]bool{
+	pb.AdminService_ListDatabases_FullMethodName: true,
+	pb.AdminService_ListBackups_FullMethodName:   true,
+}
+
+// authorizeMethod rejects calls to methods the principal's role can't use.
+func authorizeMethod(ctx context.Context, fullMethod string) error {
+	if strings.HasPrefix(fullMethod, adminMethodPrefix) {
+		return requireAdmin(ctx)
+	}
+	return nil
+}
+
+// readOnly reports whether statements of this request must not write.
+// Requests without a principal are treated as read-only.
+func readOnly(ctx context.Context) bool {
+	p, ok := auth.FromContext(ctx)
+	return !ok || !p.CanWrite()
+}
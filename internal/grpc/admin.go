@@ -0,0 +1,142 @@
+package proto
+
+import (
+	"context"
+
+	"github.com/alfredosa/netsqlite/internal/auth"
+	"github.com/alfredosa/netsqlite/internal/backups"
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// adminServer implements the AdminService, the interceptor only lets admin
+// tokens through.
+type adminServer struct {
+	pb.UnimplementedAdminServiceServer
+
+	dbManager *nsqlite.DBManager
+	backups   *backups.Scheduler // nil if scheduled backups are off
+}
+
+func newAdminServer(dbManager *nsqlite.DBManager, scheduler *backups.Scheduler) *adminServer {
+	return &adminServer{dbManager: dbManager, backups: scheduler}
+}
+
+// GetPragmas reads the pragmas off a read-only connection, they are the same
+// on every connection of the database since they are applied when it's opened.
+func (s *adminServer) GetPragmas(ctx context.Context, req *pb.GetPragmasRequest) (*pb.GetPragmasResponse, error) {
+	dbName := req.GetDatabaseName()
+	lease, err := s.dbManager.Acquire(ctx, dbName, nsqlite.Read)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+
+	pragmas, err := nsqlite.ReadPragmas(ctx, lease.Conn(), s.dbManager.SettingsFor(dbName))
+	if err != nil {
+		return nil, sqlError(lease.Check(err), "failed to read pragmas")
+	}
+
+	resp := &pb.GetPragmasResponse{Pragmas: make([]*pb.Pragma, 0, len(pragmas))}
+	for _, p := range pragmas {
+		resp.Pragmas = append(resp.Pragmas, &pb.Pragma{Name: p.Name, Value: p.Value, Configured: p.Configured})
+	}
+	return resp, nil
+}
+
+// ListDatabases only lists the databases the token has access to.
+func (s *adminServer) ListDatabases(ctx context.Context, req *pb.ListDatabasesRequest) (*pb.ListDatabasesResponse, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "request is not authenticated")
+	}
+	infos, err := s.dbManager.List()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListDatabasesResponse{}
+	for _, info := range infos {
+		if p.CanAccess(info.Name) {
+			resp.Databases = append(resp.Databases, databaseInfo(info))
+		}
+	}
+	return resp, nil
+}
+
+func (s *adminServer) GetDatabaseInfo(ctx context.Context, req *pb.GetDatabaseInfoRequest) (*pb.GetDatabaseInfoResponse, error) {
+	info, err := s.dbManager.Info(ctx, req.GetDatabaseName())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetDatabaseInfoResponse{Database: databaseInfo(info)}, nil
+}
+
+func (s *adminServer) CreateDatabase(ctx context.Context, req *pb.CreateDatabaseRequest) (*pb.CreateDatabaseResponse, error) {
+	if err := s.dbManager.Create(req.GetDatabaseName()); err != nil {
+		return nil, err
+	}
+	info, err := s.dbManager.Info(ctx, req.GetDatabaseName())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CreateDatabaseResponse{Database: databaseInfo(info)}, nil
+}
+
+func (s *adminServer) DropDatabase(ctx context.Context, req *pb.DropDatabaseRequest) (*pb.DropDatabaseResponse, error) {
+	if err := s.dbManager.Drop(ctx, req.GetDatabaseName()); err != nil {
+		return nil, err
+	}
+	return &pb.DropDatabaseResponse{}, nil
+}
+
+// ListBackups, like ListDatabases, only lists the backups of databases the
+// token has access to.
+func (s *adminServer) ListBackups(ctx context.Context, req *pb.ListBackupsRequest) (*pb.ListBackupsResponse, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "request is not authenticated")
+	}
+	if s.backups == nil {
+		return nil, status.Error(codes.FailedPrecondition, "scheduled backups are not configured")
+	}
+	list, err := s.backups.List(req.GetDatabases()...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list backups: %v", err)
+	}
+
+	resp := &pb.ListBackupsResponse{}
+	for _, backup := range list {
+		if !p.CanAccess(backup.Database) {
+			continue
+		}
+		resp.Backups = append(resp.Backups, &pb.BackupInfo{
+			DatabaseName: backup.Database,
+			FileName:     backup.File,
+			TakenAt:      timestamppb.New(backup.TakenAt),
+			SizeBytes:    backup.Size,
+			Sha256:       backup.SHA256,
+			Compressed:   backup.Compressed,
+		})
+	}
+	return resp, nil
+}
+
+func databaseInfo(info nsqlite.DatabaseInfo) *pb.DatabaseInfo {
+	db := &pb.DatabaseInfo{
+		Name:         info.Name,
+		SizeBytes:    info.Size,
+		WalSizeBytes: info.WALSize,
+		PageSize:     info.PageSize,
+		PageCount:    info.PageCount,
+		Open:         info.Open,
+	}
+	if !info.LastUsed.IsZero() {
+		db.LastAccess = timestamppb.New(info.LastUsed)
+	}
+	return db
+}
@@ -0,0 +1,72 @@
+package proto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"os"
+
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxBackupChunk is the size of the chunks Backup streams unless asked for
+// smaller ones, well under gRPC's default 4 MiB message limit.
+const maxBackupChunk = 1 << 20
+
+// Backup snapshots the database into a temporary file next to it, then
+// streams the file. The snapshot is done before the first chunk is sent, so
+// a slow client doesn't keep a read transaction open.
+func (s *adminServer) Backup(req *pb.BackupRequest, stream pb.AdminService_BackupServer) error {
+	ctx := stream.Context()
+	dbName := req.GetDatabaseName()
+
+	tmp, err := s.dbManager.CreateTemp(".backup-*")
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to create backup file: %v", err)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := s.dbManager.Backup(ctx, dbName, tmp.Name()); err != nil {
+		return err
+	}
+	f, err := os.Open(tmp.Name())
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to read backup: %v", err)
+	}
+	defer f.Close()
+
+	chunkBytes := int(req.GetChunkBytes())
+	if chunkBytes <= 0 || chunkBytes > maxBackupChunk {
+		chunkBytes = maxBackupChunk
+	}
+	hash := sha256.New()
+	var size int64
+	for {
+		chunk := make([]byte, chunkBytes)
+		n, err := io.ReadFull(f, chunk)
+		if n > 0 {
+			hash.Write(chunk[:n])
+			size += int64(n)
+			if err := stream.Send(&pb.BackupResponse{Payload: &pb.BackupResponse_Chunk{Chunk: chunk[:n]}}); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		} else if err != nil {
+			return status.Errorf(codes.Internal, "failed to read backup: %v", err)
+		}
+	}
+
+	log.Printf("Streamed backup of %s (%d bytes)", dbName, size)
+	return stream.Send(&pb.BackupResponse{Payload: &pb.BackupResponse_Summary{Summary: &pb.BackupSummary{
+		SizeBytes: size,
+		Sha256:    hex.EncodeToString(hash.Sum(nil)),
+	}}})
+}
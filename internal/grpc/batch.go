@@ -0,0 +1,132 @@
+package proto
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"log/slog"
+
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// batchSavepoint scopes a batch running inside a client transaction, so a
+// failing statement only undoes the batch and leaves the transaction open.
+const batchSavepoint = "netsqlite_batch"
+
+func (s *netsqliteServer) ExecBatch(ctx context.Context, req *pb.ExecBatchRequest) (*pb.ExecBatchResponse, error) {
+	if err := requireWrite(ctx); err != nil {
+		return nil, err
+	}
+	if req.ContinueOnError && req.TransactionId != "" {
+		return nil, status.Error(codes.InvalidArgument, "continue_on_error can't be used inside a transaction")
+	}
+
+	// Convert every argument upfront, a bad one fails the batch before anything runs
+	args := make([][]any, len(req.Statements))
+	for i, st := range req.Statements {
+		var err error
+		if args[i], err = argsFromProto(st.Args); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "statement %d: %v", i, status.Convert(err).Message())
+		}
+	}
+
+	if req.TransactionId != "" {
+		tx, err := s.txs.acquire(ctx, req.DatabaseName, req.TransactionId)
+		if err != nil {
+			return nil, err
+		}
+		defer s.txs.release(tx)
+
+		return execBatchAtomic(ctx, tx.conn, req.Statements, args,
+			"SAVEPOINT "+batchSavepoint,
+			"RELEASE "+batchSavepoint,
+			"ROLLBACK TO "+batchSavepoint+"; RELEASE "+batchSavepoint)
+	}
+
+	// Every statement of the batch runs on the writer
+	lease, err := s.dbManager.Acquire(ctx, req.DatabaseName, nsqlite.Write)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	conn := lease.Conn()
+
+	var resp *pb.ExecBatchResponse
+	if req.ContinueOnError {
+		resp, err = runBatch(ctx, conn, req.Statements, args, true)
+	} else {
+		// Take the write lock upfront, upgrading a read lock halfway through can deadlock
+		resp, err = execBatchAtomic(ctx, conn, req.Statements, args, "BEGIN IMMEDIATE", "COMMIT", "ROLLBACK")
+	}
+
+	// The connection may be left mid transaction, don't hand it back
+	if err != nil && !req.ContinueOnError {
+		lease.Discard()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("ExecBatch done for DB", "db", req.DatabaseName, "statements", len(req.Statements), "failed", resp.Failed)
+	return resp, nil
+}
+
+// execBatchAtomic runs the batch between begin and commit, the first failing
+// statement rolls the whole batch back.
+func execBatchAtomic(ctx context.Context, conn *sql.Conn, stmts []*pb.BatchStatement, args [][]any, begin, commit, rollback string) (*pb.ExecBatchResponse, error) {
+	if _, err := conn.ExecContext(ctx, begin); err != nil {
+		return nil, sqlError(err, "failed to begin batch")
+	}
+
+	resp, err := runBatch(ctx, conn, stmts, args, false)
+	if err != nil || resp.Failed {
+		// Roll back even if ctx is done
+		if _, rbErr := conn.ExecContext(context.Background(), rollback); rbErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to roll back batch: %v", rbErr)
+		}
+		return resp, err
+	}
+
+	if _, err := conn.ExecContext(ctx, commit); err != nil {
+		if _, rbErr := conn.ExecContext(context.Background(), rollback); rbErr != nil {
+			log.Printf("Rollback after failed batch commit failed: %v", rbErr)
+		}
+		return nil, sqlError(err, "failed to commit batch")
+	}
+	return resp, nil
+}
+
+// runBatch runs the statements in order, stopping at the first failure
+// unless continueOnError is set.
+func runBatch(ctx context.Context, db execQueryer, stmts []*pb.BatchStatement, args [][]any, continueOnError bool) (*pb.ExecBatchResponse, error) {
+	resp := &pb.ExecBatchResponse{Results: make([]*pb.StatementResult, 0, len(stmts))}
+	for i, st := range stmts {
+		result, err := db.ExecContext(ctx, st.Sql, args[i]...)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, status.FromContextError(ctxErr).Err()
+		}
+		if err != nil {
+			resp.Results = append(resp.Results, &pb.StatementResult{Error: err.Error()})
+			if !resp.Failed {
+				resp.Failed = true
+				resp.FailedIndex = int32(i)
+			}
+			if !continueOnError {
+				break
+			}
+			continue
+		}
+
+		rowsAffected, _ := result.RowsAffected()
+		lastInsertId, _ := result.LastInsertId()
+		resp.Results = append(resp.Results, &pb.StatementResult{
+			RowsAffected: rowsAffected,
+			LastInsertId: lastInsertId,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,187 @@
+package proto
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"strings"
+
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// bulkSavepoint scopes a bulk insert running inside a client transaction.
+const bulkSavepoint = "netsqlite_bulk"
+
+func (s *netsqliteServer) BulkInsert(stream pb.NetsqliteService_BulkInsertServer) error {
+	ctx := stream.Context()
+	if err := requireWrite(ctx); err != nil {
+		return err
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	header := first.GetHeader()
+	if header == nil {
+		return status.Error(codes.InvalidArgument, "first BulkInsert message must be the header")
+	}
+	// The header isn't a request message of its own, so the interceptor can't check it
+	if err := checkDatabase(ctx, header); err != nil {
+		return err
+	}
+	insertSQL, err := insertStatement(header.Table, header.Columns)
+	if err != nil {
+		return err
+	}
+
+	if header.TransactionId != "" {
+		tx, err := s.txs.acquire(ctx, header.DatabaseName, header.TransactionId)
+		if err != nil {
+			return err
+		}
+		defer s.txs.release(tx)
+
+		n, _, err := bulkInsert(ctx, stream, tx.conn, insertSQL, len(header.Columns),
+			"SAVEPOINT "+bulkSavepoint,
+			"RELEASE "+bulkSavepoint,
+			"ROLLBACK TO "+bulkSavepoint+"; RELEASE "+bulkSavepoint)
+		if err != nil {
+			return err
+		}
+		return stream.SendAndClose(&pb.BulkInsertResponse{RowsInserted: n})
+	}
+
+	lease, err := s.dbManager.Acquire(ctx, header.DatabaseName, nsqlite.Write)
+	if err != nil {
+		return err
+	}
+	defer lease.Release()
+
+	n, clean, err := bulkInsert(ctx, stream, lease.Conn(), insertSQL, len(header.Columns), "BEGIN IMMEDIATE", "COMMIT", "ROLLBACK")
+	// Don't hand back a connection that may be left mid transaction
+	if !clean {
+		lease.Discard()
+	}
+	if err != nil {
+		return err
+	}
+
+	slog.Info("BulkInsert done for DB", "db", header.DatabaseName, "table", header.Table, "rows", n)
+	return stream.SendAndClose(&pb.BulkInsertResponse{RowsInserted: n})
+}
+
+// bulkInsert runs the prepared INSERT for every streamed row between begin
+// and commit. Anything going wrong rolls back every row of the stream.
+// clean reports whether conn was left outside of the bulk insert transaction.
+func bulkInsert(ctx context.Context, stream pb.NetsqliteService_BulkInsertServer, conn *sql.Conn, insertSQL string, numColumns int, begin, commit, rollback string) (n int64, clean bool, err error) {
+	if _, err := conn.ExecContext(ctx, begin); err != nil {
+		return 0, true, sqlError(err, "failed to begin bulk insert")
+	}
+
+	// Roll back even if ctx is done
+	abort := func() bool {
+		if _, rbErr := conn.ExecContext(context.Background(), rollback); rbErr != nil {
+			log.Printf("Rollback of bulk insert failed: %v", rbErr)
+			return false
+		}
+		return true
+	}
+
+	n, err = insertRows(ctx, stream, conn, insertSQL, numColumns)
+	if err != nil {
+		return 0, abort(), err
+	}
+	if _, err := conn.ExecContext(ctx, commit); err != nil {
+		return 0, abort(), sqlError(err, "failed to commit bulk insert")
+	}
+	return n, true, nil
+}
+
+func insertRows(ctx context.Context, stream pb.NetsqliteService_BulkInsertServer, conn *sql.Conn, insertSQL string, numColumns int) (int64, error) {
+	stmt, err := conn.PrepareContext(ctx, insertSQL)
+	if err != nil {
+		return 0, sqlError(err, "failed to prepare insert")
+	}
+	defer stmt.Close()
+
+	var n int64
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return n, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		batch := msg.GetRows()
+		if batch == nil {
+			return 0, status.Error(codes.InvalidArgument, "expected rows after the BulkInsert header")
+		}
+		for _, row := range batch.Rows {
+			if len(row.Values) != numColumns {
+				return 0, status.Errorf(codes.InvalidArgument, "row %d has %d values, expected %d", n, len(row.Values), numColumns)
+			}
+			args, err := argsFromProto(row.Values)
+			if err != nil {
+				return 0, status.Errorf(codes.InvalidArgument, "row %d: %v", n, status.Convert(err).Message())
+			}
+			if _, err := stmt.ExecContext(ctx, args...); err != nil {
+				return 0, sqlError(err, fmt.Sprintf("insert of row %d failed", n))
+			}
+			n++
+		}
+	}
+}
+
+// insertStatement builds the INSERT for a bulk insert, quoting every
+// identifier so they can't be used to inject SQL.
+func insertStatement(table string, columns []string) (string, error) {
+	if table == "" {
+		return "", status.Error(codes.InvalidArgument, "table name is required")
+	}
+	if len(columns) == 0 {
+		return "", status.Error(codes.InvalidArgument, "at least one column is required")
+	}
+
+	// The table may be qualified with its schema, main.items
+	var b strings.Builder
+	b.WriteString("INSERT INTO ")
+	for i, part := range strings.Split(table, ".") {
+		if part == "" {
+			return "", status.Errorf(codes.InvalidArgument, "invalid table name %q", table)
+		}
+		if i > 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(quoteIdent(part))
+	}
+	b.WriteString(" (")
+	for i, col := range columns {
+		if col == "" {
+			return "", status.Errorf(codes.InvalidArgument, "column %d has no name", i)
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(quoteIdent(col))
+	}
+	b.WriteString(") VALUES (")
+	b.WriteString(strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+	b.WriteString(")")
+	return b.String(), nil
+}
+
+// quoteIdent quotes a SQLite identifier, doubling any embedded quotes.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
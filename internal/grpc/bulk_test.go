@@ -0,0 +1,27 @@
+package proto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertStatement(t *testing.T) {
+	got, err := insertStatement(`items"; DROP TABLE items; --`, []string{"id", `we"ird`})
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "items""; DROP TABLE items; --" ("id", "we""ird") VALUES (?, ?)`, got)
+
+	got, err = insertStatement("main.items", []string{"id"})
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "main"."items" ("id") VALUES (?)`, got)
+	_, err = insertStatement("main.", []string{"id"})
+	assert.Error(t, err)
+
+	_, err = insertStatement("", []string{"id"})
+	assert.Error(t, err)
+	_, err = insertStatement("items", nil)
+	assert.Error(t, err)
+	_, err = insertStatement("items", []string{"id", ""})
+	assert.Error(t, err)
+}
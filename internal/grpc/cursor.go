@@ -0,0 +1,235 @@
+package proto
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// defaultCursorIdleTimeout is how long a cursor can go without a fetch before
+// it is closed, releasing its connection and SQLite read transaction.
+const defaultCursorIdleTimeout = 2 * time.Minute
+
+// cursor is an open query whose rows are pulled by the client, batch by batch.
+type cursor struct {
+	id     string
+	dbName string
+	writer bool // whether it holds the writer connection, for a writing query
+
+	mu       sync.Mutex
+	lease    *nsqlite.Lease // nil once the rows are exhausted or closed
+	rows     *sql.Rows
+	reader   *rowReader
+	cancel   context.CancelFunc
+	seq      int64 // batches handed out so far
+	last     *pb.FetchCursorResponse
+	err      error // of the read that failed, the cursor can't go on after it
+	lastUsed time.Time
+}
+
+// fetch reads up to maxRows rows. Asking for the previous sequence again
+// replays the last batch instead of reading further. Once a read fails every
+// later fetch fails too, rather than report the rows done.
+func (c *cursor) fetch(maxRows int, seq int64) (*pb.FetchCursorResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastUsed = time.Now()
+
+	if seq == c.seq-1 && c.last != nil {
+		return c.last, nil
+	}
+	if c.err != nil {
+		return nil, status.Errorf(codes.Aborted, "cursor %s failed: %s", c.id, status.Convert(c.err).Message())
+	}
+	if seq != c.seq {
+		return nil, status.Errorf(codes.FailedPrecondition, "cursor %s is at sequence %d, got %d", c.id, c.seq, seq)
+	}
+
+	resp := &pb.FetchCursorResponse{Batch: &pb.RowBatch{}}
+	if c.lease == nil {
+		resp.Done = true
+	}
+
+	batchBytes := 0
+	for !resp.Done && len(resp.Batch.Rows) < maxRows && batchBytes < maxBatchBytes {
+		row, err := c.reader.next()
+		if err != nil {
+			c.err = err
+			c.release()
+			return nil, err
+		}
+		if row == nil {
+			resp.Done = true
+			break
+		}
+		resp.Batch.Rows = append(resp.Batch.Rows, row)
+		batchBytes += proto.Size(row)
+	}
+
+	// Don't hold on to the connection any longer than needed
+	if resp.Done {
+		c.release()
+	}
+
+	c.seq++
+	c.last = resp
+	return resp, nil
+}
+
+// release closes the rows and hands the connection back, must hold mu.
+func (c *cursor) release() {
+	if c.lease == nil {
+		return
+	}
+	if err := c.rows.Close(); err != nil {
+		log.Printf("Failed to close rows of cursor %s: %v", c.id, err)
+	}
+	c.cancel()
+	c.lease.Release()
+	c.lease = nil
+	c.rows = nil
+	c.reader = nil
+}
+
+// cursorRegistry keeps track of every open cursor by its server issued ID.
+type cursorRegistry struct {
+	mu                sync.Mutex
+	cursors           map[string]*cursor
+	idleTimeout       time.Duration
+	writerIdleTimeout time.Duration // of the cursors holding the writer, if shorter
+}
+
+func newCursorRegistry(idleTimeout, writerIdleTimeout time.Duration) *cursorRegistry {
+	return &cursorRegistry{
+		cursors:           make(map[string]*cursor),
+		idleTimeout:       idleTimeout,
+		writerIdleTimeout: writerIdleTimeout,
+	}
+}
+
+// open runs the query on the leased connection and keeps both around until
+// the cursor is done, the cursor owns the lease even if open fails.
+func (r *cursorRegistry) open(ctx context.Context, lease *nsqlite.Lease, dbName, query string, args []any) (*cursor, *pb.Columns, error) {
+	id, err := newID()
+	if err != nil {
+		lease.Release()
+		return nil, nil, status.Errorf(codes.Internal, "failed to generate cursor id: %v", err)
+	}
+
+	// The rows outlive this RPC, so they can't use its context
+	queryCtx, cancel := context.WithCancel(context.Background())
+	rows, err := lease.Conn().QueryContext(queryCtx, query, args...)
+	if err != nil {
+		cancel()
+		err = lease.Check(err)
+		lease.Release()
+		return nil, nil, sqlError(err, "SQL query failed")
+	}
+
+	columns, err := columnsMessage(rows)
+	if err != nil {
+		rows.Close()
+		cancel()
+		lease.Release()
+		return nil, nil, err
+	}
+
+	c := &cursor{
+		id:       id,
+		dbName:   dbName,
+		writer:   lease.Access() == nsqlite.Write,
+		lease:    lease,
+		rows:     rows,
+		reader:   newRowReader(rows, len(columns.Names)),
+		cancel:   cancel,
+		lastUsed: time.Now(),
+	}
+
+	r.mu.Lock()
+	r.cursors[id] = c
+	r.mu.Unlock()
+
+	return c, columns, nil
+}
+
+func (r *cursorRegistry) get(dbName, id string) (*cursor, error) {
+	r.mu.Lock()
+	c, ok := r.cursors[id]
+	r.mu.Unlock()
+
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "cursor %s not found (closed or expired)", id)
+	}
+	if c.dbName != dbName {
+		return nil, status.Errorf(codes.InvalidArgument, "cursor %s does not belong to database %s", id, dbName)
+	}
+	return c, nil
+}
+
+func (r *cursorRegistry) close(dbName, id string) error {
+	c, err := r.get(dbName, id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	delete(r.cursors, id)
+	r.mu.Unlock()
+
+	c.mu.Lock()
+	c.release()
+	c.mu.Unlock()
+	return nil
+}
+
+// reap closes every cursor idle for longer than its idle timeout.
+func (r *cursorRegistry) reap(now time.Time) {
+	r.mu.Lock()
+	var expired []*cursor
+	for id, c := range r.cursors {
+		// Skip cursors in the middle of a fetch
+		if !c.mu.TryLock() {
+			continue
+		}
+		timeout := r.idleTimeout
+		if c.writer && c.lease != nil {
+			timeout = min(timeout, r.writerIdleTimeout)
+		}
+		if now.Sub(c.lastUsed) > timeout {
+			delete(r.cursors, id)
+			expired = append(expired, c)
+		}
+		c.mu.Unlock()
+	}
+	r.mu.Unlock()
+
+	for _, c := range expired {
+		log.Printf("Closing idle cursor %s on DB '%s'", c.id, c.dbName)
+		c.mu.Lock()
+		c.release()
+		c.mu.Unlock()
+	}
+}
+
+// closeAll closes every cursor, used on shutdown.
+func (r *cursorRegistry) closeAll() {
+	r.mu.Lock()
+	cursors := r.cursors
+	r.cursors = make(map[string]*cursor)
+	r.mu.Unlock()
+
+	for _, c := range cursors {
+		c.mu.Lock()
+		c.release()
+		c.mu.Unlock()
+	}
+}
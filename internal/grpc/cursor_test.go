@@ -0,0 +1,99 @@
+package proto
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCursorRegistry_ReplaysAndReaps(t *testing.T) {
+	m := nsqlite.NewManager(t.TempDir(), nsqlite.WithSettings(nsqlite.Settings{PoolSize: 1}, nil))
+	db, err := m.Open("cursor.db")
+	require.NoError(t, err)
+	defer db.Close(context.Background())
+
+	reg := newCursorRegistry(time.Minute, time.Second)
+	defer reg.closeAll()
+
+	ctx := context.Background()
+	query := "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < 5) SELECT x FROM c"
+	lease, err := m.Acquire(ctx, "cursor.db", nsqlite.Read)
+	require.NoError(t, err)
+	cur, cols, err := reg.open(ctx, lease, "db", query, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"x"}, cols.Names)
+
+	first, err := cur.fetch(2, 0)
+	require.NoError(t, err)
+	require.Len(t, first.Batch.Rows, 2)
+
+	// A retried fetch gets the same batch back
+	again, err := cur.fetch(2, 0)
+	require.NoError(t, err)
+	assert.Same(t, first, again)
+
+	_, err = cur.fetch(2, 5)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	rest, err := cur.fetch(10, 1)
+	require.NoError(t, err)
+	assert.Len(t, rest.Batch.Rows, 3)
+	assert.True(t, rest.Done)
+	assert.Equal(t, int32(0), db.Stats().ReadersBusy, "exhausted cursor should release its connection")
+
+	// An idle cursor holding a connection is reaped
+	lease, err = m.Acquire(ctx, "cursor.db", nsqlite.Read)
+	require.NoError(t, err)
+	_, _, err = reg.open(ctx, lease, "db", query, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), db.Stats().ReadersBusy)
+
+	// One holding the writer goes sooner
+	lease, err = m.Acquire(ctx, "cursor.db", nsqlite.Write)
+	require.NoError(t, err)
+	_, _, err = reg.open(ctx, lease, "db", query, nil)
+	require.NoError(t, err)
+	reg.reap(time.Now().Add(2 * time.Second))
+	assert.False(t, db.Stats().WriterBusy)
+	assert.Equal(t, int32(1), db.Stats().ReadersBusy)
+
+	reg.reap(time.Now().Add(2 * time.Minute))
+	assert.Equal(t, int32(0), db.Stats().ReadersBusy)
+	_, err = reg.get("db", cur.id)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCursorRegistry_FailedFetch(t *testing.T) {
+	m := nsqlite.NewManager(t.TempDir())
+	defer m.Close(context.Background())
+	reg := newCursorRegistry(time.Minute, time.Second)
+	defer reg.closeAll()
+
+	// The third row overflows
+	ctx := context.Background()
+	query := "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < 5) " +
+		"SELECT CASE WHEN x < 3 THEN x ELSE abs(-9223372036854775807 - 1) END FROM c"
+	lease, err := m.Acquire(ctx, "cursor.db", nsqlite.Read)
+	require.NoError(t, err)
+	cur, _, err := reg.open(ctx, lease, "cursor.db", query, nil)
+	require.NoError(t, err)
+
+	first, err := cur.fetch(2, 0)
+	require.NoError(t, err)
+	require.Len(t, first.Batch.Rows, 2)
+	_, err = cur.fetch(2, 1)
+	require.Error(t, err)
+
+	// A client retrying the fetch gets an error too, never the end of the rows
+	_, err = cur.fetch(2, 1)
+	assert.Equal(t, codes.Aborted, status.Code(err))
+	replay, err := cur.fetch(2, 0)
+	require.NoError(t, err)
+	assert.Same(t, first, replay)
+}
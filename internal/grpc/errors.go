@@ -0,0 +1,117 @@
+package proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/mattn/go-sqlite3"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// sqliteErrorDomain is the ErrorInfo domain of errors coming from SQLite.
+// The driver looks for it to hand out typed errors.
+const sqliteErrorDomain = "sqlite.netsqlite"
+
+// sqliteCodeNames names the primary SQLite result codes, used as the ErrorInfo reason.
+var sqliteCodeNames = map[sqlite3.ErrNo]string{
+	sqlite3.ErrError:      "SQLITE_ERROR",
+	sqlite3.ErrInternal:   "SQLITE_INTERNAL",
+	sqlite3.ErrPerm:       "SQLITE_PERM",
+	sqlite3.ErrAbort:      "SQLITE_ABORT",
+	sqlite3.ErrBusy:       "SQLITE_BUSY",
+	sqlite3.ErrLocked:     "SQLITE_LOCKED",
+	sqlite3.ErrNomem:      "SQLITE_NOMEM",
+	sqlite3.ErrReadonly:   "SQLITE_READONLY",
+	sqlite3.ErrInterrupt:  "SQLITE_INTERRUPT",
+	sqlite3.ErrIoErr:      "SQLITE_IOERR",
+	sqlite3.ErrCorrupt:    "SQLITE_CORRUPT",
+	sqlite3.ErrNotFound:   "SQLITE_NOTFOUND",
+	sqlite3.ErrFull:       "SQLITE_FULL",
+	sqlite3.ErrCantOpen:   "SQLITE_CANTOPEN",
+	sqlite3.ErrProtocol:   "SQLITE_PROTOCOL",
+	sqlite3.ErrEmpty:      "SQLITE_EMPTY",
+	sqlite3.ErrSchema:     "SQLITE_SCHEMA",
+	sqlite3.ErrTooBig:     "SQLITE_TOOBIG",
+	sqlite3.ErrConstraint: "SQLITE_CONSTRAINT",
+	sqlite3.ErrMismatch:   "SQLITE_MISMATCH",
+	sqlite3.ErrMisuse:     "SQLITE_MISUSE",
+	sqlite3.ErrNoLFS:      "SQLITE_NOLFS",
+	sqlite3.ErrAuth:       "SQLITE_AUTH",
+	sqlite3.ErrFormat:     "SQLITE_FORMAT",
+	sqlite3.ErrRange:      "SQLITE_RANGE",
+	sqlite3.ErrNotADB:     "SQLITE_NOTADB",
+	sqlite3.ErrNotice:     "SQLITE_NOTICE",
+	sqlite3.ErrWarning:    "SQLITE_WARNING",
+}
+
+// sqlError turns an error from database/sql into a gRPC status error, msg
+// says what was being done. SQLite errors get the closest gRPC code and an
+// ErrorInfo detail with their primary and extended result codes, anything
+// else is Internal.
+func sqlError(err error, msg string) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+
+	st := status.New(sqliteGRPCCode(sqliteErr), fmt.Sprintf("%s: %v", msg, err))
+	reason, ok := sqliteCodeNames[sqliteErr.Code]
+	if !ok {
+		reason = "SQLITE_UNKNOWN"
+	}
+	withInfo, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: sqliteErrorDomain,
+		Metadata: map[string]string{
+			"code":          strconv.Itoa(int(sqliteErr.Code)),
+			"extended_code": strconv.Itoa(int(sqliteErr.ExtendedCode)),
+		},
+	})
+	if detailErr != nil {
+		return st.Err()
+	}
+	return withInfo.Err()
+}
+
+// sqliteGRPCCode picks the gRPC code closest to a SQLite error.
+func sqliteGRPCCode(err sqlite3.Error) codes.Code {
+	switch err.Code {
+	case sqlite3.ErrConstraint:
+		switch err.ExtendedCode {
+		case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey, sqlite3.ErrConstraintRowID:
+			return codes.AlreadyExists
+		}
+		return codes.FailedPrecondition
+	case sqlite3.ErrBusy:
+		// Another connection holds the lock, trying again later may work
+		return codes.Unavailable
+	case sqlite3.ErrLocked:
+		return codes.Aborted
+	case sqlite3.ErrError, sqlite3.ErrRange, sqlite3.ErrMismatch, sqlite3.ErrTooBig:
+		// Syntax errors, unknown tables or columns, bad parameters
+		return codes.InvalidArgument
+	case sqlite3.ErrReadonly, sqlite3.ErrPerm, sqlite3.ErrAuth:
+		return codes.PermissionDenied
+	case sqlite3.ErrFull:
+		return codes.ResourceExhausted
+	case sqlite3.ErrInterrupt:
+		return codes.Canceled
+	case sqlite3.ErrCorrupt, sqlite3.ErrNotADB:
+		return codes.DataLoss
+	case sqlite3.ErrCantOpen, sqlite3.ErrIoErr:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
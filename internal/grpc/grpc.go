@@ -6,46 +6,198 @@ import (
 	"net"
 	"time"
 
+	"github.com/alfredosa/netsqlite/internal/auth"
+	"github.com/alfredosa/netsqlite/internal/backups"
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
 	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
-func Start(ctx context.Context, validTokens map[string]bool, addr, dir string) {
-	lis, err := net.Listen("tcp", addr)
-	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
+// defaultShutdownTimeout is how long in-flight requests get to finish on
+// shutdown before the server is stopped forcefully.
+const defaultShutdownTimeout = 15 * time.Second
+
+// defaultDrainTimeout is how long the databases get on shutdown, once the
+// server stopped serving, to get their connections back and checkpoint.
+const defaultDrainTimeout = 10 * time.Second
+
+// Config holds everything Start needs to serve netsqlite.
+type Config struct {
+	Addrs   []string // Addresses and ports to listen on
+	DataDir string   // Directory holding every database
+
+	// AutoCreate creates databases on first use, otherwise clients get
+	// NotFound for databases that don't exist yet
+	AutoCreate bool
+
+	// DropLegacyTestWAL drops the _test_wal table earlier versions created
+	// in every database, as databases get opened
+	DropLegacyTestWAL bool
+
+	// Database applies to every database but those in Databases, by name
+	Database  nsqlite.Settings
+	Databases map[string]nsqlite.Settings
+
+	Limits Limits
+
+	// ShutdownTimeout is how long in-flight requests get to finish on
+	// shutdown, 15s if zero
+	ShutdownTimeout time.Duration
+	// DrainTimeout is how long the databases then get to have every
+	// connection handed back and checkpoint their WAL before being closed
+	// regardless, 10s if zero
+	DrainTimeout time.Duration
+
+	TLS TLSConfig
+
+	// Backups are taken on schedule if Backups.Dir is set
+	Backups backups.Config
+}
+
+// Limits bound the resources clients can hold on to. Zero fields use the
+// defaults.
+type Limits struct {
+	// MaxMessageBytes is the largest message the server receives or sends,
+	// gRPC's 4 MiB if zero
+	MaxMessageBytes int
+	// MaxConcurrentStreams caps the concurrent RPCs of a client connection,
+	// unlimited if zero
+	MaxConcurrentStreams uint32
+
+	// Idle server side state is rolled back or closed after these
+	TxIdleTimeout      time.Duration
+	CursorIdleTimeout  time.Duration
+	SessionIdleTimeout time.Duration
+	// WriteTxIdleTimeout is TxIdleTimeout for transactions that aren't
+	// read-only, and CursorIdleTimeout for cursors of writing queries if
+	// shorter. They hold the single writer connection of their database, so
+	// other writes wait for them, and are ended sooner.
+	WriteTxIdleTimeout time.Duration
+
+	// MaxOpenDatabases caps the databases open at once, unlimited if zero.
+	// The least recently used one nobody is using is closed to open another.
+	MaxOpenDatabases int
+	// DatabaseIdleTimeout closes databases unused for that long, never if
+	// zero. Unlike the other idle timeouts it has no default.
+	DatabaseIdleTimeout time.Duration
+}
+
+func (l Limits) withDefaults() Limits {
+	if l.TxIdleTimeout == 0 {
+		l.TxIdleTimeout = defaultTxIdleTimeout
+	}
+	if l.WriteTxIdleTimeout == 0 {
+		l.WriteTxIdleTimeout = min(defaultWriteTxIdleTimeout, l.TxIdleTimeout)
+	}
+	if l.CursorIdleTimeout == 0 {
+		l.CursorIdleTimeout = defaultCursorIdleTimeout
+	}
+	if l.SessionIdleTimeout == 0 {
+		l.SessionIdleTimeout = defaultSessionIdleTimeout
+	}
+	return l
+}
+
+// janitorInterval checks often enough to honor the shortest idle timeout.
+func (l Limits) janitorInterval() time.Duration {
+	interval := l.TxIdleTimeout
+	for _, d := range []time.Duration{l.WriteTxIdleTimeout, l.CursorIdleTimeout, l.SessionIdleTimeout} {
+		if d < interval {
+			interval = d
+		}
+	}
+	return interval / 2
+}
+
+func (l Limits) serverOptions() []grpc.ServerOption {
+	var opts []grpc.ServerOption
+	if l.MaxMessageBytes > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(l.MaxMessageBytes), grpc.MaxSendMsgSize(l.MaxMessageBytes))
+	}
+	if l.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(l.MaxConcurrentStreams))
+	}
+	return opts
+}
+
+// Start serves netsqlite until ctx is done, authenticating clients against tokens.
+func Start(ctx context.Context, tokens *auth.Store, cfg Config) {
+	var listeners []net.Listener
+	for _, addr := range cfg.Addrs {
+		lis, err := net.Listen("tcp", addr)
+		if err != nil {
+			log.Fatalf("Failed to listen: %v", err)
+		}
+		listeners = append(listeners, lis)
 	}
 
-	log.Printf("Loaded %d valid token(s) (INSECURELY!)", len(validTokens))
+	log.Printf("Loaded %d token(s)", tokens.Len())
 
-	authInterceptor := NewAuthInterceptor(validTokens)
+	authInterceptor := NewAuthInterceptor(tokens)
 
-	grpcServer := grpc.NewServer(
+	opts := []grpc.ServerOption{
 		grpc.ChainUnaryInterceptor(authInterceptor.Unary()),
 		grpc.ChainStreamInterceptor(authInterceptor.Stream()),
-	)
+	}
+	opts = append(opts, cfg.Limits.serverOptions()...)
+	if cfg.TLS.Enabled() {
+		creds, err := cfg.TLS.Credentials()
+		if err != nil {
+			log.Fatalf("Failed to set up TLS: %v", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+		log.Printf("TLS enabled (client CA: %q, client certificate required: %t)", cfg.TLS.ClientCAFile, cfg.TLS.RequireClientCert)
+	} else {
+		log.Println("TLS disabled, tokens are sent in plaintext.")
+	}
+
+	grpcServer := grpc.NewServer(opts...)
 
-	netsqliteSrv := NewNetsqliteServer(validTokens, dir)
+	netsqliteSrv := NewNetsqliteServer(cfg.DataDir, cfg.Limits,
+		nsqlite.WithAutoCreate(cfg.AutoCreate),
+		nsqlite.WithDropLegacyTestWAL(cfg.DropLegacyTestWAL),
+		nsqlite.WithSettings(cfg.Database, cfg.Databases),
+		nsqlite.WithMaxOpen(cfg.Limits.MaxOpenDatabases),
+		nsqlite.WithIdleTimeout(cfg.Limits.DatabaseIdleTimeout),
+	)
 	pb.RegisterNetsqliteServiceServer(grpcServer, netsqliteSrv)
 
+	var scheduler *backups.Scheduler
+	if cfg.Backups.Enabled() {
+		var err error
+		scheduler, err = backups.New(netsqliteSrv.dbManager, cfg.Backups)
+		if err != nil {
+			log.Fatalf("Failed to set up backups: %v", err)
+		}
+		scheduler.Start()
+		log.Printf("Backing up to %s on schedule %q", cfg.Backups.Dir, cfg.Backups.Schedule)
+	}
+	pb.RegisterAdminServiceServer(grpcServer, newAdminServer(netsqliteSrv.dbManager, scheduler))
+
 	// for reflection and grpcurl
 	reflection.Register(grpcServer)
 	log.Println("gRPC reflection service registered.")
 
-	go func() {
-		log.Printf("gRPC server listening at %v", lis.Addr())
-		if err := grpcServer.Serve(lis); err != nil && err != grpc.ErrServerStopped {
-			log.Fatalf("Failed to serve gRPC: %v", err)
-		} else if err == grpc.ErrServerStopped {
-			log.Println("gRPC server stopped serving.")
-		}
-	}()
+	for _, lis := range listeners {
+		go func() {
+			log.Printf("gRPC server listening at %v", lis.Addr())
+			if err := grpcServer.Serve(lis); err != nil && err != grpc.ErrServerStopped {
+				log.Fatalf("Failed to serve gRPC: %v", err)
+			} else if err == grpc.ErrServerStopped {
+				log.Println("gRPC server stopped serving.")
+			}
+		}()
+	}
 
 	<-ctx.Done()
 	log.Println("Shutdown signal received. Attempting graceful shutdown...")
 
+	shutdownTimeout := cfg.ShutdownTimeout
+	if shutdownTimeout == 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
 	stopped := make(chan struct{})
 	go func() {
 		grpcServer.GracefulStop()
@@ -54,12 +206,27 @@ func Start(ctx context.Context, validTokens map[string]bool, addr, dir string) {
 	select {
 	case <-stopped:
 		log.Println("gRPC server gracefully stopped.")
-	case <-time.After(15 * time.Second): // Increased timeout
+	case <-time.After(shutdownTimeout):
 		log.Println("Graceful shutdown timed out. Forcing stop.")
 		grpcServer.Stop()
 	}
 
-	// TODO: Close all dbs gracefully
-	// netsqliteSrv.CloseAllDBs()
+	// A backup in progress would hold up closing its database
+	if scheduler != nil {
+		scheduler.Stop()
+	}
+	netsqliteSrv.Close()
+
+	drainTimeout := cfg.DrainTimeout
+	if drainTimeout == 0 {
+		drainTimeout = defaultDrainTimeout
+	}
+	drainCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	if err := netsqliteSrv.dbManager.Close(drainCtx); err != nil {
+		log.Printf("Databases did not close cleanly:\n%v", err)
+	} else {
+		log.Println("Databases checkpointed and closed.")
+	}
 	log.Println("Server shut down.")
 }
@@ -1,30 +1,89 @@
 package proto_test
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
+	"io"
+	"net"
 	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/alfredosa/netsqlite/internal/auth"
+	"github.com/alfredosa/netsqlite/internal/backups"
 	proto "github.com/alfredosa/netsqlite/internal/grpc"
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	"github.com/alfredosa/netsqlite/pkg/drivers"
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
 	"github.com/stretchr/testify/assert"
-
-	_ "github.com/alfredosa/netsqlite/pkg/drivers"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
-func prepServer(ctx context.Context, t *testing.T) (string, string, string) {
+// prepServer starts a server accepting the returned token for every
+// database, plus any extra tokens.
+func prepServer(ctx context.Context, t *testing.T, extra ...auth.Token) (string, string, string) {
+	t.Helper()
+	return startServer(ctx, t, proto.Config{AutoCreate: true}, extra...)
+}
+
+// startServer is prepServer with cfg, its address and data directory are
+// filled in.
+func startServer(ctx context.Context, t *testing.T, cfg proto.Config, extra ...auth.Token) (string, string, string) {
 	t.Helper()
 	token := "123"
-	addr := ":3451"
+	addr := freeAddr(t)
 	dir, err := os.MkdirTemp("", "netsqlite-*")
 	assert.NoError(t, err)
 
-	go proto.Start(ctx, map[string]bool{token: true}, addr, dir)
+	tokens, err := auth.NewStore(append(extra, auth.Token{Name: "test", Hash: hashToken(t, token)})...)
+	require.NoError(t, err)
+
+	cfg.Addrs = []string{addr}
+	cfg.DataDir = dir
+	go proto.Start(ctx, tokens, cfg)
+	waitForServer(t, addr)
 
 	return addr, token, dir
+}
+
+func hashToken(t *testing.T, token string) string {
+	t.Helper()
+	hash, err := auth.HashToken(token)
+	require.NoError(t, err)
+	return hash
+}
 
+// freeAddr finds a port nobody is listening on, so servers of different tests don't collide.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+	return lis.Addr().String()
+}
+
+func waitForServer(t *testing.T, addr string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("server at %s did not come up", addr)
 }
 
 func Test_Ping(t *testing.T) {
@@ -49,6 +108,44 @@ func Test_Ping(t *testing.T) {
 	defer time.Sleep(time.Millisecond * 10)
 }
 
+func Test_InvalidToken(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t)
+	defer os.RemoveAll(dir)
+
+	grpcConn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer grpcConn.Close()
+	client := pb.NewNetsqliteServiceClient(grpcConn)
+
+	for _, tok := range []string{"SUPERSECRETTOKEN", "", token + "x"} {
+		md := metadata.Pairs(proto.AuthTokenHeader, "Bearer "+tok, proto.DatabaseHeader, "authdb")
+		_, err = client.Ping(metadata.NewOutgoingContext(ctx, md), &pb.PingRequest{DatabaseName: "authdb"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err), "token %q", tok)
+	}
+}
+
+func Test_InvalidDatabaseName(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"..%2F..%2Fetc%2Fescape", "%2Ftmp%2Fescape.db", "escape.db-wal"} {
+		conn, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/%s?database=%s", addr, token, name))
+		require.NoError(t, err)
+
+		err = conn.PingContext(ctx)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), name)
+		conn.Close()
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "no database should have been created")
+}
+
 func Test_Exec(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
 	defer cancel()
@@ -113,3 +210,1100 @@ func Test_Exec(t *testing.T) {
 	// wait a bit for the server to die
 	defer time.Sleep(time.Millisecond * 10)
 }
+
+func Test_Transactions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t)
+	defer os.RemoveAll(dir)
+
+	dns := fmt.Sprintf("netsqlite://%s/%s?database=%s", addr, token, "txdb")
+	conn, err := sql.Open("netsqlite", dns)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Exec(`CREATE TABLE IF NOT EXISTS accounts (id INTEGER PRIMARY KEY, balance INTEGER)`)
+	require.NoError(t, err)
+
+	count := func() int {
+		var n int
+		require.NoError(t, conn.QueryRow(`SELECT count(*) FROM accounts`).Scan(&n))
+		return n
+	}
+
+	// Rolled back work is gone
+	tx, err := conn.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	_, err = tx.Exec(`INSERT INTO accounts (balance) VALUES (?)`, 100)
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+	assert.Equal(t, 0, count())
+
+	// Committed work stays, and is visible inside the transaction before it
+	tx, err = conn.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	require.NoError(t, err)
+	_, err = tx.Exec(`INSERT INTO accounts (balance) VALUES (?), (?)`, 100, 50)
+	require.NoError(t, err)
+	var inTx int
+	require.NoError(t, tx.QueryRow(`SELECT count(*) FROM accounts`).Scan(&inTx))
+	assert.Equal(t, 2, inTx)
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, 2, count())
+
+	// Read only transactions can't write
+	tx, err = conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	require.NoError(t, err)
+	_, err = tx.Exec(`INSERT INTO accounts (balance) VALUES (?)`, 1)
+	assert.Error(t, err)
+	require.NoError(t, tx.Rollback())
+
+	// Finished transactions are unknown to the server
+	assert.Error(t, tx.Commit())
+}
+
+func Test_PreparedStatements(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t)
+	defer os.RemoveAll(dir)
+
+	dns := fmt.Sprintf("netsqlite://%s/%s?database=%s", addr, token, "stmtdb")
+	conn, err := sql.Open("netsqlite", dns)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Exec(`CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT, price REAL)`)
+	require.NoError(t, err)
+
+	insert, err := conn.PrepareContext(ctx, `INSERT INTO items (name, price) VALUES (?, ?)`)
+	require.NoError(t, err)
+	defer insert.Close()
+
+	for i := range 20 {
+		res, err := insert.ExecContext(ctx, fmt.Sprintf("item-%d", i), float64(i))
+		require.NoError(t, err)
+		id, err := res.LastInsertId()
+		require.NoError(t, err)
+		assert.Equal(t, int64(i+1), id)
+	}
+
+	// The server reports the parameter count, so database/sql checks it for us
+	_, err = insert.ExecContext(ctx, "too few")
+	assert.Error(t, err)
+
+	sel, err := conn.PrepareContext(ctx, `SELECT id, name FROM items WHERE price >= ? ORDER BY id`)
+	require.NoError(t, err)
+	defer sel.Close()
+
+	rows, err := sel.QueryContext(ctx, 15.0)
+	require.NoError(t, err)
+	cols, err := rows.Columns()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "name"}, cols)
+
+	var names []string
+	for rows.Next() {
+		var id int
+		var name string
+		require.NoError(t, rows.Scan(&id, &name))
+		names = append(names, name)
+	}
+	require.NoError(t, rows.Err())
+	require.NoError(t, rows.Close())
+	assert.Equal(t, []string{"item-15", "item-16", "item-17", "item-18", "item-19"}, names)
+
+	// Statements prepared inside a transaction see its uncommitted schema
+	tx, err := conn.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	_, err = tx.ExecContext(ctx, `CREATE TABLE tags (name TEXT)`)
+	require.NoError(t, err)
+	tagInsert, err := tx.PrepareContext(ctx, `INSERT INTO tags (name) VALUES (?)`)
+	require.NoError(t, err)
+	_, err = tagInsert.ExecContext(ctx, "new")
+	require.NoError(t, err)
+	// Statements prepared outside of it run inside it through tx.Stmt
+	_, err = tx.StmtContext(ctx, insert).ExecContext(ctx, "in-tx", 99.0)
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	err = sel.QueryRowContext(ctx, 99.0).Scan(new(int), new(string))
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func Test_TypedValues(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t)
+	defer os.RemoveAll(dir)
+
+	dns := fmt.Sprintf("netsqlite://%s/%s?database=%s", addr, token, "typesdb")
+	conn, err := sql.Open("netsqlite", dns)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Exec(`CREATE TABLE typed (
+		i INTEGER, f REAL, s TEXT, b BLOB, flag BOOLEAN, ts DATETIME, n TEXT)`)
+	require.NoError(t, err)
+
+	bigInt := int64(1<<62 + 1) // not representable as a float64
+	blob := []byte{0x00, 0xff, 0x10, 0x00}
+	ts := time.Date(2024, 2, 29, 13, 14, 15, 123456000, time.UTC)
+
+	_, err = conn.Exec(`INSERT INTO typed VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		bigInt, 3.25, "text", blob, true, ts, nil)
+	require.NoError(t, err)
+
+	var (
+		i    int64
+		f    float64
+		s    string
+		b    []byte
+		flag bool
+		got  time.Time
+		n    sql.NullString
+	)
+	err = conn.QueryRow(`SELECT i, f, s, b, flag, ts, n FROM typed`).Scan(&i, &f, &s, &b, &flag, &got, &n)
+	require.NoError(t, err)
+
+	assert.Equal(t, bigInt, i)
+	assert.Equal(t, 3.25, f)
+	assert.Equal(t, "text", s)
+	assert.Equal(t, blob, b)
+	assert.True(t, flag)
+	assert.True(t, ts.Equal(got), "expected %v, got %v", ts, got)
+	assert.False(t, n.Valid)
+
+	// Exact integer types also work as args
+	var count int
+	err = conn.QueryRow(`SELECT count(*) FROM typed WHERE i = ?`, bigInt).Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func Test_ColumnTypes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t)
+	defer os.RemoveAll(dir)
+
+	dns := fmt.Sprintf("netsqlite://%s/%s?database=%s", addr, token, "coltypesdb")
+	conn, err := sql.Open("netsqlite", dns)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Exec(`CREATE TABLE products (
+		id INTEGER PRIMARY KEY, name VARCHAR(255), price REAL, image BLOB, active BOOLEAN, created DATETIME)`)
+	require.NoError(t, err)
+
+	rows, err := conn.Query(`SELECT id, name, price, image, active, created, 1 + 1 AS expr FROM products`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	cts, err := rows.ColumnTypes()
+	require.NoError(t, err)
+	require.Len(t, cts, 7)
+
+	expected := []struct {
+		dbType   string
+		scanType reflect.Type
+	}{
+		{"INTEGER", reflect.TypeFor[sql.NullInt64]()},
+		{"VARCHAR(255)", reflect.TypeFor[sql.NullString]()},
+		{"REAL", reflect.TypeFor[sql.NullFloat64]()},
+		{"BLOB", reflect.TypeFor[sql.RawBytes]()},
+		{"BOOLEAN", reflect.TypeFor[sql.NullBool]()},
+		{"DATETIME", reflect.TypeFor[sql.NullTime]()},
+		{"", reflect.TypeFor[any]()},
+	}
+	for i, ct := range cts {
+		assert.Equal(t, expected[i].dbType, ct.DatabaseTypeName(), ct.Name())
+		assert.Equal(t, expected[i].scanType, ct.ScanType(), ct.Name())
+		nullable, ok := ct.Nullable()
+		assert.True(t, ok, ct.Name())
+		assert.True(t, nullable, ct.Name())
+	}
+}
+
+func Test_BatchedRows(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t)
+	defer os.RemoveAll(dir)
+
+	const total = 1000
+	query := fmt.Sprintf(`WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < %d)
+		SELECT x, printf('row-%%04d', x) FROM c`, total)
+
+	for i, params := range []string{"", "&batch_rows=7", "&batch_bytes=64", "&batch_rows=1"} {
+		t.Run("params"+params, func(t *testing.T) {
+			dns := fmt.Sprintf("netsqlite://%s/%s?database=batch%d%s", addr, token, i, params)
+			conn, err := sql.Open("netsqlite", dns)
+			require.NoError(t, err)
+			defer conn.Close()
+
+			rows, err := conn.QueryContext(ctx, query)
+			require.NoError(t, err)
+			defer rows.Close()
+
+			n := 0
+			for rows.Next() {
+				var x int
+				var label string
+				require.NoError(t, rows.Scan(&x, &label))
+				n++
+				require.Equal(t, n, x)
+				require.Equal(t, fmt.Sprintf("row-%04d", n), label)
+			}
+			require.NoError(t, rows.Err())
+			assert.Equal(t, total, n)
+		})
+	}
+}
+
+func Test_Cursor(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t)
+	defer os.RemoveAll(dir)
+
+	const total = 500
+	query := fmt.Sprintf(`WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < %d)
+		SELECT x FROM c`, total)
+
+	for i, params := range []string{"", "&fetch_size=1", "&fetch_size=33", "&fetch_size=500"} {
+		t.Run("params"+params, func(t *testing.T) {
+			dns := fmt.Sprintf("netsqlite://%s/%s?database=cursor%d&cursor=true%s", addr, token, i, params)
+			conn, err := sql.Open("netsqlite", dns)
+			require.NoError(t, err)
+			defer conn.Close()
+
+			rows, err := conn.QueryContext(ctx, query)
+			require.NoError(t, err)
+			defer rows.Close()
+
+			n := 0
+			for rows.Next() {
+				var x int
+				require.NoError(t, rows.Scan(&x))
+				n++
+				require.Equal(t, n, x)
+			}
+			require.NoError(t, rows.Err())
+			assert.Equal(t, total, n)
+
+			// Closing halfway through frees the cursor too
+			rows, err = conn.QueryContext(ctx, query)
+			require.NoError(t, err)
+			require.True(t, rows.Next())
+			require.NoError(t, rows.Close())
+
+			// A row failing partway is an error, not the end of the rows
+			rows, err = conn.QueryContext(ctx, `WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < 100)
+				SELECT CASE WHEN x < 50 THEN x ELSE abs(-9223372036854775807 - 1) END FROM c`)
+			require.NoError(t, err)
+			for rows.Next() {
+			}
+			assert.Error(t, rows.Err())
+			require.NoError(t, rows.Close())
+
+			// Inside a transaction queries are streamed as usual
+			tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+			require.NoError(t, err)
+			var count int
+			require.NoError(t, tx.QueryRowContext(ctx, "SELECT count(*) FROM ("+query+")").Scan(&count))
+			assert.Equal(t, total, count)
+			require.NoError(t, tx.Rollback())
+		})
+	}
+}
+
+func Test_ExecBatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t)
+	defer os.RemoveAll(dir)
+
+	dns := fmt.Sprintf("netsqlite://%s/%s?database=%s", addr, token, "execbatchdb")
+	db, err := sql.Open("netsqlite", dns)
+	require.NoError(t, err)
+	defer db.Close()
+
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Counted in a transaction so the check doesn't hold on to a pooled connection
+	count := func() int {
+		tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		require.NoError(t, err)
+		defer tx.Rollback()
+		var n int
+		require.NoError(t, tx.QueryRowContext(ctx, `SELECT count(*) FROM items`).Scan(&n))
+		return n
+	}
+
+	results, err := drivers.ExecBatch(ctx, conn, []drivers.Statement{
+		{SQL: `CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT UNIQUE)`},
+		{SQL: `INSERT INTO items (name) VALUES (?), (?)`, Args: []any{"a", "b"}},
+		{SQL: `INSERT INTO items (name) VALUES (?)`, Args: []any{"c"}},
+	}, drivers.BatchOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, int64(2), results[1].RowsAffected)
+	assert.Equal(t, int64(3), results[2].LastInsertID)
+	assert.Equal(t, 3, count())
+
+	// A failure rolls back the whole batch
+	results, err = drivers.ExecBatch(ctx, conn, []drivers.Statement{
+		{SQL: `INSERT INTO items (name) VALUES (?)`, Args: []any{"d"}},
+		{SQL: `INSERT INTO items (name) VALUES (?)`, Args: []any{"a"}},
+		{SQL: `INSERT INTO items (name) VALUES (?)`, Args: []any{"e"}},
+	}, drivers.BatchOptions{})
+	var batchErr *drivers.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 1, batchErr.Index)
+	assert.Len(t, results, 2)
+	assert.Equal(t, 3, count())
+
+	// Unless asked to carry on
+	results, err = drivers.ExecBatch(ctx, conn, []drivers.Statement{
+		{SQL: `INSERT INTO items (name) VALUES (?)`, Args: []any{"d"}},
+		{SQL: `INSERT INTO items (name) VALUES (?)`, Args: []any{"a"}},
+		{SQL: `INSERT INTO items (name) VALUES (?)`, Args: []any{"e"}},
+	}, drivers.BatchOptions{ContinueOnError: true})
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 1, batchErr.Index)
+	require.Len(t, results, 3)
+	assert.Error(t, results[1].Err)
+	assert.NoError(t, results[2].Err)
+	assert.Equal(t, 5, count())
+
+	// Inside a transaction a failing batch is undone, the transaction carries on
+	tx, err := conn.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	_, err = tx.ExecContext(ctx, `INSERT INTO items (name) VALUES ('f')`)
+	require.NoError(t, err)
+	_, err = drivers.ExecBatch(ctx, conn, []drivers.Statement{
+		{SQL: `INSERT INTO items (name) VALUES ('g')`},
+		{SQL: `INSERT INTO items (name) VALUES ('a')`},
+	}, drivers.BatchOptions{})
+	require.ErrorAs(t, err, &batchErr)
+	_, err = drivers.ExecBatch(ctx, conn, []drivers.Statement{{SQL: `SELECT 1`}}, drivers.BatchOptions{ContinueOnError: true})
+	assert.Error(t, err)
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, 6, count())
+}
+
+func Test_BulkInsert(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t)
+	defer os.RemoveAll(dir)
+
+	dns := fmt.Sprintf("netsqlite://%s/%s?database=%s", addr, token, "bulkdb")
+	db, err := sql.Open("netsqlite", dns)
+	require.NoError(t, err)
+	defer db.Close()
+
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	count := func() int {
+		tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		require.NoError(t, err)
+		defer tx.Rollback()
+		var n int
+		require.NoError(t, tx.QueryRowContext(ctx, `SELECT count(*) FROM "bulk items"`).Scan(&n))
+		return n
+	}
+
+	_, err = drivers.ExecBatch(ctx, conn, []drivers.Statement{
+		{SQL: `CREATE TABLE "bulk items" (id INTEGER PRIMARY KEY, "the name" TEXT UNIQUE, price REAL)`},
+	}, drivers.BatchOptions{})
+	require.NoError(t, err)
+
+	const total = 5000
+	rows := func(yield func([]any, error) bool) {
+		for i := range total {
+			if !yield([]any{i + 1, fmt.Sprintf("item-%d", i+1), float64(i) / 2}, nil) {
+				return
+			}
+		}
+	}
+	columns := []string{"id", "the name", "price"}
+	n, err := drivers.BulkInsert(ctx, conn, "bulk items", columns, rows)
+	require.NoError(t, err)
+	assert.Equal(t, int64(total), n)
+	assert.Equal(t, total, count())
+
+	// A failing row rolls back every row of the insert
+	_, err = drivers.BulkInsertRows(ctx, conn, "bulk items", columns, [][]any{
+		{total + 1, "new", 1.0},
+		{total + 2, "item-1", 2.0},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, total, count())
+
+	// So does an error from the caller's iterator
+	_, err = drivers.BulkInsert(ctx, conn, "bulk items", columns, func(yield func([]any, error) bool) {
+		if yield([]any{total + 1, "new", 1.0}, nil) {
+			yield(nil, fmt.Errorf("source went away"))
+		}
+	})
+	assert.ErrorContains(t, err, "source went away")
+	assert.Equal(t, total, count())
+
+	_, err = drivers.BulkInsertRows(ctx, conn, "no such table", columns, [][]any{{1, "a", 1.0}})
+	assert.Error(t, err)
+
+	// Inside a transaction the rows only land on commit
+	tx, err := conn.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	n, err = drivers.BulkInsertRows(ctx, conn, "bulk items", columns, [][]any{{total + 1, "new", 1.0}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, total+1, count())
+}
+
+func Test_SQLiteErrors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t)
+	defer os.RemoveAll(dir)
+
+	dns := fmt.Sprintf("netsqlite://%s/%s?database=%s", addr, token, "errdb")
+	db, err := sql.Open("netsqlite", dns)
+	require.NoError(t, err)
+	defer db.Close()
+
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = drivers.ExecBatch(ctx, conn, []drivers.Statement{
+		{SQL: `CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL UNIQUE)`},
+		{SQL: `INSERT INTO users (email) VALUES ('a@example.com')`},
+	}, drivers.BatchOptions{})
+	require.NoError(t, err)
+
+	sqliteError := func(err error) *drivers.Error {
+		t.Helper()
+		var sqliteErr *drivers.Error
+		require.ErrorAs(t, err, &sqliteErr)
+		return sqliteErr
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO users (email) VALUES ('a@example.com')`)
+	sqliteErr := sqliteError(err)
+	assert.Equal(t, drivers.ErrConstraint, sqliteErr.Code)
+	assert.Equal(t, drivers.ErrConstraintUnique, sqliteErr.ExtendedCode)
+	assert.Equal(t, "SQLITE_CONSTRAINT", sqliteErr.Reason)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO users (email) VALUES (NULL)`)
+	sqliteErr = sqliteError(err)
+	assert.Equal(t, drivers.ErrConstraintNotNull, sqliteErr.ExtendedCode)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO users email VALUES`)
+	assert.Equal(t, drivers.ErrError, sqliteError(err).Code)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.NoError(t, tx.Rollback())
+
+	roTx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	require.NoError(t, err)
+	_, err = roTx.ExecContext(ctx, `INSERT INTO users (email) VALUES ('b@example.com')`)
+	assert.Equal(t, drivers.ErrReadonly, sqliteError(err).Code)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.NoError(t, roTx.Rollback())
+
+	// Queries report errors the same way
+	_, err = db.QueryContext(ctx, `SELECT * FROM no_such_table`)
+	assert.Equal(t, drivers.ErrError, sqliteError(err).Code)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func Test_TokenPermissions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t,
+		auth.Token{Name: "bi", Hash: hashToken(t, "bi-token"), Role: "read-only", Databases: []string{"analytics*"}},
+	)
+	defer os.RemoveAll(dir)
+
+	owner, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/%s?database=analytics", addr, token))
+	require.NoError(t, err)
+	defer owner.Close()
+	ownerConn, err := owner.Conn(ctx)
+	require.NoError(t, err)
+	defer ownerConn.Close()
+	_, err = drivers.ExecBatch(ctx, ownerConn, []drivers.Statement{
+		{SQL: `CREATE TABLE sales (id INTEGER PRIMARY KEY, amount INTEGER)`},
+		{SQL: `INSERT INTO sales (amount) VALUES (10), (20)`},
+	}, drivers.BatchOptions{})
+	require.NoError(t, err)
+
+	bi, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/%s?database=analytics", addr, "bi-token"))
+	require.NoError(t, err)
+	defer bi.Close()
+	biConn, err := bi.Conn(ctx)
+	require.NoError(t, err)
+	defer biConn.Close()
+
+	// Reading is fine
+	var total int
+	require.NoError(t, biConn.QueryRowContext(ctx, `SELECT sum(amount) FROM sales`).Scan(&total))
+	assert.Equal(t, 30, total)
+
+	// Writing isn't, however it's attempted
+	_, err = biConn.ExecContext(ctx, `DELETE FROM sales`)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = drivers.ExecBatch(ctx, biConn, []drivers.Statement{{SQL: `DELETE FROM sales`}}, drivers.BatchOptions{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = drivers.BulkInsertRows(ctx, biConn, "sales", []string{"amount"}, [][]any{{1}})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	rows, err := biConn.QueryContext(ctx, `INSERT INTO sales (amount) VALUES (1) RETURNING id`)
+	if err == nil {
+		assert.False(t, rows.Next())
+		err = rows.Err()
+		rows.Close()
+	}
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	tx, err := biConn.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.QueryRowContext(ctx, `SELECT count(*) FROM sales`).Scan(&total))
+	assert.Equal(t, 2, total)
+	_, err = tx.ExecContext(ctx, `DELETE FROM sales`)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.NoError(t, tx.Rollback())
+
+	// Nor can it touch databases it wasn't granted
+	grpcConn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer grpcConn.Close()
+	md := metadata.Pairs(proto.AuthTokenHeader, "Bearer bi-token", proto.DatabaseHeader, "production")
+	_, err = pb.NewNetsqliteServiceClient(grpcConn).Ping(metadata.NewOutgoingContext(ctx, md), &pb.PingRequest{DatabaseName: "production"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	require.NoError(t, ownerConn.QueryRowContext(ctx, `SELECT count(*) FROM sales`).Scan(&total))
+	assert.Equal(t, 2, total)
+}
+
+func Test_DatabaseHeaderMismatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t,
+		auth.Token{Name: "bi", Hash: hashToken(t, "bi-token"), Role: "read-only", Databases: []string{"analytics"}},
+	)
+	defer os.RemoveAll(dir)
+
+	grpcConn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer grpcConn.Close()
+	client := pb.NewNetsqliteServiceClient(grpcConn)
+
+	// Authorized for analytics, asking for production in the body
+	md := metadata.Pairs(proto.AuthTokenHeader, "Bearer bi-token", proto.DatabaseHeader, "analytics")
+	mdCtx := metadata.NewOutgoingContext(ctx, md)
+
+	_, err = client.Ping(mdCtx, &pb.PingRequest{DatabaseName: "production"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.BeginTx(mdCtx, &pb.BeginTxRequest{DatabaseName: "production"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	stream, err := client.Query(mdCtx, &pb.QueryRequest{DatabaseName: "production", Sql: "SELECT 1"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.Ping(mdCtx, &pb.PingRequest{DatabaseName: "analytics"})
+	assert.NoError(t, err)
+
+	// Same for a writer token on the client streaming RPC
+	md = metadata.Pairs(proto.AuthTokenHeader, "Bearer "+token, proto.DatabaseHeader, "scratch")
+	bulk, err := client.BulkInsert(metadata.NewOutgoingContext(ctx, md))
+	require.NoError(t, err)
+	require.NoError(t, bulk.Send(&pb.BulkInsertRequest{Payload: &pb.BulkInsertRequest_Header{Header: &pb.BulkInsertHeader{
+		DatabaseName: "production",
+		Table:        "t",
+		Columns:      []string{"a"},
+	}}}))
+	_, err = bulk.CloseAndRecv()
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.NotContains(t, e.Name(), "production")
+	}
+}
+
+func Test_PragmaProfiles(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	addr, token, dir := startServer(ctx, t, proto.Config{
+		AutoCreate: true,
+		Database:   nsqlite.Settings{Pragmas: map[string]string{"foreign_keys": "ON", "synchronous": "NORMAL"}},
+		Databases: map[string]nsqlite.Settings{
+			"bulk.db": {Pragmas: map[string]string{"synchronous": "OFF"}},
+		},
+	}, auth.Token{Name: "ops", Hash: hashToken(t, "ops-token"), Role: "admin"})
+	defer os.RemoveAll(dir)
+
+	// Foreign keys are enforced for every client without them asking
+	db, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/%s?database=shop.db", addr, token))
+	require.NoError(t, err)
+	defer db.Close()
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = drivers.ExecBatch(ctx, conn, []drivers.Statement{
+		{SQL: `CREATE TABLE customers (id INTEGER PRIMARY KEY)`},
+		{SQL: `CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER REFERENCES customers (id))`},
+	}, drivers.BatchOptions{})
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, `INSERT INTO orders (customer_id) VALUES (42)`)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	grpcConn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer grpcConn.Close()
+	admin := pb.NewAdminServiceClient(grpcConn)
+
+	getPragmas := func(tok, dbName string) (map[string]*pb.Pragma, error) {
+		md := metadata.Pairs(proto.AuthTokenHeader, "Bearer "+tok, proto.DatabaseHeader, dbName)
+		resp, err := admin.GetPragmas(metadata.NewOutgoingContext(ctx, md), &pb.GetPragmasRequest{DatabaseName: dbName})
+		if err != nil {
+			return nil, err
+		}
+		pragmas := make(map[string]*pb.Pragma)
+		for _, p := range resp.GetPragmas() {
+			pragmas[p.GetName()] = p
+		}
+		return pragmas, nil
+	}
+
+	// Only admins can look
+	_, err = getPragmas(token, "shop.db")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	pragmas, err := getPragmas("ops-token", "shop.db")
+	require.NoError(t, err)
+	assert.Equal(t, "1", pragmas["foreign_keys"].GetValue())
+	assert.Equal(t, "ON", pragmas["foreign_keys"].GetConfigured())
+	assert.Equal(t, "1", pragmas["synchronous"].GetValue()) // NORMAL
+	assert.Equal(t, "wal", pragmas["journal_mode"].GetValue())
+	assert.Equal(t, "", pragmas["mmap_size"].GetConfigured())
+
+	// Per database pragmas go over the defaults
+	pragmas, err = getPragmas("ops-token", "bulk.db")
+	require.NoError(t, err)
+	assert.Equal(t, "1", pragmas["foreign_keys"].GetValue())
+	assert.Equal(t, "0", pragmas["synchronous"].GetValue()) // OFF
+}
+
+func Test_ConcurrentPoolUse(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*20)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t)
+	defer os.RemoveAll(dir)
+
+	db, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/%s?database=%s", addr, token, "busy"))
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.ExecContext(ctx, `CREATE TABLE hits (id INTEGER PRIMARY KEY, worker INTEGER)`)
+	require.NoError(t, err)
+
+	// Far more calls than the pool has connections, each must hand its
+	// connection back for the others to get one
+	const workers = 300
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := db.ExecContext(ctx, `INSERT INTO hits (worker) VALUES (?)`, i); err != nil {
+				errs <- err
+				return
+			}
+			var n int
+			if err := db.QueryRowContext(ctx, `SELECT count(*) FROM hits WHERE worker = ?`, i).Scan(&n); err != nil {
+				errs <- err
+				return
+			}
+			if err := db.PingContext(ctx); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	var total int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT count(*) FROM hits`).Scan(&total))
+	assert.Equal(t, workers, total)
+
+	// Streams cancelled half way through hand their connection back too
+	grpcConn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer grpcConn.Close()
+	client := pb.NewNetsqliteServiceClient(grpcConn)
+	md := metadata.Pairs(proto.AuthTokenHeader, "Bearer "+token, proto.DatabaseHeader, "busy")
+	for range 20 {
+		streamCtx, cancelStream := context.WithCancel(metadata.NewOutgoingContext(ctx, md))
+		stream, err := client.Query(streamCtx, &pb.QueryRequest{
+			DatabaseName: "busy",
+			Sql:          "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < 1000000) SELECT x FROM c",
+		})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.NoError(t, err)
+		cancelStream()
+	}
+	require.NoError(t, db.PingContext(ctx))
+}
+
+func Test_WritesThroughQuery(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t)
+	defer os.RemoveAll(dir)
+
+	db, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/%s?database=%s", addr, token, "returning"))
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.ExecContext(ctx, `CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT)`)
+	require.NoError(t, err)
+
+	// Queries go to read-only connections, unless they write
+	var id int64
+	require.NoError(t, db.QueryRowContext(ctx, `INSERT INTO notes (body) VALUES ('hi') RETURNING id`).Scan(&id))
+	assert.Equal(t, int64(1), id)
+
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+	stmt, err := conn.PrepareContext(ctx, `INSERT INTO notes (body) VALUES (?) RETURNING id`)
+	require.NoError(t, err)
+	defer stmt.Close()
+	require.NoError(t, stmt.QueryRowContext(ctx, "again").Scan(&id))
+	assert.Equal(t, int64(2), id)
+
+	var n int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT count(*) FROM notes`).Scan(&n))
+	assert.Equal(t, 2, n)
+}
+
+func Test_AdminDatabases(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	addr, token, dir := startServer(ctx, t, proto.Config{},
+		auth.Token{Name: "ops", Hash: hashToken(t, "ops-token"), Role: "admin"},
+		auth.Token{Name: "tenant-ops", Hash: hashToken(t, "tenant-token"), Role: "admin", Databases: []string{"tenant-*"}},
+	)
+	defer os.RemoveAll(dir)
+
+	grpcConn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer grpcConn.Close()
+	admin := pb.NewAdminServiceClient(grpcConn)
+	as := func(tok, dbName string) context.Context {
+		md := metadata.Pairs(proto.AuthTokenHeader, "Bearer "+tok)
+		if dbName != "" {
+			md.Set(proto.DatabaseHeader, dbName)
+		}
+		return metadata.NewOutgoingContext(ctx, md)
+	}
+
+	// Created on demand even though the server doesn't create them on first use
+	for _, name := range []string{"tenant-a.db", "tenant-b.db", "internal.db"} {
+		created, err := admin.CreateDatabase(as("ops-token", name), &pb.CreateDatabaseRequest{DatabaseName: name})
+		require.NoError(t, err)
+		assert.Equal(t, name, created.GetDatabase().GetName())
+		assert.True(t, created.GetDatabase().GetOpen())
+	}
+	_, err = admin.CreateDatabase(as("ops-token", "internal.db"), &pb.CreateDatabaseRequest{DatabaseName: "internal.db"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	db, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/%s?database=tenant-a.db", addr, token))
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.ExecContext(ctx, `CREATE TABLE t (n INTEGER)`)
+	require.NoError(t, err)
+
+	// No database header needed, only the databases the token can access
+	names := func(tok string) []string {
+		resp, err := admin.ListDatabases(as(tok, ""), &pb.ListDatabasesRequest{})
+		require.NoError(t, err)
+		var names []string
+		for _, db := range resp.GetDatabases() {
+			names = append(names, db.GetName())
+		}
+		return names
+	}
+	assert.Equal(t, []string{"internal.db", "tenant-a.db", "tenant-b.db"}, names("ops-token"))
+	assert.Equal(t, []string{"tenant-a.db", "tenant-b.db"}, names("tenant-token"))
+	_, err = admin.ListDatabases(as(token, ""), &pb.ListDatabasesRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	info, err := admin.GetDatabaseInfo(as("ops-token", "tenant-a.db"), &pb.GetDatabaseInfoRequest{DatabaseName: "tenant-a.db"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), info.GetDatabase().GetPageCount())
+	assert.NotZero(t, info.GetDatabase().GetWalSizeBytes())
+	assert.NotNil(t, info.GetDatabase().GetLastAccess())
+	_, err = admin.GetDatabaseInfo(as("tenant-token", "internal.db"), &pb.GetDatabaseInfoRequest{DatabaseName: "internal.db"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Gone along with its WAL, clients can't bring it back
+	_, err = admin.DropDatabase(as("ops-token", "tenant-a.db"), &pb.DropDatabaseRequest{DatabaseName: "tenant-a.db"})
+	require.NoError(t, err)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.NotContains(t, e.Name(), "tenant-a")
+	}
+	_, err = db.ExecContext(ctx, `INSERT INTO t VALUES (1)`)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = admin.DropDatabase(as("ops-token", "tenant-a.db"), &pb.DropDatabaseRequest{DatabaseName: "tenant-a.db"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func Test_Backup(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t, auth.Token{Name: "ops", Hash: hashToken(t, "ops-token"), Role: "admin"})
+	defer os.RemoveAll(dir)
+
+	db, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/%s?database=live.db", addr, token))
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.ExecContext(ctx, `CREATE TABLE t (id INTEGER PRIMARY KEY, data BLOB)`)
+	require.NoError(t, err)
+	for range 200 {
+		_, err = db.ExecContext(ctx, `INSERT INTO t (data) VALUES (randomblob(1000))`)
+		require.NoError(t, err)
+	}
+
+	// Writes keep going during the backup
+	writing, stopWriting := context.WithCancel(ctx)
+	defer stopWriting()
+	go func() {
+		for writing.Err() == nil {
+			db.ExecContext(writing, `INSERT INTO t (data) VALUES (randomblob(1000))`)
+		}
+	}()
+
+	admin, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/ops-token?database=live.db", addr))
+	require.NoError(t, err)
+	defer admin.Close()
+	conn, err := admin.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	path := filepath.Join(t.TempDir(), "live-backup.db")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	n, err := drivers.Backup(ctx, conn, f)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	stopWriting()
+	assert.Greater(t, n, int64(200*1000))
+
+	copied, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer copied.Close()
+	var rows int
+	require.NoError(t, copied.QueryRowContext(ctx, `SELECT count(*) FROM t`).Scan(&rows))
+	assert.GreaterOrEqual(t, rows, 200)
+	var integrity string
+	require.NoError(t, copied.QueryRowContext(ctx, `PRAGMA integrity_check`).Scan(&integrity))
+	assert.Equal(t, "ok", integrity)
+
+	// Small chunks add up to the same file
+	grpcConn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer grpcConn.Close()
+	md := metadata.Pairs(proto.AuthTokenHeader, "Bearer ops-token", proto.DatabaseHeader, "live.db")
+	stream, err := pb.NewAdminServiceClient(grpcConn).Backup(metadata.NewOutgoingContext(ctx, md), &pb.BackupRequest{DatabaseName: "live.db", ChunkBytes: 4096})
+	require.NoError(t, err)
+	var chunks int
+	var size int64
+	for {
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		if summary := resp.GetSummary(); summary != nil {
+			assert.Equal(t, size, summary.GetSizeBytes())
+			assert.Len(t, summary.GetSha256(), 64)
+			break
+		}
+		assert.LessOrEqual(t, len(resp.GetChunk()), 4096)
+		size += int64(len(resp.GetChunk()))
+		chunks++
+	}
+	assert.Equal(t, int((size+4095)/4096), chunks)
+
+	// Only admins take backups
+	writer, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer writer.Close()
+	_, err = drivers.Backup(ctx, writer, io.Discard)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func Test_Restore(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	addr, token, dir := prepServer(ctx, t, auth.Token{Name: "ops", Hash: hashToken(t, "ops-token"), Role: "admin"})
+	defer os.RemoveAll(dir)
+
+	db, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/%s?database=live.db", addr, token))
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.ExecContext(ctx, `CREATE TABLE t (id INTEGER PRIMARY KEY, data BLOB)`)
+	require.NoError(t, err)
+	for range 100 {
+		_, err = db.ExecContext(ctx, `INSERT INTO t (data) VALUES (randomblob(20000))`)
+		require.NoError(t, err)
+	}
+
+	admin, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/ops-token?database=live.db", addr))
+	require.NoError(t, err)
+	defer admin.Close()
+	conn, err := admin.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	path := filepath.Join(t.TempDir(), "live-backup.db")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	size, err := drivers.Backup(ctx, conn, f)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	_, err = db.ExecContext(ctx, `DELETE FROM t WHERE id > 10`)
+	require.NoError(t, err)
+
+	// Several chunks, checked against the checksum
+	f, err = os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	n, err := drivers.Restore(ctx, conn, f)
+	require.NoError(t, err)
+	assert.Equal(t, size, n)
+	assert.Greater(t, n, int64(1<<20))
+
+	var rows int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT count(*) FROM t`).Scan(&rows))
+	assert.Equal(t, 100, rows)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Name(), ".restore-")
+	}
+
+	// Garbage never gets swapped in
+	_, err = drivers.Restore(ctx, conn, bytes.NewReader(make([]byte, 8192)))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT count(*) FROM t`).Scan(&rows))
+	assert.Equal(t, 100, rows)
+
+	// Nor does an upload that doesn't match its checksum
+	grpcConn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer grpcConn.Close()
+	md := metadata.Pairs(proto.AuthTokenHeader, "Bearer ops-token", proto.DatabaseHeader, "live.db")
+	stream, err := pb.NewAdminServiceClient(grpcConn).Restore(metadata.NewOutgoingContext(ctx, md))
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&pb.RestoreRequest{Payload: &pb.RestoreRequest_Header{Header: &pb.RestoreHeader{DatabaseName: "live.db", Sha256: "00"}}}))
+	_, err = f.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&pb.RestoreRequest{Payload: &pb.RestoreRequest_Chunk{Chunk: data[:1<<20]}}))
+	_, err = stream.CloseAndRecv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Only admins restore
+	writer, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer writer.Close()
+	_, err = f.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	_, err = drivers.Restore(ctx, writer, f)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func Test_ListBackups(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	backupDir := t.TempDir()
+	manifest := `{"backups": [
+  {"database": "tenant-a.db", "file": "tenant-a-20261016T000000Z.db.gz", "taken_at": "2026-10-16T00:00:00Z", "size_bytes": 512, "sha256": "aa", "compressed": true},
+  {"database": "tenant-a.db", "file": "tenant-a-20261017T000000Z.db.gz", "taken_at": "2026-10-17T00:00:00Z", "size_bytes": 640, "sha256": "bb", "compressed": true},
+  {"database": "internal.db", "file": "internal-20261017T000000Z.db.gz", "taken_at": "2026-10-17T00:00:00Z", "size_bytes": 128, "sha256": "cc", "compressed": true}
+]}`
+	require.NoError(t, os.WriteFile(filepath.Join(backupDir, "manifest.json"), []byte(manifest), 0o600))
+	addr, _, dir := startServer(ctx, t, proto.Config{Backups: backups.Config{Dir: backupDir, Schedule: "@daily"}},
+		auth.Token{Name: "ops", Hash: hashToken(t, "ops-token"), Role: "admin"},
+		auth.Token{Name: "tenant-ops", Hash: hashToken(t, "tenant-token"), Role: "admin", Databases: []string{"tenant-*"}},
+	)
+	defer os.RemoveAll(dir)
+
+	grpcConn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer grpcConn.Close()
+	admin := pb.NewAdminServiceClient(grpcConn)
+	as := func(tok string) context.Context {
+		return metadata.NewOutgoingContext(ctx, metadata.Pairs(proto.AuthTokenHeader, "Bearer "+tok))
+	}
+
+	list, err := admin.ListBackups(as("ops-token"), &pb.ListBackupsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetBackups(), 3)
+	assert.Equal(t, "internal.db", list.GetBackups()[0].GetDatabaseName())
+	latest := list.GetBackups()[1]
+	assert.Equal(t, "tenant-a-20261017T000000Z.db.gz", latest.GetFileName())
+	assert.Equal(t, time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), latest.GetTakenAt().AsTime())
+	assert.Equal(t, int64(640), latest.GetSizeBytes())
+	assert.Equal(t, "bb", latest.GetSha256())
+	assert.True(t, latest.GetCompressed())
+
+	list, err = admin.ListBackups(as("ops-token"), &pb.ListBackupsRequest{Databases: []string{"internal.db"}})
+	require.NoError(t, err)
+	assert.Len(t, list.GetBackups(), 1)
+
+	// Only the backups of databases the token has access to
+	list, err = admin.ListBackups(as("tenant-token"), &pb.ListBackupsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetBackups(), 2)
+	for _, backup := range list.GetBackups() {
+		assert.Equal(t, "tenant-a.db", backup.GetDatabaseName())
+	}
+
+	_, err = admin.ListBackups(as("123"), &pb.ListBackupsRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Servers without scheduled backups say so
+	addr, _, dir = startServer(ctx, t, proto.Config{}, auth.Token{Name: "ops", Hash: hashToken(t, "ops-token"), Role: "admin"})
+	defer os.RemoveAll(dir)
+	other, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer other.Close()
+	_, err = pb.NewAdminServiceClient(other).ListBackups(as("ops-token"), &pb.ListBackupsRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -3,12 +3,19 @@ package proto
 
 import (
 	"context"
+	"crypto/x509"
+	"errors"
 	"log"
 	"strings"
 
+	"github.com/alfredosa/netsqlite/internal/auth"
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -20,51 +27,141 @@ const (
 
 // AuthInterceptor provides gRPC interceptors for authentication.
 type AuthInterceptor struct {
-	validTokens map[string]bool // TODO: REPLACEEEEE
+	tokens *auth.Store
 }
 
-// NewAuthInterceptor creates a new interceptor.
-func NewAuthInterceptor(validTokens map[string]bool) *AuthInterceptor {
+// NewAuthInterceptor creates a new interceptor validating tokens against the store.
+func NewAuthInterceptor(tokens *auth.Store) *AuthInterceptor {
 	return &AuthInterceptor{
-		validTokens: validTokens,
+		tokens: tokens,
 	}
 }
 
-// authenticate performs the actual validation.
-func (a *AuthInterceptor) authenticate(ctx context.Context) (string, error) {
+// authenticate performs the actual validation, the returned context
+// carries the authenticated auth.Principal.
+func (a *AuthInterceptor) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return "", status.Error(codes.Unauthenticated, "metadata is not provided")
+		return nil, status.Error(codes.Unauthenticated, "metadata is not provided")
 	}
 
-	// 1. Extract and validate Token
+	// 1. Extract and validate Token, falling back to the client certificate
+	var principal *auth.Principal
+	var err error
 	authHeaders := md.Get(AuthTokenHeader)
-	if len(authHeaders) == 0 {
-		return "", status.Error(codes.Unauthenticated, "authorization token is not provided")
+	cert := peerCertificate(ctx)
+	switch {
+	case len(authHeaders) > 0:
+		// Assuming "Bearer <token>" format
+		token := strings.TrimPrefix(authHeaders[0], "Bearer ")
+		principal, err = a.tokens.Authenticate(token)
+	case cert != nil:
+		principal, err = a.tokens.AuthenticateCert(auth.CertIdentities(cert))
+	default:
+		return nil, status.Error(codes.Unauthenticated, "authorization token is not provided")
+	}
+	switch {
+	case errors.Is(err, auth.ErrExpiredToken), errors.Is(err, auth.ErrRevokedToken):
+		log.Printf("Auth failed: token %q: %v", principal.Name, err)
+		return nil, status.Errorf(codes.Unauthenticated, "authorization %v", err)
+	case err != nil && len(authHeaders) == 0:
+		log.Printf("Auth failed: client certificate %q: %v", cert.Subject, err)
+		return nil, status.Error(codes.Unauthenticated, "client certificate is not authorized")
+	case err != nil:
+		log.Printf("Auth failed: %v", err)
+		return nil, status.Error(codes.Unauthenticated, "invalid authorization token")
 	}
-	// Assuming "Bearer <token>" format
-	token := strings.TrimPrefix(authHeaders[0], "Bearer ")
-	if token == "" || !a.validTokens[token] { // Use your actual validation logic
-		log.Printf("Auth failed: Invalid token received '%s'", token)
-		return "", status.Error(codes.Unauthenticated, "invalid authorization token")
+
+	if serverWideMethods[fullMethod] {
+		log.Printf("Auth successful for %s (Token: %s, %s)", fullMethod, principal.Name, principal.Role)
+		return auth.NewContext(ctx, principal), nil
 	}
 
 	// 2. Extract Database Name
 	dbNames := md.Get(DatabaseHeader)
 	if len(dbNames) == 0 {
-		return "", status.Error(codes.InvalidArgument, "x-database-name header is not provided")
+		return nil, status.Error(codes.InvalidArgument, "x-database-name header is not provided")
 	}
 	dbName := dbNames[0]
 	if dbName == "" {
-		return "", status.Error(codes.InvalidArgument, "x-database-name header cannot be empty")
+		return nil, status.Error(codes.InvalidArgument, "x-database-name header cannot be empty")
+	}
+	if err := nsqlite.ValidateName(dbName); err != nil {
+		return nil, err
 	}
 
-	// TODO: Add any further checks here if needed (e.g., does this token
-	// have permission for this specific database?)
+	// 3. Check the token was granted this database
+	if !principal.CanAccess(dbName) {
+		log.Printf("Auth failed: token %q has no access to DB: %s", principal.Name, dbName)
+		return nil, status.Errorf(codes.PermissionDenied, "token has no access to database %s", dbName)
+	}
 
 	// Authentication successful
-	log.Printf("Auth successful for DB: %s (Token: %s...)", dbName, token[:min(len(token), 4)]) // Log truncated token
-	return dbName, nil                                                                          // Return validated DB name
+	log.Printf("Auth successful for DB: %s (Token: %s, %s)", dbName, principal.Name, principal.Role)
+	ctx = auth.NewContext(ctx, principal)
+	return context.WithValue(ctx, databaseKey{}, dbName), nil
+}
+
+// peerCertificate returns the client certificate of the connection if it
+// was verified against the client CA, nil otherwise.
+func peerCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.PeerCertificates) == 0 {
+		return nil
+	}
+	return info.State.PeerCertificates[0]
+}
+
+type databaseKey struct{}
+
+// DatabaseFromContext returns the database the request was authorized for.
+func DatabaseFromContext(ctx context.Context) (string, bool) {
+	dbName, ok := ctx.Value(databaseKey{}).(string)
+	return dbName, ok
+}
+
+// databaseNamer is implemented by every request message naming its database.
+type databaseNamer interface {
+	GetDatabaseName() string
+}
+
+// checkDatabase rejects messages naming another database than the one the
+// request was authorized for, otherwise the header check would mean nothing.
+func checkDatabase(ctx context.Context, msg any) error {
+	named, ok := msg.(databaseNamer)
+	if !ok {
+		return nil
+	}
+	dbName, ok := DatabaseFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "request is not authenticated")
+	}
+	if named.GetDatabaseName() != dbName {
+		return status.Errorf(codes.PermissionDenied, "database_name %q does not match the %s header %q", named.GetDatabaseName(), DatabaseHeader, dbName)
+	}
+	return nil
+}
+
+// authedStream hands the authenticated context to stream handlers, and
+// checks every message received names the authorized database.
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return checkDatabase(s.ctx, m)
 }
 
 // Unary returns a server interceptor function for unary RPCs
@@ -81,10 +178,16 @@ func (a *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 		// }
 
 		log.Printf("--> Unary Interceptor: %s", info.FullMethod)
-		_, err := a.authenticate(ctx)
+		ctx, err := a.authenticate(ctx, info.FullMethod)
 		if err != nil {
 			return nil, err // Authentication failed
 		}
+		if err := authorizeMethod(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		if err := checkDatabase(ctx, req); err != nil {
+			return nil, err
+		}
 
 		// Authentication successful, proceed with the handler
 		return handler(ctx, req)
@@ -100,19 +203,15 @@ func (a *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
 		handler grpc.StreamHandler,
 	) error {
 		log.Printf("--> Stream Interceptor: %s", info.FullMethod)
-		_, err := a.authenticate(stream.Context()) // Auth check uses the stream's context
+		ctx, err := a.authenticate(stream.Context(), info.FullMethod) // Auth check uses the stream's context
 		if err != nil {
 			return err // Authentication failed
 		}
+		if err := authorizeMethod(ctx, info.FullMethod); err != nil {
+			return err
+		}
 
 		// Authentication successful, proceed with the handler
-		return handler(srv, stream)
-	}
-}
-
-func min(a, b int) int {
-	if a < b {
-		return a
+		return handler(srv, &authedStream{ServerStream: stream, ctx: ctx})
 	}
-	return b
 }
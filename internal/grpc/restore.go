@@ -0,0 +1,87 @@
+package proto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"os"
+
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Restore receives the whole file into a temporary file next to the
+// databases before anything is swapped, so a broken upload never touches
+// the database.
+func (s *adminServer) Restore(stream pb.AdminService_RestoreServer) error {
+	ctx := stream.Context()
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	header := first.GetHeader()
+	if header == nil {
+		return status.Error(codes.InvalidArgument, "first Restore message must be the header")
+	}
+	// The header isn't a request message of its own, so the interceptor can't check it
+	if err := checkDatabase(ctx, header); err != nil {
+		return err
+	}
+
+	tmp, err := s.dbManager.CreateTemp(".restore-*")
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to create restore file: %v", err)
+	}
+	// Gone once Restore renamed it into place
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	w := io.MultiWriter(tmp, hash)
+	var size int64
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			tmp.Close()
+			return err
+		}
+		chunk, ok := req.Payload.(*pb.RestoreRequest_Chunk)
+		if !ok {
+			tmp.Close()
+			return status.Error(codes.InvalidArgument, "only the first Restore message can be the header")
+		}
+		n, err := w.Write(chunk.Chunk)
+		size += int64(n)
+		if err != nil {
+			tmp.Close()
+			return status.Errorf(codes.Internal, "failed to write restore file: %v", err)
+		}
+	}
+	err = tmp.Sync()
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to write restore file: %v", err)
+	}
+	if header.Sha256 != "" && header.Sha256 != hex.EncodeToString(hash.Sum(nil)) {
+		return status.Errorf(codes.InvalidArgument, "sha256 of the %d bytes received doesn't match the header", size)
+	}
+
+	if err := s.dbManager.Restore(ctx, header.DatabaseName, tmp.Name()); err != nil {
+		return err
+	}
+	log.Printf("Restored %s from %d bytes", header.DatabaseName, size)
+
+	info, err := s.dbManager.Info(ctx, header.DatabaseName)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(&pb.RestoreResponse{Database: databaseInfo(info)})
+}
@@ -0,0 +1,164 @@
+package proto
+
+import (
+	"database/sql"
+	"log"
+
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Row batching defaults, a batch is flushed once either limit is reached.
+const (
+	defaultBatchRows  = 256
+	defaultBatchBytes = 1 << 20 // 1 MiB
+	// maxBatchBytes keeps batches under gRPC's default 4 MiB message limit
+	maxBatchBytes = 3 << 20
+)
+
+// batchLimits applies the server defaults and caps to the client's options.
+func batchLimits(opts *pb.BatchOptions) (maxRows int, maxBytes int) {
+	maxRows, maxBytes = defaultBatchRows, defaultBatchBytes
+	if opts.GetMaxRows() > 0 {
+		maxRows = int(opts.GetMaxRows())
+	}
+	if opts.GetMaxBytes() > 0 {
+		maxBytes = min(int(opts.GetMaxBytes()), maxBatchBytes)
+	}
+	return maxRows, maxBytes
+}
+
+// rowReader scans *sql.Rows into protobuf Rows.
+type rowReader struct {
+	rows     *sql.Rows
+	values   []any
+	scanArgs []any
+}
+
+func newRowReader(rows *sql.Rows, colCount int) *rowReader {
+	// Prepare a slice of pointers for Scan (needed for handling NULLs properly)
+	r := &rowReader{
+		rows:     rows,
+		values:   make([]any, colCount),
+		scanArgs: make([]any, colCount),
+	}
+	for i := range r.values {
+		r.scanArgs[i] = &r.values[i]
+	}
+	return r
+}
+
+// next returns the next row, or nil once the rows are exhausted.
+func (r *rowReader) next() (*pb.Row, error) {
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return nil, sqlError(err, "row iteration error")
+		}
+		return nil, nil
+	}
+
+	if err := r.rows.Scan(r.scanArgs...); err != nil {
+		return nil, sqlError(err, "failed to scan row")
+	}
+
+	protoValues := make([]*pb.SqlValue, len(r.values))
+	for i, v := range r.values {
+		var err error
+		protoValues[i], err = sqlValue(v)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert value: %v", err)
+		}
+	}
+	return &pb.Row{Values: protoValues}, nil
+}
+
+// columnsMessage describes the columns of rows.
+func columnsMessage(rows *sql.Rows) (*pb.Columns, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get columns: %v", err)
+	}
+	types, err := columnTypes(rows)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get column types: %v", err)
+	}
+	return &pb.Columns{Names: columns, Types: types}, nil
+}
+
+// streamRows sends the columns followed by every row of rows down the stream,
+// packed in batches bounded by opts.
+func streamRows(stream pb.NetsqliteService_QueryServer, rows *sql.Rows, dbName string, opts *pb.BatchOptions) error {
+	// 1. Get column names and send first message
+	columns, err := columnsMessage(rows)
+	if err != nil {
+		log.Printf("Failed to get columns for DB '%s': %v", dbName, err)
+		return err
+	}
+
+	columnResp := &pb.QueryResponse{
+		Result: &pb.QueryResponse_Columns{Columns: columns},
+	}
+	if err := stream.Send(columnResp); err != nil {
+		log.Printf("Failed to send columns to client: %v", err)
+		return status.Errorf(codes.Internal, "failed to send columns: %v", err)
+	}
+	log.Printf("Sent columns for query on DB '%s': %v", dbName, columns.Names)
+
+	// 2. Stream rows in batches
+	maxRows, maxBytes := batchLimits(opts)
+	reader := newRowReader(rows, len(columns.Names))
+
+	batch := &pb.RowBatch{}
+	batchBytes := 0
+	flush := func() error {
+		if len(batch.Rows) == 0 {
+			return nil
+		}
+		resp := &pb.QueryResponse{
+			Result: &pb.QueryResponse_Batch{Batch: batch},
+		}
+		if err := stream.Send(resp); err != nil {
+			return status.Errorf(codes.Internal, "failed to send rows: %v", err)
+		}
+		batch = &pb.RowBatch{}
+		batchBytes = 0
+		return nil
+	}
+
+	for {
+		row, err := reader.next()
+		if err != nil {
+			log.Printf("Error reading rows for DB '%s': %v", dbName, err)
+			return err
+		}
+		if row == nil {
+			break
+		}
+
+		batch.Rows = append(batch.Rows, row)
+		batchBytes += proto.Size(row)
+		if len(batch.Rows) >= maxRows || batchBytes >= maxBytes {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+
+		// Check context cancellation frequently during long streams
+		select {
+		case <-stream.Context().Done():
+			log.Printf("Client disconnected during query stream for DB '%s'", dbName)
+			return status.Error(codes.Canceled, "client disconnected")
+		default:
+		}
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+
+	log.Printf("Finished streaming query results for DB '%s'", dbName)
+	return nil
+}
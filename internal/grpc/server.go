@@ -3,16 +3,17 @@ package proto
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log"
 	"log/slog"
+	"time"
 
 	"github.com/alfredosa/netsqlite/internal/nsqlite"
 	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/structpb"
 
 	_ "github.com/mattn/go-sqlite3" // SQLite driver
 )
@@ -22,65 +23,113 @@ type netsqliteServer struct {
 	pb.UnimplementedNetsqliteServiceServer
 
 	// --- Server State ---
-	// TODO: VERY basic token check - REPLACE with secure validation
-	validTokens map[string]bool
-
 	// dbManager will manage all connections to all databases concurrently and safely
 	dbManager *nsqlite.DBManager
+
+	// txs holds every open transaction, pinned to its own connection
+	txs *txRegistry
+
+	// stmts holds the prepared statements of every client session
+	stmts *stmtRegistry
+
+	// cursors holds every open cursor, each with its own connection
+	cursors *cursorRegistry
+
+	stopJanitor chan struct{}
 }
 
 // NewNetsqliteServer creates a new server instance.
-func NewNetsqliteServer(tokens map[string]bool, datadir string) *netsqliteServer {
-	manager := nsqlite.NewManager(datadir)
+func NewNetsqliteServer(datadir string, limits Limits, opts ...nsqlite.Option) *netsqliteServer {
+	manager := nsqlite.NewManager(datadir, opts...)
+	limits = limits.withDefaults()
 
-	return &netsqliteServer{
-		validTokens: tokens,
+	s := &netsqliteServer{
 		dbManager:   manager,
+		txs:         newTxRegistry(limits.TxIdleTimeout, limits.WriteTxIdleTimeout),
+		stmts:       newStmtRegistry(limits.SessionIdleTimeout),
+		cursors:     newCursorRegistry(limits.CursorIdleTimeout, limits.WriteTxIdleTimeout),
+		stopJanitor: make(chan struct{}),
 	}
+	go s.janitor(limits.janitorInterval())
+
+	return s
+}
+
+// janitor periodically cleans up server side state abandoned by clients.
+func (s *netsqliteServer) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			s.txs.reap(now)
+			s.stmts.reap(now)
+			s.cursors.reap(now)
+		case <-s.stopJanitor:
+			return
+		}
+	}
+}
+
+// Close stops background work, rolls back any transaction still open and
+// closes every prepared statement and cursor.
+// It must only be called once the gRPC server has stopped serving.
+func (s *netsqliteServer) Close() {
+	close(s.stopJanitor)
+	s.cursors.closeAll()
+	s.stmts.closeAll()
+	s.txs.closeAll()
 }
 
 // --- Service Method Implementations ---
 func (s *netsqliteServer) Ping(ctx context.Context, req *pb.PingRequest) (*pb.PingResponse, error) {
 	log.Println("Received Ping request")
-	pool, err := s.dbManager.AcquirePool(req.DatabaseName)
-	if err != nil {
-		return nil, err
-	}
-
-	db, err := pool.Acquire(ctx)
+	lease, err := s.dbManager.Acquire(ctx, req.DatabaseName, nsqlite.Read)
 	if err != nil {
 		return nil, err
 	}
+	defer lease.Release()
 
-	if err := db.Value().PingContext(ctx); err != nil {
+	if err := lease.Conn().PingContext(ctx); err != nil {
 		log.Printf("Actual DB Ping failed for %s: %v", req.DatabaseName, err)
-		return nil, status.Errorf(codes.Internal, "database ping failed for %s: %v", req.DatabaseName, err)
+		return nil, sqlError(lease.Check(err), "database ping failed for "+req.DatabaseName)
 	}
 
 	return &pb.PingResponse{Message: fmt.Sprintf("PONG for db %s", req.DatabaseName)}, nil
 }
 
 func (s *netsqliteServer) Exec(ctx context.Context, req *pb.ExecRequest) (*pb.ExecResponse, error) {
-	pool, err := s.dbManager.AcquirePool(req.DatabaseName)
-	if err != nil {
+	if err := requireWrite(ctx); err != nil {
 		return nil, err
 	}
 
-	db, err := pool.Acquire(ctx)
+	args, err := argsFromProto(req.Args)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert protobuf Value args to []any
-	args := make([]any, len(req.Args))
-	for i, val := range req.Args {
-		args[i] = val.AsInterface()
+	var db execQueryer
+	check := func(err error) error { return err }
+	if req.TransactionId != "" {
+		tx, err := s.txs.acquire(ctx, req.DatabaseName, req.TransactionId)
+		if err != nil {
+			return nil, err
+		}
+		defer s.txs.release(tx)
+		db = tx.conn
+	} else {
+		// Waits for the writer, behind any other write to the database
+		lease, err := s.dbManager.Acquire(ctx, req.DatabaseName, nsqlite.Write)
+		if err != nil {
+			return nil, err
+		}
+		defer lease.Release()
+		db, check = lease.Conn(), lease.Check
 	}
 
-	sqlResult, err := db.Value().ExecContext(ctx, req.Sql, args...)
+	sqlResult, err := db.ExecContext(ctx, req.Sql, args...)
 	if err != nil {
-		// TODO: Map SQLite errors to gRPC codes more specifically
-		return nil, status.Errorf(codes.Internal, "SQL execution failed: %v", err)
+		return nil, sqlError(check(err), "SQL execution failed")
 	}
 
 	// TODO: don't ignore error
@@ -95,94 +144,310 @@ func (s *netsqliteServer) Exec(ctx context.Context, req *pb.ExecRequest) (*pb.Ex
 }
 
 func (s *netsqliteServer) Query(req *pb.QueryRequest, stream pb.NetsqliteService_QueryServer) error {
-	pool, err := s.dbManager.AcquirePool(req.DatabaseName)
+	args, err := argsFromProto(req.Args)
 	if err != nil {
 		return err
 	}
 
-	db, err := pool.Acquire(stream.Context())
+	var db execQueryer
+	check := func(err error) error { return err }
+	if req.TransactionId != "" {
+		tx, err := s.txs.acquire(stream.Context(), req.DatabaseName, req.TransactionId)
+		if err != nil {
+			return err
+		}
+		// Held until the whole result set has been streamed
+		defer s.txs.release(tx)
+		db = tx.conn
+	} else {
+		// Held until the whole result set has been streamed, or the client
+		// goes away and the stream context is canceled
+		lease, err := s.queryLease(stream.Context(), req.DatabaseName, req.Sql)
+		if err != nil {
+			return err
+		}
+		defer lease.Release()
+		db, check = lease.Conn(), lease.Check
+	}
+
+	rows, err := db.QueryContext(stream.Context(), req.Sql, args...)
 	if err != nil {
-		return err
+		log.Printf("Query failed for DB '%s': %v", req.DatabaseName, err)
+		return sqlError(check(err), "SQL query failed")
 	}
+	defer rows.Close() // Ensure rows are closed
+
+	return streamRows(stream, rows, req.DatabaseName, req.Batch)
+}
 
-	// Convert protobuf Value args to []any
-	args := make([]any, len(req.Args))
-	for i, val := range req.Args {
-		args[i] = val.AsInterface()
+func (s *netsqliteServer) OpenCursor(ctx context.Context, req *pb.OpenCursorRequest) (*pb.OpenCursorResponse, error) {
+	args, err := argsFromProto(req.Args)
+	if err != nil {
+		return nil, err
 	}
 
-	rows, err := db.Value().QueryContext(stream.Context(), req.Sql, args...)
+	lease, err := s.queryLease(ctx, req.DatabaseName, req.Sql)
 	if err != nil {
-		log.Printf("Query failed for DB '%s': %v", req.DatabaseName, err)
-		return status.Errorf(codes.Internal, "SQL query failed: %v", err)
+		return nil, err
 	}
-	defer rows.Close() // Ensure rows are closed
 
-	// 1. Get column names and send first message
-	columns, err := rows.Columns()
+	// The cursor owns the lease from here on
+	cur, columns, err := s.cursors.open(ctx, lease, req.DatabaseName, req.Sql, args)
 	if err != nil {
-		log.Printf("Failed to get columns for DB '%s': %v", req.DatabaseName, err)
-		return status.Errorf(codes.Internal, "failed to get columns: %v", err)
+		log.Printf("OpenCursor failed for DB '%s': %v", req.DatabaseName, err)
+		return nil, err
 	}
 
-	columnResp := &pb.QueryResponse{
-		Result: &pb.QueryResponse_Columns{
-			Columns: &pb.Columns{Names: columns},
-		},
+	slog.Info("Cursor opened", "db", req.DatabaseName, "cursor", cur.id)
+	return &pb.OpenCursorResponse{CursorId: cur.id, Columns: columns}, nil
+}
+
+func (s *netsqliteServer) FetchCursor(ctx context.Context, req *pb.FetchCursorRequest) (*pb.FetchCursorResponse, error) {
+	cur, err := s.cursors.get(req.DatabaseName, req.CursorId)
+	if err != nil {
+		return nil, err
 	}
-	if err := stream.Send(columnResp); err != nil {
-		log.Printf("Failed to send columns to client: %v", err)
-		return status.Errorf(codes.Internal, "failed to send columns: %v", err)
+
+	maxRows, _ := batchLimits(&pb.BatchOptions{MaxRows: req.MaxRows})
+	resp, err := cur.fetch(maxRows, req.Sequence)
+	if err != nil {
+		log.Printf("FetchCursor failed for DB '%s': %v", req.DatabaseName, err)
+		return nil, err
 	}
-	log.Printf("Sent columns for query on DB '%s': %v", req.DatabaseName, columns)
+	return resp, nil
+}
+
+func (s *netsqliteServer) CloseCursor(ctx context.Context, req *pb.CloseCursorRequest) (*pb.CloseCursorResponse, error) {
+	if err := s.cursors.close(req.DatabaseName, req.CursorId); err != nil {
+		return nil, err
+	}
+	return &pb.CloseCursorResponse{}, nil
+}
+
+func (s *netsqliteServer) BeginTx(ctx context.Context, req *pb.BeginTxRequest) (*pb.BeginTxResponse, error) {
+	// Read-only principals only ever get read-only transactions, which run
+	// on a read-only connection. Any other holds the writer until it ends,
+	// whatever its mode, so it's rolled back after WriteTxIdleTimeout idle.
+	txReadOnly := req.ReadOnly || readOnly(ctx)
+	access := nsqlite.Write
+	if txReadOnly {
+		access = nsqlite.Read
+	}
+	lease, err := s.dbManager.Acquire(ctx, req.DatabaseName, access)
+	if err != nil {
+		return nil, err
+	}
+
+	// The transaction owns the lease from here on
+	tx, err := s.txs.begin(ctx, lease, access, req.DatabaseName, req.Mode)
+	if err != nil {
+		log.Printf("BeginTx failed for DB '%s': %v", req.DatabaseName, err)
+		return nil, err
+	}
+
+	slog.Info("Transaction started", "db", req.DatabaseName, "tx", tx.id, "mode", req.Mode, "read_only", txReadOnly)
+	return &pb.BeginTxResponse{TransactionId: tx.id}, nil
+}
+
+func (s *netsqliteServer) Commit(ctx context.Context, req *pb.CommitRequest) (*pb.CommitResponse, error) {
+	if err := s.txs.finish(ctx, req.DatabaseName, req.TransactionId, true); err != nil {
+		log.Printf("Commit failed for DB '%s': %v", req.DatabaseName, err)
+		return nil, err
+	}
+	return &pb.CommitResponse{}, nil
+}
+
+func (s *netsqliteServer) Rollback(ctx context.Context, req *pb.RollbackRequest) (*pb.RollbackResponse, error) {
+	if err := s.txs.finish(ctx, req.DatabaseName, req.TransactionId, false); err != nil {
+		log.Printf("Rollback failed for DB '%s': %v", req.DatabaseName, err)
+		return nil, err
+	}
+	return &pb.RollbackResponse{}, nil
+}
 
-	// 2. Stream rows
-	// Prepare a slice of pointers for Scan (needed for handling NULLs properly)
-	colCount := len(columns)
-	values := make([]any, colCount)
-	scanArgs := make([]any, colCount)
-	for i := range values {
-		scanArgs[i] = &values[i]
+func (s *netsqliteServer) Prepare(ctx context.Context, req *pb.PrepareRequest) (*pb.PrepareResponse, error) {
+	if req.SessionId == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id is required")
 	}
 
-	for rows.Next() {
-		if err := rows.Scan(scanArgs...); err != nil {
-			log.Printf("Failed to scan row for DB '%s': %v", req.DatabaseName, err)
-			return status.Errorf(codes.Internal, "failed to scan row: %v", err)
+	var conn *sql.Conn
+	if req.TransactionId != "" {
+		// The statement may refer to tables only visible inside the transaction
+		tx, err := s.txs.acquire(ctx, req.DatabaseName, req.TransactionId)
+		if err != nil {
+			return nil, err
 		}
+		defer s.txs.release(tx)
+		conn = tx.conn
+	} else {
+		// Describing the statement never steps it, so it can't write
+		lease, err := s.dbManager.Acquire(ctx, req.DatabaseName, nsqlite.Read)
+		if err != nil {
+			return nil, err
+		}
+		defer lease.Release()
+		conn = lease.Conn()
+	}
+
+	stmt, err := describeStatement(conn, req.Sql)
+	if err != nil {
+		log.Printf("Prepare failed for DB '%s': %v", req.DatabaseName, err)
+		return nil, sqlError(err, "SQL prepare failed")
+	}
+	stmt.dbName = req.DatabaseName
+	stmt.maxConns = int(s.dbManager.SettingsFor(req.DatabaseName).PoolSize) + 1
 
-		protoValues := make([]*structpb.Value, colCount)
-		for i, v := range values {
-			protoValues[i], err = structpb.NewValue(v)
-			if err != nil {
-				log.Printf("Failed to convert value to protobuf Value: %v", err)
-				return status.Errorf(codes.Internal, "failed to convert value: %v", err)
-			}
+	if err := s.stmts.add(req.SessionId, stmt); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Statement prepared", "db", req.DatabaseName, "stmt", stmt.id, "inputs", stmt.numInput)
+	return &pb.PrepareResponse{
+		StatementId: stmt.id,
+		NumInput:    int32(stmt.numInput),
+		Columns:     stmt.columns,
+		ReadOnly:    stmt.readOnly,
+	}, nil
+}
+
+// stmtFor finds a session's statement and readies it to run either inside
+// the given transaction or on a leased connection, the writer if the
+// statement writes. release must be called once the statement (and any rows
+// it returned) is done, with the error it ended with if any.
+func (s *netsqliteServer) stmtFor(ctx context.Context, dbName, sessionID, stmtID, txID string) (st *sql.Stmt, release func(error), err error) {
+	stmt, err := s.stmts.get(sessionID, dbName, stmtID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if txID != "" {
+		tx, err := s.txs.acquire(ctx, dbName, txID)
+		if err != nil {
+			return nil, nil, err
+		}
+		st, err := tx.prepared(ctx, stmt)
+		if err != nil {
+			s.txs.release(tx)
+			return nil, nil, sqlError(err, "SQL prepare failed")
 		}
+		return st, func(error) { s.txs.release(tx) }, nil
+	}
 
-		rowResp := &pb.QueryResponse{
-			Result: &pb.QueryResponse_Row{
-				Row: &pb.Row{Values: protoValues},
-			},
+	access := nsqlite.Write
+	if stmt.readOnly {
+		access = nsqlite.Read
+	}
+	lease, err := s.dbManager.Acquire(ctx, dbName, access)
+	if err != nil {
+		return nil, nil, err
+	}
+	st, err = stmt.on(ctx, lease.Conn())
+	if err != nil {
+		err = lease.Check(err)
+		lease.Release()
+		if status.Code(err) == codes.NotFound {
+			return nil, nil, err
+		}
+		return nil, nil, sqlError(err, "SQL prepare failed")
+	}
+	conn := lease.Conn()
+	return st, func(err error) {
+		if connGone(lease.Check(err)) {
+			stmt.forget(conn)
 		}
+		lease.Release()
+	}, nil
+}
+
+// queryLease leases a read-only connection to run query on, or the writer
+// if query writes (e.g. INSERT ... RETURNING). Read-only principals always
+// get a read-only connection, which refuses the write.
+func (s *netsqliteServer) queryLease(ctx context.Context, dbName, query string) (*nsqlite.Lease, error) {
+	lease, err := s.dbManager.Acquire(ctx, dbName, nsqlite.Read)
+	if err != nil || readOnly(ctx) {
+		return lease, err
+	}
+
+	writes, err := writesStatement(lease.Conn(), query)
+	if err != nil {
+		err = lease.Check(err)
+		lease.Release()
+		return nil, sqlError(err, "SQL query failed")
+	}
+	if !writes {
+		return lease, nil
+	}
+	lease.Release()
+	return s.dbManager.Acquire(ctx, dbName, nsqlite.Write)
+}
+
+func (s *netsqliteServer) ExecPrepared(ctx context.Context, req *pb.ExecPreparedRequest) (*pb.ExecResponse, error) {
+	if err := requireWrite(ctx); err != nil {
+		return nil, err
+	}
+
+	args, err := argsFromProto(req.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	st, release, err := s.stmtFor(ctx, req.DatabaseName, req.SessionId, req.StatementId, req.TransactionId)
+	if err != nil {
+		return nil, err
+	}
 
-		if err := stream.Send(rowResp); err != nil {
-			return status.Errorf(codes.Internal, "failed to send row: %v", err)
+	sqlResult, err := st.ExecContext(ctx, args...)
+	release(err)
+	if err != nil {
+		return nil, sqlError(err, "SQL execution failed")
+	}
+
+	rowsAffected, _ := sqlResult.RowsAffected()
+	lastInsertId, _ := sqlResult.LastInsertId()
+
+	return &pb.ExecResponse{
+		RowsAffected: rowsAffected,
+		LastInsertId: lastInsertId,
+	}, nil
+}
+
+func (s *netsqliteServer) QueryPrepared(req *pb.QueryPreparedRequest, stream pb.NetsqliteService_QueryPreparedServer) error {
+	args, err := argsFromProto(req.Args)
+	if err != nil {
+		return err
+	}
+
+	if readOnly(stream.Context()) {
+		// SQLite knows whether the statement writes, e.g. INSERT ... RETURNING
+		stmt, err := s.stmts.get(req.SessionId, req.DatabaseName, req.StatementId)
+		if err != nil {
+			return err
 		}
-		// Check context cancellation frequently during long streams
-		select {
-		case <-stream.Context().Done():
-			log.Printf("Client disconnected during query stream for DB '%s'", req.DatabaseName)
-			return status.Error(codes.Canceled, "client disconnected")
-		default:
+		if !stmt.readOnly {
+			return status.Error(codes.PermissionDenied, "read-only token can't run a statement that writes")
 		}
-	} // end rows.Next() loop
+	}
 
-	if err := rows.Err(); err != nil {
-		log.Printf("Error during row iteration for DB '%s': %v", req.DatabaseName, err)
-		return status.Errorf(codes.Internal, "row iteration error: %v", err)
+	st, release, err := s.stmtFor(stream.Context(), req.DatabaseName, req.SessionId, req.StatementId, req.TransactionId)
+	if err != nil {
+		return err
+	}
+
+	rows, err := st.QueryContext(stream.Context(), args...)
+	if err != nil {
+		release(err)
+		log.Printf("Prepared query failed for DB '%s': %v", req.DatabaseName, err)
+		return sqlError(err, "SQL query failed")
 	}
+	err = streamRows(stream, rows, req.DatabaseName, req.Batch)
+	rows.Close()
+	release(rows.Err())
+	return err
+}
 
-	log.Printf("Finished streaming query results for DB '%s'", req.DatabaseName)
-	return nil
+func (s *netsqliteServer) CloseStmt(ctx context.Context, req *pb.CloseStmtRequest) (*pb.CloseStmtResponse, error) {
+	if err := s.stmts.remove(req.SessionId, req.DatabaseName, req.StatementId); err != nil {
+		return nil, err
+	}
+	return &pb.CloseStmtResponse{}, nil
 }
@@ -0,0 +1,290 @@
+package proto
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultSessionIdleTimeout is how long a client session can go without using
+// any of its prepared statements before they are all closed. The driver
+// re-prepares transparently if it comes back later.
+const defaultSessionIdleTimeout = 10 * time.Minute
+
+// preparedStmt is a statement prepared by a client session. The matching
+// *sql.Stmt is prepared lazily on each leased connection it ends up running on.
+type preparedStmt struct {
+	id       string
+	dbName   string
+	query    string
+	numInput int
+	columns  []string
+	readOnly bool
+
+	// maxConns caps byConn, the database only has that many connections at
+	// once. Connections closed since, when discarded or when the database
+	// is evicted, are never used again so they are the first dropped.
+	maxConns int
+
+	mu     sync.Mutex
+	byConn map[*sql.Conn]*connStmt
+	closed bool
+}
+
+// connStmt is a preparedStmt prepared on one connection.
+type connStmt struct {
+	st       *sql.Stmt
+	lastUsed time.Time
+}
+
+// on returns the statement prepared on conn, preparing it the first time.
+func (p *preparedStmt) on(ctx context.Context, conn *sql.Conn) (*sql.Stmt, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, status.Errorf(codes.NotFound, "statement %s was closed", p.id)
+	}
+	if cs, ok := p.byConn[conn]; ok {
+		cs.lastUsed = time.Now()
+		return cs.st, nil
+	}
+	st, err := conn.PrepareContext(ctx, p.query)
+	if err != nil {
+		return nil, err
+	}
+	if p.maxConns > 0 && len(p.byConn) >= p.maxConns {
+		p.dropLeastUsed()
+	}
+	p.byConn[conn] = &connStmt{st: st, lastUsed: time.Now()}
+	return st, nil
+}
+
+// dropLeastUsed closes the statement on the connection used the longest ago,
+// must be called with mu held.
+func (p *preparedStmt) dropLeastUsed() {
+	var oldest *sql.Conn
+	for conn, cs := range p.byConn {
+		if oldest == nil || cs.lastUsed.Before(p.byConn[oldest].lastUsed) {
+			oldest = conn
+		}
+	}
+	if oldest != nil {
+		// Most likely closed along with its connection already
+		p.closeOn(oldest)
+	}
+}
+
+// forget closes the statement on conn, once conn turned out to be closed.
+func (p *preparedStmt) forget(conn *sql.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closeOn(conn)
+}
+
+// closeOn must be called with mu held.
+func (p *preparedStmt) closeOn(conn *sql.Conn) error {
+	cs, ok := p.byConn[conn]
+	if !ok {
+		return nil
+	}
+	delete(p.byConn, conn)
+	return cs.st.Close()
+}
+
+func (p *preparedStmt) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	for conn := range p.byConn {
+		if err := p.closeOn(conn); err != nil {
+			log.Printf("Failed to close statement %s: %v", p.id, err)
+		}
+	}
+}
+
+// connGone reports whether err means the connection it came from is closed,
+// or about to be.
+func connGone(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone)
+}
+
+// describeStatement prepares query on the raw SQLite connection to find out its
+// parameter count and result columns. The statement is never stepped, so
+// nothing is executed.
+func describeStatement(conn *sql.Conn, query string) (*preparedStmt, error) {
+	stmt := &preparedStmt{
+		query:  query,
+		byConn: make(map[*sql.Conn]*connStmt),
+	}
+
+	err := conn.Raw(func(driverConn any) error {
+		sc, ok := driverConn.(*sqlite3.SQLiteConn)
+		if !ok {
+			return fmt.Errorf("unexpected driver connection %T", driverConn)
+		}
+
+		ds, err := sc.Prepare(query)
+		if err != nil {
+			return err
+		}
+		defer ds.Close()
+
+		st := ds.(*sqlite3.SQLiteStmt)
+		stmt.numInput = st.NumInput()
+		stmt.readOnly = st.Readonly()
+
+		// Binding NULLs and asking for the columns doesn't step the statement
+		rows, err := st.Query(make([]driver.Value, stmt.numInput))
+		if err != nil {
+			return err
+		}
+		stmt.columns = rows.Columns()
+		return rows.Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// writesStatement reports whether query writes to the database, preparing
+// it on the raw SQLite connection without stepping it.
+func writesStatement(conn *sql.Conn, query string) (bool, error) {
+	var writes bool
+	err := conn.Raw(func(driverConn any) error {
+		sc, ok := driverConn.(*sqlite3.SQLiteConn)
+		if !ok {
+			return fmt.Errorf("unexpected driver connection %T", driverConn)
+		}
+
+		ds, err := sc.Prepare(query)
+		if err != nil {
+			return err
+		}
+		defer ds.Close()
+		writes = !ds.(*sqlite3.SQLiteStmt).Readonly()
+		return nil
+	})
+	return writes, err
+}
+
+type stmtSession struct {
+	stmts    map[string]*preparedStmt
+	lastUsed time.Time
+}
+
+// stmtRegistry holds the prepared statements of every client session.
+type stmtRegistry struct {
+	mu          sync.Mutex
+	sessions    map[string]*stmtSession
+	idleTimeout time.Duration
+}
+
+func newStmtRegistry(idleTimeout time.Duration) *stmtRegistry {
+	return &stmtRegistry{
+		sessions:    make(map[string]*stmtSession),
+		idleTimeout: idleTimeout,
+	}
+}
+
+func (r *stmtRegistry) add(sessionID string, stmt *preparedStmt) error {
+	id, err := newID()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to generate statement id: %v", err)
+	}
+	stmt.id = id
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sess, ok := r.sessions[sessionID]
+	if !ok {
+		sess = &stmtSession{stmts: make(map[string]*preparedStmt)}
+		r.sessions[sessionID] = sess
+	}
+	sess.stmts[id] = stmt
+	sess.lastUsed = time.Now()
+	return nil
+}
+
+func (r *stmtRegistry) get(sessionID, dbName, id string) (*preparedStmt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sess, ok := r.sessions[sessionID]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "statement %s not found (closed or expired)", id)
+	}
+	stmt, ok := sess.stmts[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "statement %s not found (closed or expired)", id)
+	}
+	if stmt.dbName != dbName {
+		return nil, status.Errorf(codes.InvalidArgument, "statement %s does not belong to database %s", id, dbName)
+	}
+	sess.lastUsed = time.Now()
+	return stmt, nil
+}
+
+func (r *stmtRegistry) remove(sessionID, dbName, id string) error {
+	stmt, err := r.get(sessionID, dbName, id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	if sess, ok := r.sessions[sessionID]; ok {
+		delete(sess.stmts, id)
+		if len(sess.stmts) == 0 {
+			delete(r.sessions, sessionID)
+		}
+	}
+	r.mu.Unlock()
+
+	stmt.close()
+	return nil
+}
+
+// reap closes the statements of every session idle for longer than the idle timeout.
+func (r *stmtRegistry) reap(now time.Time) {
+	r.mu.Lock()
+	var expired []*stmtSession
+	for id, sess := range r.sessions {
+		if now.Sub(sess.lastUsed) > r.idleTimeout {
+			delete(r.sessions, id)
+			expired = append(expired, sess)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, sess := range expired {
+		for _, stmt := range sess.stmts {
+			stmt.close()
+		}
+	}
+}
+
+// closeAll closes every prepared statement, used on shutdown.
+func (r *stmtRegistry) closeAll() {
+	r.mu.Lock()
+	sessions := r.sessions
+	r.sessions = make(map[string]*stmtSession)
+	r.mu.Unlock()
+
+	for _, sess := range sessions {
+		for _, stmt := range sess.stmts {
+			stmt.close()
+		}
+	}
+}
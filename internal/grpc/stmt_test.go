@@ -0,0 +1,52 @@
+package proto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreparedStmt_DropsClosedConns(t *testing.T) {
+	m := nsqlite.NewManager(t.TempDir(), nsqlite.WithSettings(nsqlite.Settings{PoolSize: 1}, nil))
+	defer m.Close(context.Background())
+	ctx := context.Background()
+
+	lease, err := m.Acquire(ctx, "stmt.db", nsqlite.Read)
+	require.NoError(t, err)
+	stmt, err := describeStatement(lease.Conn(), "SELECT 1")
+	lease.Release()
+	require.NoError(t, err)
+	stmt.maxConns = 2
+	defer stmt.close()
+
+	// Every discarded connection is replaced by a new one, the statement
+	// stays prepared on as many as the database can have at once
+	for range 5 {
+		lease, err := m.Acquire(ctx, "stmt.db", nsqlite.Read)
+		require.NoError(t, err)
+		st, err := stmt.on(ctx, lease.Conn())
+		require.NoError(t, err)
+		var n int
+		require.NoError(t, st.QueryRowContext(ctx).Scan(&n))
+		lease.Discard()
+		lease.Release()
+	}
+	assert.Len(t, stmt.byConn, 2)
+
+	// Running on a closed connection forgets it right away
+	lease, err = m.Acquire(ctx, "stmt.db", nsqlite.Read)
+	require.NoError(t, err)
+	conn := lease.Conn()
+	st, err := stmt.on(ctx, conn)
+	require.NoError(t, err)
+	lease.Discard()
+	lease.Release()
+	_, err = st.QueryContext(ctx)
+	require.True(t, connGone(err), "got %v", err)
+	stmt.forget(conn)
+	_, cached := stmt.byConn[conn]
+	assert.False(t, cached)
+}
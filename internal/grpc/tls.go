@@ -0,0 +1,75 @@
+package proto
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// TLSConfig enables TLS on the server. Without a certificate the server
+// speaks plaintext.
+type TLSConfig struct {
+	CertFile string // PEM certificate chain of the server
+	KeyFile  string // PEM private key of CertFile
+
+	// ClientCAFile holds the PEM CAs client certificates are verified
+	// against. Verified certificates can stand in for tokens, see
+	// auth.CertIdentities.
+	ClientCAFile string
+	// RequireClientCert rejects clients without a certificate signed by
+	// ClientCAFile (mutual TLS)
+	RequireClientCert bool
+}
+
+// Enabled reports whether the server should serve TLS.
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+// Credentials loads the certificates into gRPC transport credentials.
+func (c TLSConfig) Credentials() (credentials.TransportCredentials, error) {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, errors.New("TLS needs both a certificate and a key")
+	}
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading TLS certificate: %w", err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	switch {
+	case c.ClientCAFile != "":
+		pool, err := loadCertPool(c.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+		if c.RequireClientCert {
+			cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	case c.RequireClientCert:
+		return nil, errors.New("requiring client certificates needs a client CA")
+	}
+	return credentials.NewTLS(cfg), nil
+}
+
+// loadCertPool reads the PEM certificates in path.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,196 @@
+package proto_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alfredosa/netsqlite/internal/auth"
+	proto "github.com/alfredosa/netsqlite/internal/grpc"
+	"github.com/alfredosa/netsqlite/pkg/drivers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testCA signs certificates for the TLS tests.
+type testCA struct {
+	dir  string
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	file string // PEM of cert
+}
+
+func newTestCA(t *testing.T, dir, name string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	ca := &testCA{dir: dir, cert: cert, key: key, file: filepath.Join(dir, name+".pem")}
+	writePEM(t, ca.file, "CERTIFICATE", der)
+	return ca
+}
+
+// issue writes a certificate and key signed by the CA, returning their paths.
+func (ca *testCA) issue(t *testing.T, name string, tmpl *x509.Certificate) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	tmpl.SerialNumber = serial
+	tmpl.NotBefore = time.Now().Add(-time.Hour)
+	tmpl.NotAfter = time.Now().Add(time.Hour)
+	tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(ca.dir, name+".pem")
+	keyFile := filepath.Join(ca.dir, name+"-key.pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600))
+}
+
+func serverCert(t *testing.T, ca *testCA) (string, string) {
+	return ca.issue(t, "server", &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "netsqlite"},
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+}
+
+func clientCert(t *testing.T, ca *testCA, name string, dnsNames ...string) (string, string) {
+	return ca.issue(t, name, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: name},
+		DNSNames:    dnsNames,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+}
+
+func Test_TLS(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	ca := newTestCA(t, t.TempDir(), "ca")
+	certFile, keyFile := serverCert(t, ca)
+	addr, token, dir := startServer(ctx, t, proto.Config{AutoCreate: true, TLS: proto.TLSConfig{CertFile: certFile, KeyFile: keyFile}})
+	defer os.RemoveAll(dir)
+
+	db, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/%s?database=tls&tls=true&ca=%s", addr, token, ca.file))
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.PingContext(ctx))
+
+	// The server name can differ from the dialed address
+	named, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/%s?database=tls-named&tls=true&ca=%s&servername=localhost", addr, token, ca.file))
+	require.NoError(t, err)
+	defer named.Close()
+	require.NoError(t, named.PingContext(ctx))
+
+	// Plaintext clients can't talk to a TLS server
+	plain, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/%s?database=tls-plain", addr, token))
+	require.NoError(t, err)
+	defer plain.Close()
+	assert.Error(t, plain.PingContext(ctx))
+
+	// Neither can clients not trusting its certificate
+	other := newTestCA(t, t.TempDir(), "other")
+	untrusted, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/%s?database=tls-untrusted&tls=true&ca=%s", addr, token, other.file))
+	require.NoError(t, err)
+	defer untrusted.Close()
+	assert.Error(t, untrusted.PingContext(ctx))
+}
+
+func Test_MutualTLS(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	ca := newTestCA(t, t.TempDir(), "ca")
+	certFile, keyFile := serverCert(t, ca)
+	addr, token, dir := startServer(ctx, t,
+		proto.Config{AutoCreate: true, TLS: proto.TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: ca.file, RequireClientCert: true}},
+		auth.Token{Name: "reporting", ClientCerts: []string{"DNS:reports.internal"}, Role: "read-only", Databases: []string{"reports"}},
+	)
+	defer os.RemoveAll(dir)
+
+	// Tokens still work alongside the client certificate
+	ownerCert, ownerKey := clientCert(t, ca, "owner")
+	owner, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/%s?database=reports&tls=true&ca=%s&cert=%s&key=%s", addr, token, ca.file, ownerCert, ownerKey))
+	require.NoError(t, err)
+	defer owner.Close()
+	ownerConn, err := owner.Conn(ctx)
+	require.NoError(t, err)
+	defer ownerConn.Close()
+	_, err = drivers.ExecBatch(ctx, ownerConn, []drivers.Statement{
+		{SQL: `CREATE TABLE reports (id INTEGER PRIMARY KEY, title TEXT)`},
+		{SQL: `INSERT INTO reports (title) VALUES ('q1')`},
+	}, drivers.BatchOptions{})
+	require.NoError(t, err)
+
+	// The certificate alone authenticates, with the permissions it was granted
+	reportsCert, reportsKey := clientCert(t, ca, "reports", "reports.internal")
+	reports, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/?database=reports&tls=true&ca=%s&cert=%s&key=%s", addr, ca.file, reportsCert, reportsKey))
+	require.NoError(t, err)
+	defer reports.Close()
+	reportsConn, err := reports.Conn(ctx)
+	require.NoError(t, err)
+	defer reportsConn.Close()
+
+	var title string
+	require.NoError(t, reportsConn.QueryRowContext(ctx, `SELECT title FROM reports`).Scan(&title))
+	assert.Equal(t, "q1", title)
+	_, err = reportsConn.ExecContext(ctx, `DELETE FROM reports`)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Only for the databases it was granted
+	elsewhere, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/?database=other&tls=true&ca=%s&cert=%s&key=%s", addr, ca.file, reportsCert, reportsKey))
+	require.NoError(t, err)
+	defer elsewhere.Close()
+	assert.Equal(t, codes.PermissionDenied, status.Code(elsewhere.PingContext(ctx)))
+
+	// Certificates nobody was granted don't authenticate
+	strangerCert, strangerKey := clientCert(t, ca, "stranger")
+	stranger, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/?database=reports-stranger&tls=true&ca=%s&cert=%s&key=%s", addr, ca.file, strangerCert, strangerKey))
+	require.NoError(t, err)
+	defer stranger.Close()
+	assert.Equal(t, codes.Unauthenticated, status.Code(stranger.PingContext(ctx)))
+
+	// Clients without a certificate don't get through the handshake
+	anonymous, err := sql.Open("netsqlite", fmt.Sprintf("netsqlite://%s/%s?database=reports-anonymous&tls=true&ca=%s", addr, token, ca.file))
+	require.NoError(t, err)
+	defer anonymous.Close()
+	assert.Error(t, anonymous.PingContext(ctx))
+}
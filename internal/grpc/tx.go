@@ -0,0 +1,313 @@
+package proto
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/alfredosa/netsqlite/internal/auth"
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultTxIdleTimeout is how long a transaction can sit without any statement
+// before the reaper rolls it back. An abandoned transaction would otherwise
+// hold the SQLite write lock (and a pool slot) forever.
+const defaultTxIdleTimeout = 30 * time.Second
+
+// defaultWriteTxIdleTimeout is the same for transactions holding the writer
+// connection, shorter since every other write to the database waits for them.
+const defaultWriteTxIdleTimeout = 10 * time.Second
+
+// execQueryer is satisfied by *sql.DB and *sql.Conn, so statements can run
+// either on a leased connection or inside a pinned transaction.
+type execQueryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// transaction is a SQLite transaction holding a leased connection until it
+// is committed, rolled back or reaped.
+type transaction struct {
+	id     string
+	dbName string
+	owner  string // name of the token that began it, the only one that can use it
+	writer bool   // whether it holds the writer connection
+	lease  *nsqlite.Lease
+	conn   *sql.Conn
+
+	// sem serializes statements on the pinned connection, a query holds it
+	// until its stream is done.
+	sem      chan struct{}
+	lastUsed time.Time // guarded by sem
+
+	// stmts are prepared statements used inside the transaction, guarded by sem
+	stmts map[string]*sql.Stmt
+}
+
+// lock waits for the transaction to be free or for ctx to be done.
+func (tx *transaction) lock(ctx context.Context) error {
+	select {
+	case tx.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+func (tx *transaction) tryLock() bool {
+	select {
+	case tx.sem <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (tx *transaction) unlock() {
+	tx.lastUsed = time.Now()
+	<-tx.sem
+}
+
+// prepared returns p prepared on the pinned connection, must be called with sem held.
+func (tx *transaction) prepared(ctx context.Context, p *preparedStmt) (*sql.Stmt, error) {
+	if st, ok := tx.stmts[p.id]; ok {
+		return st, nil
+	}
+	st, err := tx.conn.PrepareContext(ctx, p.query)
+	if err != nil {
+		return nil, err
+	}
+	tx.stmts[p.id] = st
+	return st, nil
+}
+
+// end commits or rolls back the transaction and hands the connection back.
+// If the connection is left in an unknown state it is closed instead.
+func (tx *transaction) end(ctx context.Context, commit bool) error {
+	for id, st := range tx.stmts {
+		if err := st.Close(); err != nil {
+			log.Printf("Failed to close statement %s of tx %s: %v", id, tx.id, err)
+		}
+	}
+
+	stmt := "ROLLBACK"
+	if commit {
+		stmt = "COMMIT"
+	}
+	_, err := tx.conn.ExecContext(ctx, stmt)
+	if err != nil && commit {
+		// A failed COMMIT leaves the transaction open, so don't leak it.
+		if _, rbErr := tx.conn.ExecContext(context.Background(), "ROLLBACK"); rbErr != nil {
+			log.Printf("Rollback after failed commit of tx %s failed: %v", tx.id, rbErr)
+		}
+	}
+
+	if err != nil {
+		tx.lease.Discard()
+	}
+	tx.lease.Release()
+	return err
+}
+
+// txRegistry keeps track of every open transaction by its server issued ID.
+type txRegistry struct {
+	mu                sync.Mutex
+	txs               map[string]*transaction
+	idleTimeout       time.Duration
+	writerIdleTimeout time.Duration // of the transactions holding the writer
+}
+
+func newTxRegistry(idleTimeout, writerIdleTimeout time.Duration) *txRegistry {
+	return &txRegistry{
+		txs:               make(map[string]*transaction),
+		idleTimeout:       idleTimeout,
+		writerIdleTimeout: writerIdleTimeout,
+	}
+}
+
+// beginStatement returns the SQL to start a transaction in the given mode.
+func beginStatement(mode pb.TxMode) (string, error) {
+	switch mode {
+	case pb.TxMode_TX_MODE_DEFERRED:
+		return "BEGIN DEFERRED", nil
+	case pb.TxMode_TX_MODE_IMMEDIATE:
+		return "BEGIN IMMEDIATE", nil
+	case pb.TxMode_TX_MODE_EXCLUSIVE:
+		return "BEGIN EXCLUSIVE", nil
+	default:
+		return "", status.Errorf(codes.InvalidArgument, "unknown transaction mode %v", mode)
+	}
+}
+
+// begin starts a transaction on the leased connection, a read-only one for
+// read-only transactions. access is what the lease was acquired for. The
+// transaction owns the lease, which is released if begin fails.
+func (r *txRegistry) begin(ctx context.Context, lease *nsqlite.Lease, access nsqlite.Access, dbName string, mode pb.TxMode) (*transaction, error) {
+	beginSQL, err := beginStatement(mode)
+	if err != nil {
+		lease.Release()
+		return nil, err
+	}
+
+	id, err := newID()
+	if err != nil {
+		lease.Release()
+		return nil, status.Errorf(codes.Internal, "failed to generate transaction id: %v", err)
+	}
+
+	conn := lease.Conn()
+	if _, err := conn.ExecContext(ctx, beginSQL); err != nil {
+		lease.Discard()
+		lease.Release()
+		return nil, sqlError(err, "failed to begin transaction")
+	}
+
+	var owner string
+	if p, ok := auth.FromContext(ctx); ok {
+		owner = p.Name
+	}
+	tx := &transaction{
+		id:       id,
+		dbName:   dbName,
+		owner:    owner,
+		writer:   access == nsqlite.Write,
+		lease:    lease,
+		conn:     conn,
+		sem:      make(chan struct{}, 1),
+		lastUsed: time.Now(),
+		stmts:    make(map[string]*sql.Stmt),
+	}
+
+	r.mu.Lock()
+	r.txs[id] = tx
+	r.mu.Unlock()
+
+	return tx, nil
+}
+
+// get finds the transaction, which must belong to the principal of ctx:
+// otherwise anyone with access to the database, read-only tokens included,
+// could run statements in it or end it.
+func (r *txRegistry) get(ctx context.Context, dbName, id string) (*transaction, error) {
+	r.mu.Lock()
+	tx, ok := r.txs[id]
+	r.mu.Unlock()
+
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "transaction %s not found (committed, rolled back or expired)", id)
+	}
+	if tx.dbName != dbName {
+		return nil, status.Errorf(codes.InvalidArgument, "transaction %s does not belong to database %s", id, dbName)
+	}
+	var caller string
+	if p, ok := auth.FromContext(ctx); ok {
+		caller = p.Name
+	}
+	if tx.owner != caller {
+		return nil, status.Errorf(codes.PermissionDenied, "transaction %s was begun by another token", id)
+	}
+	return tx, nil
+}
+
+// acquire locks the transaction for a single statement, callers must release it.
+func (r *txRegistry) acquire(ctx context.Context, dbName, id string) (*transaction, error) {
+	tx, err := r.get(ctx, dbName, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.lock(ctx); err != nil {
+		return nil, err
+	}
+
+	// It might have been finished while we were waiting
+	if _, err := r.get(ctx, dbName, id); err != nil {
+		tx.unlock()
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (r *txRegistry) release(tx *transaction) {
+	tx.unlock()
+}
+
+// finish commits or rolls back the transaction and forgets about it.
+func (r *txRegistry) finish(ctx context.Context, dbName, id string, commit bool) error {
+	tx, err := r.acquire(ctx, dbName, id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	delete(r.txs, id)
+	r.mu.Unlock()
+
+	defer tx.unlock()
+	if err := tx.end(ctx, commit); err != nil {
+		return status.Errorf(codes.Aborted, "transaction %s failed to end: %v", id, err)
+	}
+	return nil
+}
+
+// reap rolls back every transaction that has been idle for longer than its
+// idle timeout.
+func (r *txRegistry) reap(now time.Time) {
+	r.mu.Lock()
+	var expired []*transaction
+	for id, tx := range r.txs {
+		// Skip transactions that are running a statement
+		if !tx.tryLock() {
+			continue
+		}
+		timeout := r.idleTimeout
+		if tx.writer {
+			timeout = r.writerIdleTimeout
+		}
+		if now.Sub(tx.lastUsed) > timeout {
+			delete(r.txs, id)
+			expired = append(expired, tx)
+			continue
+		}
+		<-tx.sem // not tx.unlock(), checking it doesn't count as using it
+	}
+	r.mu.Unlock()
+
+	for _, tx := range expired {
+		log.Printf("Rolling back idle transaction %s on DB '%s'", tx.id, tx.dbName)
+		if err := tx.end(context.Background(), false); err != nil {
+			log.Printf("Failed to roll back idle transaction %s: %v", tx.id, err)
+		}
+		<-tx.sem
+	}
+}
+
+// closeAll rolls back every open transaction, used on shutdown.
+func (r *txRegistry) closeAll() {
+	r.mu.Lock()
+	txs := r.txs
+	r.txs = make(map[string]*transaction)
+	r.mu.Unlock()
+
+	for _, tx := range txs {
+		if err := tx.end(context.Background(), false); err != nil {
+			log.Printf("Failed to roll back transaction %s on shutdown: %v", tx.id, err)
+		}
+	}
+}
+
+// newID returns a random, unguessable identifier for server side handles.
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,94 @@
+package proto
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alfredosa/netsqlite/internal/auth"
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTxRegistry_ReapsIdleTransactions(t *testing.T) {
+	dir, err := os.MkdirTemp("", "netsqlite-*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	m := nsqlite.NewManager(dir)
+	db, err := m.Open("reap.db")
+	require.NoError(t, err)
+	defer db.Close(context.Background())
+
+	ctx := context.Background()
+	txs := newTxRegistry(time.Minute, time.Second)
+
+	lease, err := m.Acquire(ctx, "reap.db", nsqlite.Write)
+	require.NoError(t, err)
+	idle, err := txs.begin(ctx, lease, nsqlite.Write, "reap.db", pb.TxMode_TX_MODE_IMMEDIATE)
+	require.NoError(t, err)
+	lease, err = m.Acquire(ctx, "reap.db", nsqlite.Read)
+	require.NoError(t, err)
+	busy, err := txs.begin(ctx, lease, nsqlite.Read, "reap.db", pb.TxMode_TX_MODE_DEFERRED)
+	require.NoError(t, err)
+
+	// Nothing is old enough yet
+	txs.reap(time.Now())
+	assert.Equal(t, nsqlite.Stats{Readers: 1, ReadersBusy: 1, MaxReaders: 5, WriterBusy: true}, db.Stats())
+
+	// The one holding the writer goes sooner
+	txs.reap(time.Now().Add(2 * time.Second))
+	_, err = txs.get(ctx, "reap.db", idle.id)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.False(t, db.Stats().WriterBusy)
+	_, err = txs.get(ctx, "reap.db", busy.id)
+	assert.NoError(t, err)
+
+	// A transaction running a statement is never reaped
+	inUse, err := txs.acquire(ctx, "reap.db", busy.id)
+	require.NoError(t, err)
+
+	txs.reap(time.Now().Add(time.Hour))
+	assert.Equal(t, int32(1), db.Stats().ReadersBusy)
+
+	txs.release(inUse)
+	_, err = txs.get(ctx, "reap.db", busy.id)
+	assert.NoError(t, err)
+
+	// Wrong database
+	_, err = txs.get(ctx, "other.db", busy.id)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	txs.closeAll()
+	assert.Equal(t, int32(0), db.Stats().ReadersBusy)
+}
+
+func TestTxRegistry_Owner(t *testing.T) {
+	m := nsqlite.NewManager(t.TempDir())
+	defer m.Close(context.Background())
+	txs := newTxRegistry(time.Minute, time.Minute)
+	defer txs.closeAll()
+
+	owner := auth.NewContext(context.Background(), &auth.Principal{Name: "app", Role: auth.RoleReadWrite})
+	other := auth.NewContext(context.Background(), &auth.Principal{Name: "reporting", Role: auth.RoleReadOnly})
+
+	lease, err := m.Acquire(owner, "owned.db", nsqlite.Write)
+	require.NoError(t, err)
+	tx, err := txs.begin(owner, lease, nsqlite.Write, "owned.db", pb.TxMode_TX_MODE_DEFERRED)
+	require.NoError(t, err)
+
+	// Another token can neither run statements in it nor end it
+	_, err = txs.acquire(other, "owned.db", tx.id)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, codes.PermissionDenied, status.Code(txs.finish(other, "owned.db", tx.id, true)))
+
+	inTx, err := txs.acquire(owner, "owned.db", tx.id)
+	require.NoError(t, err)
+	txs.release(inTx)
+	assert.NoError(t, txs.finish(owner, "owned.db", tx.id, false))
+}
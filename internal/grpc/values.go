@@ -0,0 +1,105 @@
+package proto
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// argsFromProto converts SqlValue args to the Go types go-sqlite3 binds.
+func argsFromProto(vals []*pb.SqlValue) ([]any, error) {
+	args := make([]any, len(vals))
+	for i, val := range vals {
+		switch v := val.GetValue().(type) {
+		case nil, *pb.SqlValue_NullValue:
+			args[i] = nil
+		case *pb.SqlValue_Int64Value:
+			args[i] = v.Int64Value
+		case *pb.SqlValue_DoubleValue:
+			args[i] = v.DoubleValue
+		case *pb.SqlValue_TextValue:
+			args[i] = v.TextValue
+		case *pb.SqlValue_BlobValue:
+			args[i] = v.BlobValue
+		case *pb.SqlValue_BoolValue:
+			args[i] = v.BoolValue
+		case *pb.SqlValue_TimestampValue:
+			if err := v.TimestampValue.CheckValid(); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid timestamp arg at index %d: %v", i, err)
+			}
+			args[i] = v.TimestampValue.AsTime()
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported arg type %T at index %d", v, i)
+		}
+	}
+	return args, nil
+}
+
+// sqlValue converts a value scanned by go-sqlite3 to its SqlValue.
+func sqlValue(v any) (*pb.SqlValue, error) {
+	switch v := v.(type) {
+	case nil:
+		return &pb.SqlValue{Value: &pb.SqlValue_NullValue{NullValue: structpb.NullValue_NULL_VALUE}}, nil
+	case int64:
+		return &pb.SqlValue{Value: &pb.SqlValue_Int64Value{Int64Value: v}}, nil
+	case float64:
+		return &pb.SqlValue{Value: &pb.SqlValue_DoubleValue{DoubleValue: v}}, nil
+	case string:
+		return &pb.SqlValue{Value: &pb.SqlValue_TextValue{TextValue: v}}, nil
+	case []byte:
+		return &pb.SqlValue{Value: &pb.SqlValue_BlobValue{BlobValue: v}}, nil
+	case bool:
+		return &pb.SqlValue{Value: &pb.SqlValue_BoolValue{BoolValue: v}}, nil
+	case time.Time:
+		return &pb.SqlValue{Value: &pb.SqlValue_TimestampValue{TimestampValue: timestamppb.New(v)}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported column value type %T", v)
+	}
+}
+
+// columnTypes describes the result columns of rows.
+func columnTypes(rows *sql.Rows) ([]*pb.ColumnType, error) {
+	cts, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	types := make([]*pb.ColumnType, len(cts))
+	for i, ct := range cts {
+		nullable, ok := ct.Nullable()
+		types[i] = &pb.ColumnType{
+			DatabaseTypeName: ct.DatabaseTypeName(),
+			Nullable:         nullable,
+			NullableKnown:    ok,
+			ScanType:         scanType(ct.ScanType()),
+		}
+	}
+	return types, nil
+}
+
+func scanType(t reflect.Type) pb.ScanType {
+	switch t {
+	case reflect.TypeFor[sql.NullInt64]():
+		return pb.ScanType_SCAN_TYPE_INT64
+	case reflect.TypeFor[sql.NullFloat64]():
+		return pb.ScanType_SCAN_TYPE_FLOAT64
+	case reflect.TypeFor[sql.NullString]():
+		return pb.ScanType_SCAN_TYPE_STRING
+	case reflect.TypeFor[sql.RawBytes]():
+		return pb.ScanType_SCAN_TYPE_BYTES
+	case reflect.TypeFor[sql.NullBool]():
+		return pb.ScanType_SCAN_TYPE_BOOL
+	case reflect.TypeFor[sql.NullTime]():
+		return pb.ScanType_SCAN_TYPE_TIME
+	default:
+		return pb.ScanType_SCAN_TYPE_ANY
+	}
+}
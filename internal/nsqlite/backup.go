@@ -0,0 +1,119 @@
+package nsqlite
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/mattn/go-sqlite3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateTemp creates a new file in the data directory, on the same
+// filesystem as the databases, see os.CreateTemp. pattern should start with
+// a '.' so the file is never taken for a database.
+func (s *DBManager) CreateTemp(pattern string) (*os.File, error) {
+	return os.CreateTemp(s.datadir, pattern)
+}
+
+// Backup copies the database named dbName to a new file at dst with the
+// SQLite online backup API. The copy is a consistent snapshot: it's taken in
+// a single read transaction, which in WAL mode doesn't hold up writers.
+// Taking it doesn't count as a use of the database, it can still be evicted
+// for idling.
+func (s *DBManager) Backup(ctx context.Context, dbName, dst string) error {
+	if err := ValidateName(dbName); err != nil {
+		return err
+	}
+	if found, err := fileExists(dbName, s.path(dbName)); err != nil {
+		return err
+	} else if !found {
+		return status.Errorf(codes.NotFound, "database %s does not exist", dbName)
+	}
+
+	lease, err := s.acquire(ctx, dbName, Read, false)
+	if err != nil {
+		return err
+	}
+	defer lease.Release()
+
+	err = lease.Conn().Raw(func(driverConn any) error {
+		src, ok := driverConn.(*sqlite3.SQLiteConn)
+		if !ok {
+			return fmt.Errorf("unexpected driver connection %T", driverConn)
+		}
+		return backup(ctx, src, dst)
+	})
+	if err != nil {
+		os.Remove(dst)
+		if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+			return status.FromContextError(ctxErr).Err()
+		}
+		slog.Error("Backup failed", "database", dbName, "error", err)
+		return status.Errorf(codes.Internal, "failed to back up database %s: %v", dbName, lease.Check(err))
+	}
+	slog.Info("Backed up database", "database", dbName, "to", dst)
+	return nil
+}
+
+// backupStepPages is how many pages backup copies between checks of its
+// context.
+const backupStepPages = 1024
+
+// backup copies every page of src to a new database at dst, a few at a time
+// until done or ctx is done.
+func backup(ctx context.Context, src *sqlite3.SQLiteConn, dst string) error {
+	dstConn, err := (&sqlite3.SQLiteDriver{}).Open(dst)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", dst, err)
+	}
+	defer dstConn.Close()
+
+	// A read transaction held on src across the steps keeps them on the same
+	// snapshot, otherwise a write in between would start the backup over
+	if err := beginRead(src); err != nil {
+		return err
+	}
+	defer src.Exec("ROLLBACK", nil)
+
+	b, err := dstConn.(*sqlite3.SQLiteConn).Backup("main", src, "main")
+	if err != nil {
+		return err
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			b.Finish()
+			return err
+		}
+		done, err := b.Step(backupStepPages)
+		if err != nil {
+			b.Finish()
+			return err
+		}
+		if done {
+			return b.Finish()
+		}
+	}
+}
+
+// beginRead starts a read transaction on conn, which BEGIN alone defers to
+// the first read.
+func beginRead(conn *sqlite3.SQLiteConn) error {
+	if _, err := conn.Exec("BEGIN", nil); err != nil {
+		return err
+	}
+	rows, err := conn.Query("SELECT count(*) FROM sqlite_master", nil)
+	if err == nil {
+		err = rows.Next(make([]driver.Value, 1))
+		rows.Close()
+	}
+	if err != nil {
+		conn.Exec("ROLLBACK", nil)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package nsqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDBManager_Backup(t *testing.T) {
+	m := nsqlite.NewManager(t.TempDir())
+	defer m.Close(context.Background())
+	ctx := context.Background()
+
+	lease, err := m.Acquire(ctx, "live.db", nsqlite.Write)
+	require.NoError(t, err)
+	_, err = lease.Conn().ExecContext(ctx, `CREATE TABLE t (n INTEGER); INSERT INTO t VALUES (1), (2), (3)`)
+	require.NoError(t, err)
+	// A write transaction in progress isn't part of the snapshot
+	_, err = lease.Conn().ExecContext(ctx, `BEGIN; INSERT INTO t VALUES (4)`)
+	require.NoError(t, err)
+
+	// The rows are still in the WAL, the copy has them all the same
+	dst := filepath.Join(t.TempDir(), "copy.db")
+	require.NoError(t, m.Backup(ctx, "live.db", dst))
+	_, err = lease.Conn().ExecContext(ctx, `COMMIT`)
+	require.NoError(t, err)
+	lease.Release()
+
+	// Backups don't count as using the database
+	before, err := m.Info(ctx, "live.db")
+	require.NoError(t, err)
+	require.NoError(t, m.Backup(ctx, "live.db", filepath.Join(t.TempDir(), "again.db")))
+	after, err := m.Info(ctx, "live.db")
+	require.NoError(t, err)
+	assert.Equal(t, before.LastUsed, after.LastUsed)
+
+	copied, err := sql.Open("sqlite3", dst)
+	require.NoError(t, err)
+	defer copied.Close()
+	var n int
+	require.NoError(t, copied.QueryRowContext(ctx, `SELECT count(*) FROM t`).Scan(&n))
+	assert.Equal(t, 3, n)
+	var integrity string
+	require.NoError(t, copied.QueryRowContext(ctx, `PRAGMA integrity_check`).Scan(&integrity))
+	assert.Equal(t, "ok", integrity)
+
+	// Cancelled, it stops and leaves nothing behind
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	dst = filepath.Join(t.TempDir(), "cancelled.db")
+	err = m.Backup(cancelled, "live.db", dst)
+	assert.Equal(t, codes.Canceled, status.Code(err))
+	assert.NoFileExists(t, dst)
+
+	err = m.Backup(ctx, "missing.db", filepath.Join(t.TempDir(), "missing.db"))
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,246 @@
+package nsqlite
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// headerMagic starts every SQLite database file.
+var headerMagic = []byte("SQLite format 3\x00")
+
+// DatabaseInfo describes a database of the data directory.
+type DatabaseInfo struct {
+	Name     string
+	Size     int64 // of the database file
+	WALSize  int64 // of its WAL file, 0 if there is none
+	PageSize int64
+	// PageCount comes from the file header for closed databases, which
+	// leaves out the pages still in the WAL
+	PageCount int64
+	Open      bool
+	LastUsed  time.Time // zero if not used since the manager was created
+}
+
+// List describes every database in the data directory, sorted by name,
+// without opening any of them.
+func (s *DBManager) List() ([]DatabaseInfo, error) {
+	entries, err := os.ReadDir(s.datadir)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read the data directory: %v", err)
+	}
+
+	var infos []DatabaseInfo
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || ValidateName(entry.Name()) != nil {
+			continue
+		}
+		info, err := s.fileInfo(entry.Name())
+		if errors.Is(err, errNotDatabase) || errors.Is(err, os.ErrNotExist) {
+			// Not ours, or dropped meanwhile
+			continue
+		} else if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to read database %s: %v", entry.Name(), err)
+		}
+		infos = append(infos, info)
+	}
+	slices.SortFunc(infos, func(a, b DatabaseInfo) int { return strings.Compare(a.Name, b.Name) })
+	return infos, nil
+}
+
+// Info describes the database named dbName. Open databases report their
+// page count as SQLite sees it, WAL included.
+func (s *DBManager) Info(ctx context.Context, dbName string) (DatabaseInfo, error) {
+	if err := ValidateName(dbName); err != nil {
+		return DatabaseInfo{}, err
+	}
+	info, err := s.fileInfo(dbName)
+	switch {
+	case errors.Is(err, os.ErrNotExist), errors.Is(err, errNotDatabase):
+		return DatabaseInfo{}, status.Errorf(codes.NotFound, "database %s does not exist", dbName)
+	case err != nil:
+		return DatabaseInfo{}, status.Errorf(codes.Internal, "failed to read database %s: %v", dbName, err)
+	}
+
+	db := s.peek(dbName)
+	if db == nil {
+		return info, nil
+	}
+	defer s.unpeek(db)
+	lease, err := db.Acquire(ctx, Read)
+	if err != nil {
+		return DatabaseInfo{}, err
+	}
+	defer lease.Release()
+	err = lease.Conn().QueryRowContext(ctx, "PRAGMA page_count").Scan(&info.PageCount)
+	if err != nil {
+		return DatabaseInfo{}, status.Errorf(codes.Internal, "failed to count pages of %s: %v", dbName, lease.Check(err))
+	}
+	return info, nil
+}
+
+// errNotDatabase is returned by fileInfo for files without the SQLite header.
+var errNotDatabase = errors.New("not an SQLite database")
+
+// fileInfo describes the database named dbName from its files, without
+// opening it.
+func (s *DBManager) fileInfo(dbName string) (DatabaseInfo, error) {
+	dbpath := s.path(dbName)
+	info := DatabaseInfo{Name: dbName}
+
+	f, err := os.Open(dbpath)
+	if err != nil {
+		return info, err
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return info, err
+	}
+	info.Size = stat.Size()
+
+	// SQLite only writes the header along with the first page, so a new
+	// database can be empty
+	header := make([]byte, 100)
+	if _, err := io.ReadFull(f, header); err == nil {
+		if !bytes.HasPrefix(header, headerMagic) {
+			return info, errNotDatabase
+		}
+		info.PageSize = int64(binary.BigEndian.Uint16(header[16:18]))
+		if info.PageSize == 1 {
+			info.PageSize = 65536
+		}
+		// The page count in the header is only valid if written by a
+		// version of SQLite that keeps it up to date
+		if bytes.Equal(header[24:28], header[92:96]) {
+			info.PageCount = int64(binary.BigEndian.Uint32(header[28:32]))
+		} else {
+			info.PageCount = info.Size / info.PageSize
+		}
+	} else if !errors.Is(err, io.EOF) {
+		return info, errNotDatabase
+	}
+
+	if wal, err := os.Stat(dbpath + "-wal"); err == nil {
+		info.WALSize = wal.Size()
+	}
+
+	s.dbMutex.Lock()
+	if db, ok := s.dbHandles[dbpath]; ok {
+		info.Open = true
+		info.LastUsed = db.lastUsed
+	} else {
+		info.LastUsed = s.lastUsed[dbpath]
+	}
+	s.dbMutex.Unlock()
+	return info, nil
+}
+
+// peek returns the database named dbName if it is open, keeping it from
+// being evicted until unpeek, without counting as a use of it.
+func (s *DBManager) peek(dbName string) *Database {
+	s.dbMutex.Lock()
+	defer s.dbMutex.Unlock()
+
+	db, ok := s.dbHandles[s.path(dbName)]
+	if !ok {
+		return nil
+	}
+	db.refs++
+	return db
+}
+
+func (s *DBManager) unpeek(db *Database) {
+	s.dbMutex.Lock()
+	defer s.dbMutex.Unlock()
+	db.refs--
+}
+
+// Create creates the database named dbName and opens it, whether or not
+// databases are created on first use. It fails with AlreadyExists if there
+// is one by that name.
+func (s *DBManager) Create(dbName string) error {
+	if err := ValidateName(dbName); err != nil {
+		return err
+	}
+	dbpath := s.path(dbName)
+
+	s.dbMutex.Lock()
+	defer s.dbMutex.Unlock()
+	s.waitPending(dbpath)
+
+	if found, err := fileExists(dbName, dbpath); err != nil {
+		return err
+	} else if found {
+		return status.Errorf(codes.AlreadyExists, "database %s already exists", dbName)
+	}
+	_, err := s.open(dbName, dbpath)
+	return err
+}
+
+// Drop closes the database named dbName and deletes it, its WAL and shared
+// memory files included. It fails with FailedPrecondition while the
+// database is in use, rather than pulling connections from under requests,
+// transactions or cursors.
+func (s *DBManager) Drop(ctx context.Context, dbName string) error {
+	if err := ValidateName(dbName); err != nil {
+		return err
+	}
+	dbpath := s.path(dbName)
+
+	s.dbMutex.Lock()
+	s.waitPending(dbpath)
+	if found, err := fileExists(dbName, dbpath); err != nil || !found {
+		s.dbMutex.Unlock()
+		if err == nil {
+			err = status.Errorf(codes.NotFound, "database %s does not exist", dbName)
+		}
+		return err
+	}
+	db, open := s.dbHandles[dbpath]
+	if open && db.refs > 0 {
+		s.dbMutex.Unlock()
+		return status.Errorf(codes.FailedPrecondition, "database %s is in use", dbName)
+	}
+	if open {
+		delete(s.dbHandles, dbpath)
+		s.lru.Remove(db.elem)
+	}
+	delete(s.lastUsed, dbpath)
+	// Requests for the database wait until it's gone
+	done := make(chan struct{})
+	s.closing[dbpath] = done
+	s.dbMutex.Unlock()
+
+	defer func() {
+		s.dbMutex.Lock()
+		delete(s.closing, dbpath)
+		s.dbMutex.Unlock()
+		close(done)
+	}()
+
+	if open {
+		if err := db.Close(ctx); err != nil {
+			slog.Error("Failed to close dropped database", "database", dbName, "error", err)
+		}
+	}
+	// The database file goes last, a journal left behind without it could
+	// end up applied to a new database of the same name
+	for _, suffix := range append(slices.Clone(sidecarSuffixes), "") {
+		if err := os.Remove(dbpath + suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return status.Errorf(codes.Internal, "failed to delete database %s: %v", dbName, err)
+		}
+	}
+	slog.Info("Dropped database", "database", dbName)
+	return nil
+}
@@ -0,0 +1,76 @@
+package nsqlite_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDBManager_Catalog(t *testing.T) {
+	dir := t.TempDir()
+	m := nsqlite.NewManager(dir, nsqlite.WithAutoCreate(false))
+	defer m.Close(context.Background())
+	ctx := context.Background()
+
+	// Files that aren't databases are left out
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a database, honest"), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "backups"), 0o700))
+
+	require.NoError(t, m.Create("orders.db"))
+	assert.Equal(t, codes.AlreadyExists, status.Code(m.Create("orders.db")))
+	require.NoError(t, m.Create("users.db"))
+
+	lease, err := m.Acquire(ctx, "orders.db", nsqlite.Write)
+	require.NoError(t, err)
+	_, err = lease.Conn().ExecContext(ctx, `CREATE TABLE t (n INTEGER); INSERT INTO t VALUES (1)`)
+	require.NoError(t, err)
+	lease.Release()
+
+	infos, err := m.List()
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	assert.Equal(t, "orders.db", infos[0].Name)
+	assert.Equal(t, "users.db", infos[1].Name)
+	assert.True(t, infos[0].Open)
+	assert.NotZero(t, infos[0].WALSize)
+	assert.False(t, infos[0].LastUsed.IsZero())
+	assert.True(t, infos[1].LastUsed.IsZero())
+
+	// Open databases count the pages still in the WAL
+	info, err := m.Info(ctx, "orders.db")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4096), info.PageSize)
+	assert.Equal(t, int64(2), info.PageCount)
+	_, err = m.Info(ctx, "missing.db")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Not while in use
+	lease, err = m.Acquire(ctx, "orders.db", nsqlite.Read)
+	require.NoError(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(m.Drop(ctx, "orders.db")))
+	lease.Release()
+
+	require.NoError(t, m.Drop(ctx, "orders.db"))
+	for _, name := range []string{"orders.db", "orders.db-wal", "orders.db-shm"} {
+		assert.NoFileExists(t, filepath.Join(dir, name))
+	}
+	assert.Equal(t, codes.NotFound, status.Code(m.Drop(ctx, "orders.db")))
+	_, err = m.Acquire(ctx, "orders.db", nsqlite.Read)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// A database of the same name starts out empty
+	require.NoError(t, m.Create("orders.db"))
+	lease, err = m.Acquire(ctx, "orders.db", nsqlite.Read)
+	require.NoError(t, err)
+	defer lease.Release()
+	var tables int
+	require.NoError(t, lease.Conn().QueryRowContext(ctx, `SELECT count(*) FROM sqlite_schema`).Scan(&tables))
+	assert.Zero(t, tables)
+}
@@ -1,72 +1,247 @@
 package nsqlite
 
 import (
+	"container/list"
 	"context"
-	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
 	"log"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
-	"github.com/jackc/puddle/v2"
 	_ "github.com/mattn/go-sqlite3" // SQLite driver
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type DBManager struct {
-	dbHandles map[string]*puddle.Pool[*sql.DB]
-	dbMutex   sync.RWMutex
+	dbHandles map[string]*Database
+	dbMutex   sync.Mutex
 	datadir   string
+	closed    bool // guarded by dbMutex, no database is opened once set
+
+	// Guarded by dbMutex too
+	lru       *list.List               // of the open databases, most recently used first
+	closing   map[string]chan struct{} // closed once the evicted or dropped database is
+	opening   map[string]chan struct{} // closed once the database is open, or failed to
+	restoring map[string]bool          // databases being replaced, see Restore
+	lastUsed  map[string]time.Time     // of the databases closed since they were opened
+	stats     ManagerStats
+
+	maxOpen     int
+	idleTimeout time.Duration
+	evictions   sync.WaitGroup
+	opens       sync.WaitGroup // in progress, see open
+	stopEvictor chan struct{}
+
+	// autoCreate creates databases on first use, otherwise they must already exist
+	autoCreate bool
+
+	// dropLegacyTestWAL drops the _test_wal table of earlier versions when
+	// opening a database
+	dropLegacyTestWAL bool
+
+	// settings apply to every database but those in dbSettings
+	settings   Settings
+	dbSettings map[string]Settings
+}
+
+// Option configures a DBManager.
+type Option func(*DBManager)
+
+// WithAutoCreate sets whether databases that don't exist yet are created on
+// first use (the default) or rejected with NotFound.
+func WithAutoCreate(autoCreate bool) Option {
+	return func(m *DBManager) {
+		m.autoCreate = autoCreate
+	}
+}
+
+// WithSettings sets the settings of every database, overridden for some by
+// name. Zero fields fall back to defaults, then DefaultSettings.
+func WithSettings(defaults Settings, overrides map[string]Settings) Option {
+	return func(m *DBManager) {
+		m.settings = defaults.Or(DefaultSettings())
+		m.dbSettings = overrides
+	}
 }
 
-func NewManager(datadir string) *DBManager {
+// WithDropLegacyTestWAL drops the empty _test_wal table earlier versions
+// created in every database, the first time each database is opened.
+func WithDropLegacyTestWAL(drop bool) Option {
+	return func(m *DBManager) {
+		m.dropLegacyTestWAL = drop
+	}
+}
+
+func NewManager(datadir string, opts ...Option) *DBManager {
 	// Make sure that the datadir exists
 	err := os.MkdirAll(datadir, os.ModePerm)
 	if err != nil {
 		log.Fatal("Unable to create dir", err)
 	}
 
-	return &DBManager{
-		dbHandles: make(map[string]*puddle.Pool[*sql.DB]),
-		datadir:   datadir,
+	m := &DBManager{
+		dbHandles:   make(map[string]*Database),
+		datadir:     datadir,
+		lru:         list.New(),
+		closing:     make(map[string]chan struct{}),
+		opening:     make(map[string]chan struct{}),
+		lastUsed:    make(map[string]time.Time),
+		restoring:   make(map[string]bool),
+		stopEvictor: make(chan struct{}),
+		autoCreate:  true,
+		settings:    DefaultSettings(),
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.idleTimeout > 0 {
+		go m.evictor()
+	}
+	return m
+}
+
+// Open returns the database named dbName, opening it on first use. Unlike
+// with Acquire, nothing keeps it from being evicted right after.
+func (s *DBManager) Open(dbName string) (*Database, error) {
+	db, err := s.ref(dbName)
+	if err != nil {
+		return nil, err
+	}
+	s.unref(db)
+	return db, nil
+}
+
+func (s *DBManager) path(dbName string) string {
+	return filepath.Join(s.datadir, dbName)
 }
 
-// --- Helper to get/open DB handle ---
-func (s *DBManager) AcquirePool(dbName string) (*puddle.Pool[*sql.DB], error) {
-	if dbName == "" {
-		return nil, status.Error(codes.InvalidArgument, "database_name is required")
+// open opens the database at dbpath, making room for it under the cap.
+// The caller must hold dbMutex. It's unlocked while SQLite opens and checks
+// the file so a slow one doesn't hold up the others, meanwhile the database
+// is in opening.
+func (s *DBManager) open(dbName, dbpath string) (*Database, error) {
+	if s.closed {
+		return nil, status.Error(codes.Unavailable, "server is shutting down")
 	}
+	if err := s.makeRoom(); err != nil {
+		return nil, err
+	}
+	done := make(chan struct{})
+	s.opening[dbpath] = done
+	s.opens.Add(1)
+	s.dbMutex.Unlock()
+
+	newDb, err := s.openFile(dbName, dbpath)
 
-	dbpath := filepath.Join(s.datadir, dbName)
+	s.dbMutex.Lock()
+	delete(s.opening, dbpath)
+	close(done)
+	s.opens.Done()
+	if err != nil {
+		return nil, err
+	}
+	if s.closed {
+		// Close didn't know about it
+		s.dbMutex.Unlock()
+		newDb.Close(context.Background())
+		s.dbMutex.Lock()
+		return nil, status.Error(codes.Unavailable, "server is shutting down")
+	}
 
-	s.dbMutex.RLock()
-	dbpool, exists := s.dbHandles[dbpath]
-	s.dbMutex.RUnlock()
+	s.dbHandles[dbpath] = newDb
+	newDb.elem = s.lru.PushFront(newDb)
+	newDb.lastUsed = s.lastUsed[dbpath]
+	delete(s.lastUsed, dbpath)
+	s.stats.Opened++
+	return newDb, nil
+}
 
-	if exists {
-		return dbpool, nil
+// openFile opens and checks the database at dbpath, cleaning up after
+// earlier versions if asked to.
+func (s *DBManager) openFile(dbName, dbpath string) (*Database, error) {
+	newDb, err := openDatabase(context.Background(), dbpath, s.SettingsFor(dbName))
+	if err != nil {
+		slog.Error("Failed to open database", "database", dbName, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to open database %s", dbName)
 	}
 
-	// DB not open, try opening it (use WAL mode, etc.)
+	if s.dropLegacyTestWAL {
+		if err := migrate(newDb, dbName); err != nil {
+			newDb.Close(context.Background())
+			slog.Error("Legacy cleanup failed", "database", dbName, "error", err)
+			return nil, status.Errorf(codes.Internal, "failed to clean up database %s", dbName)
+		}
+	}
+	return newDb, nil
+}
+
+// Close closes every open database at once, see Database.Close, and keeps
+// any other from being opened. Databases still in use when ctx is done are
+// closed without being checkpointed, and reported in the error.
+func (s *DBManager) Close(ctx context.Context) error {
 	s.dbMutex.Lock()
-	defer s.dbMutex.Unlock()
+	if !s.closed && s.idleTimeout > 0 {
+		close(s.stopEvictor)
+	}
+	s.closed = true
+	dbs := s.dbHandles
+	s.dbHandles = make(map[string]*Database)
+	for _, db := range dbs {
+		s.lru.Remove(db.elem)
+	}
+	s.dbMutex.Unlock()
 
-	// Double-check if another goroutine opened it while waiting for lock
-	dbpool, exists = s.dbHandles[dbpath]
-	if exists {
-		return dbpool, nil
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for path, db := range dbs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := db.Close(ctx); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(path), err))
+				mu.Unlock()
+			}
+		}()
 	}
+	wg.Wait()
+	s.evictions.Wait()
+	s.opens.Wait()
+	return errors.Join(errs...)
+}
 
-	// This is going to be a poooool
-	newDb, err := NewPool(context.Background(), dbpath)
+// fileExists reports whether the database file at dbpath exists.
+func fileExists(dbName, dbpath string) (bool, error) {
+	_, err := os.Stat(dbpath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return false, nil
+	case err != nil:
+		return false, status.Errorf(codes.Internal, "failed to check database %s: %v", dbName, err)
+	}
+	return true, nil
+}
+
+// migrate cleans up after earlier versions in a newly opened database.
+func migrate(db *Database, dbName string) error {
+	lease, err := db.Acquire(context.Background(), Write)
 	if err != nil {
-		slog.Error("Fatal Pool Creation", "error", err)
-		return nil, status.Error(codes.Internal, "failed to created a pool")
+		return err
 	}
+	defer lease.Release()
+	return lease.Check(dropLegacyTestWAL(context.Background(), lease.Conn(), dbName))
+}
 
-	s.dbHandles[dbpath] = newDb
-	return newDb, nil
+// SettingsFor returns the settings dbName is opened with.
+func (s *DBManager) SettingsFor(dbName string) Settings {
+	return s.dbSettings[dbName].Or(s.settings)
 }
@@ -2,15 +2,21 @@ package nsqlite_test
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/alfredosa/netsqlite/internal/nsqlite"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-func TestDBManager_AcquirePool(t *testing.T) {
+func TestDBManager_Open(t *testing.T) {
 	// Create a temporary directory
 	dir, err := os.MkdirTemp("", "netsqlite-*")
 	require.NoError(t, err, "Failed to create temp directory")
@@ -26,23 +32,178 @@ func TestDBManager_AcquirePool(t *testing.T) {
 	// Use a simple filename - not a path
 	dbFileName := "wowdb.db"
 
-	// Get the pool
-	got, err := s.AcquirePool(dbFileName)
-	require.NoError(t, err, "Failed to acquire pool")
-	require.NotNil(t, got, "Pool should not be nil")
+	// Get the database
+	got, err := s.Open(dbFileName)
+	require.NoError(t, err, "Failed to open database")
+	require.NotNil(t, got, "Database should not be nil")
 
-	// Acquire a connection from the pool
-	db, err := got.Acquire(context.Background())
+	// Lease a connection of the database
+	lease, err := got.Acquire(context.Background(), nsqlite.Read)
 	require.NoError(t, err, "Failed to acquire connection")
-	require.NotNil(t, db, "Connection should not be nil")
+	require.NotNil(t, lease, "Lease should not be nil")
 
 	// Ping the database
-	err = db.Value().Ping()
+	err = lease.Conn().PingContext(context.Background())
 	assert.NoError(t, err, "Ping should not fail")
 
 	// Release the connection
-	db.Release()
+	lease.Release()
 
-	// Close the pool
-	got.Close()
+	// Close the database
+	require.NoError(t, got.Close(context.Background()))
+}
+
+func TestDBManager_Settings(t *testing.T) {
+	dir := t.TempDir()
+	s := nsqlite.NewManager(dir, nsqlite.WithSettings(
+		nsqlite.Settings{BusyTimeout: time.Second},
+		map[string]nsqlite.Settings{"rollback.db": {JournalMode: "delete", PoolSize: 1}},
+	))
+
+	pragmas := func(dbName string) (string, int, int32) {
+		db, err := s.Open(dbName)
+		require.NoError(t, err)
+		lease, err := db.Acquire(context.Background(), nsqlite.Write)
+		require.NoError(t, err)
+		defer lease.Release()
+
+		var journalMode string
+		var busyTimeout int
+		require.NoError(t, lease.Conn().QueryRowContext(context.Background(), "PRAGMA journal_mode").Scan(&journalMode))
+		require.NoError(t, lease.Conn().QueryRowContext(context.Background(), "PRAGMA busy_timeout").Scan(&busyTimeout))
+		return journalMode, busyTimeout, db.Stats().MaxReaders
+	}
+
+	journalMode, busyTimeout, poolSize := pragmas("default.db")
+	assert.Equal(t, "wal", journalMode)
+	assert.Equal(t, 1000, busyTimeout)
+	assert.Equal(t, int32(5), poolSize)
+
+	journalMode, busyTimeout, poolSize = pragmas("rollback.db")
+	assert.Equal(t, "delete", journalMode)
+	assert.Equal(t, 1000, busyTimeout)
+	assert.Equal(t, int32(1), poolSize)
+
+	assert.Error(t, nsqlite.Settings{JournalMode: "sideways"}.Validate())
+	assert.Error(t, nsqlite.Settings{PoolSize: -1}.Validate())
+	assert.NoError(t, nsqlite.Settings{}.Validate())
+}
+
+func TestDBManager_Pragmas(t *testing.T) {
+	s := nsqlite.NewManager(t.TempDir(), nsqlite.WithSettings(
+		nsqlite.Settings{Pragmas: map[string]string{"foreign_keys": "ON", "temp_store": "MEMORY"}},
+		map[string]nsqlite.Settings{"big.db": {Pragmas: map[string]string{"cache_size": "-64000"}}},
+	))
+
+	db, err := s.Open("big.db")
+	require.NoError(t, err)
+
+	// Every connection gets them, the writer and the readers alike
+	var conns []*sql.Conn
+	for _, access := range []nsqlite.Access{nsqlite.Write, nsqlite.Read, nsqlite.Read, nsqlite.Read} {
+		lease, err := db.Acquire(context.Background(), access)
+		require.NoError(t, err)
+		defer lease.Release()
+		conns = append(conns, lease.Conn())
+	}
+	for _, conn := range conns {
+		var foreignKeys int
+		require.NoError(t, conn.QueryRowContext(context.Background(), "PRAGMA foreign_keys").Scan(&foreignKeys))
+		assert.Equal(t, 1, foreignKeys)
+	}
+
+	pragmas, err := nsqlite.ReadPragmas(context.Background(), conns[0], s.SettingsFor("big.db"))
+	require.NoError(t, err)
+	byName := make(map[string]nsqlite.Pragma)
+	for _, p := range pragmas {
+		byName[p.Name] = p
+	}
+	assert.Equal(t, nsqlite.Pragma{Name: "cache_size", Value: "-64000", Configured: "-64000"}, byName["cache_size"])
+	assert.Equal(t, nsqlite.Pragma{Name: "temp_store", Value: "2", Configured: "MEMORY"}, byName["temp_store"])
+	assert.Equal(t, nsqlite.Pragma{Name: "busy_timeout", Value: "5000", Configured: "5000"}, byName["busy_timeout"])
+	assert.Equal(t, "", byName["mmap_size"].Configured)
+
+	for _, bad := range []map[string]string{
+		{"foreign_keys": "ON; DROP TABLE users"},
+		{"Foreign Keys": "ON"},
+		{"journal_mode": "WAL"},
+		{"query_only": "ON"},
+	} {
+		assert.Error(t, nsqlite.Settings{Pragmas: bad}.Validate(), "%v", bad)
+	}
+}
+
+// walSize is the size of dbPath's WAL, 0 if there is none.
+func walSize(t *testing.T, dbPath string) int64 {
+	t.Helper()
+	info, err := os.Stat(dbPath + "-wal")
+	if errors.Is(err, os.ErrNotExist) {
+		return 0
+	}
+	require.NoError(t, err)
+	return info.Size()
+}
+
+func TestDBManager_Close(t *testing.T) {
+	dir := t.TempDir()
+	m := nsqlite.NewManager(dir)
+	ctx := context.Background()
+
+	writer, err := m.Acquire(ctx, "close.db", nsqlite.Write)
+	require.NoError(t, err)
+	_, err = writer.Conn().ExecContext(ctx, `CREATE TABLE t (id INTEGER PRIMARY KEY)`)
+	require.NoError(t, err)
+	_, err = writer.Conn().ExecContext(ctx, `INSERT INTO t VALUES (1), (2), (3)`)
+	require.NoError(t, err)
+	writer.Release()
+	assert.NotZero(t, walSize(t, filepath.Join(dir, "close.db")))
+
+	db, err := m.Open("close.db")
+	require.NoError(t, err)
+	reader, err := db.Acquire(ctx, nsqlite.Read)
+	require.NoError(t, err)
+
+	closed := make(chan error, 1)
+	go func() {
+		closeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		closed <- m.Close(closeCtx)
+	}()
+
+	// Nothing new is handed out, but the lease still works
+	assert.Eventually(t, func() bool {
+		_, err := db.Acquire(ctx, nsqlite.Write)
+		return status.Code(err) == codes.Unavailable
+	}, time.Second, time.Millisecond)
+	_, err = m.Acquire(ctx, "close.db", nsqlite.Read)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = m.Acquire(ctx, "other.db", nsqlite.Read)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	var n int
+	require.NoError(t, reader.Conn().QueryRowContext(ctx, `SELECT count(*) FROM t`).Scan(&n))
+
+	select {
+	case err := <-closed:
+		t.Fatalf("Close returned with a connection leased out: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	reader.Release()
+	require.NoError(t, <-closed)
+	assert.Zero(t, walSize(t, filepath.Join(dir, "close.db")), "the WAL should have been checkpointed")
+}
+
+func TestDBManager_CloseTimeout(t *testing.T) {
+	m := nsqlite.NewManager(t.TempDir())
+	ctx := context.Background()
+
+	writer, err := m.Acquire(ctx, "stuck.db", nsqlite.Write)
+	require.NoError(t, err)
+	defer writer.Release()
+
+	closeCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	err = m.Close(closeCtx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "stuck.db")
 }
@@ -0,0 +1,211 @@
+package nsqlite
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithMaxOpen caps the databases open at once. Opening one more closes the
+// least recently used database nobody is using, or fails with
+// ResourceExhausted if they are all in use. Zero means no cap.
+func WithMaxOpen(maxOpen int) Option {
+	return func(m *DBManager) {
+		m.maxOpen = maxOpen
+	}
+}
+
+// WithIdleTimeout closes databases nobody used for idleTimeout, they are
+// opened again on their next use. Zero keeps them open until Close.
+func WithIdleTimeout(idleTimeout time.Duration) Option {
+	return func(m *DBManager) {
+		m.idleTimeout = idleTimeout
+	}
+}
+
+// ManagerStats counts the databases a DBManager opened and closed since it
+// was created.
+type ManagerStats struct {
+	Open        int    // databases open right now
+	Opened      uint64 // including those opened again after an eviction
+	EvictedIdle uint64 // closed after idling for the idle timeout
+	EvictedLRU  uint64 // closed to make room for another under the cap
+	Rejected    uint64 // opens failed because every open database was in use
+}
+
+// Stats reports the databases open right now and the counts so far.
+func (s *DBManager) Stats() ManagerStats {
+	s.dbMutex.Lock()
+	defer s.dbMutex.Unlock()
+
+	stats := s.stats
+	stats.Open = len(s.dbHandles)
+	return stats
+}
+
+// ref returns the database named dbName, opening it if needed, and keeps it
+// from being evicted until unref.
+func (s *DBManager) ref(dbName string) (*Database, error) {
+	return s.hold(dbName, true)
+}
+
+// hold is ref, counting as a use of the database only if use is set. Held
+// otherwise, it must be let go of with unpeek, and one opened for it goes to
+// the back of the LRU order as nobody used it since.
+func (s *DBManager) hold(dbName string, use bool) (*Database, error) {
+	if err := ValidateName(dbName); err != nil {
+		return nil, err
+	}
+	dbpath := s.path(dbName)
+
+	s.dbMutex.Lock()
+	defer s.dbMutex.Unlock()
+
+	// An evicted database is opened again once it's done closing, and one
+	// being opened is shared once it's open. One being restored fails right
+	// away so clients don't pile up behind the swap.
+	for {
+		if s.restoring[dbpath] {
+			return nil, status.Errorf(codes.Unavailable, "database %s is being restored, try again shortly", dbName)
+		}
+		done := s.pending(dbpath)
+		if done == nil {
+			break
+		}
+		s.dbMutex.Unlock()
+		<-done
+		s.dbMutex.Lock()
+	}
+
+	db, exists := s.dbHandles[dbpath]
+	if !exists {
+		if !s.autoCreate {
+			if found, err := fileExists(dbName, dbpath); err != nil {
+				return nil, err
+			} else if !found {
+				return nil, status.Errorf(codes.NotFound, "database %s does not exist", dbName)
+			}
+		}
+		var err error
+		if db, err = s.open(dbName, dbpath); err != nil {
+			return nil, err
+		}
+	}
+	db.refs++
+	if use {
+		db.lastUsed = time.Now()
+		s.lru.MoveToFront(db.elem)
+	} else if !exists {
+		s.lru.MoveToBack(db.elem)
+	}
+	return db, nil
+}
+
+// pending returns a channel closed once the database at dbpath is done being
+// opened, evicted or dropped, nil if it isn't. The caller must hold dbMutex.
+func (s *DBManager) pending(dbpath string) chan struct{} {
+	if done := s.closing[dbpath]; done != nil {
+		return done
+	}
+	return s.opening[dbpath]
+}
+
+// waitPending waits until the database at dbpath is done being opened,
+// evicted or dropped, if it is. The caller must hold dbMutex, it's unlocked
+// meanwhile.
+func (s *DBManager) waitPending(dbpath string) {
+	for done := s.pending(dbpath); done != nil; done = s.pending(dbpath) {
+		s.dbMutex.Unlock()
+		<-done
+		s.dbMutex.Lock()
+	}
+}
+
+// unref lets db be evicted again once nobody else holds a reference.
+func (s *DBManager) unref(db *Database) {
+	s.dbMutex.Lock()
+	defer s.dbMutex.Unlock()
+
+	db.refs--
+	db.lastUsed = time.Now()
+	s.lru.MoveToFront(db.elem)
+}
+
+// makeRoom evicts the least recently used database nobody is using if the
+// cap is reached. The caller must hold dbMutex.
+func (s *DBManager) makeRoom() error {
+	if s.maxOpen <= 0 || len(s.dbHandles)+len(s.opening) < s.maxOpen {
+		return nil
+	}
+	for e := s.lru.Back(); e != nil; e = e.Prev() {
+		if db := e.Value.(*Database); db.refs == 0 {
+			s.stats.EvictedLRU++
+			s.evict(db, "lru")
+			return nil
+		}
+	}
+	s.stats.Rejected++
+	return status.Errorf(codes.ResourceExhausted, "too many databases in use, at most %d can be open", s.maxOpen)
+}
+
+// evictIdle closes the databases nobody used since before cutoff.
+func (s *DBManager) evictIdle(cutoff time.Time) {
+	s.dbMutex.Lock()
+	defer s.dbMutex.Unlock()
+
+	// The list is ordered by last use, so the idle ones are all at the back
+	for e := s.lru.Back(); e != nil; {
+		db, prev := e.Value.(*Database), e.Prev()
+		if !db.lastUsed.Before(cutoff) {
+			break
+		}
+		if db.refs == 0 {
+			s.stats.EvictedIdle++
+			s.evict(db, "idle")
+		}
+		e = prev
+	}
+}
+
+// evict forgets db and closes it in the background, opening it again waits
+// until it's closed. The caller must hold dbMutex.
+func (s *DBManager) evict(db *Database, reason string) {
+	delete(s.dbHandles, db.path)
+	s.lru.Remove(db.elem)
+	s.lastUsed[db.path] = db.lastUsed
+
+	done := make(chan struct{})
+	s.closing[db.path] = done
+	s.evictions.Add(1)
+	go func() {
+		defer s.evictions.Done()
+		// Nothing is leased out, so this only waits for the checkpoint
+		if err := db.Close(context.Background()); err != nil {
+			slog.Error("Failed to close evicted database", "database", db.path, "error", err)
+		} else {
+			slog.Info("Evicted database", "database", db.path, "reason", reason)
+		}
+
+		s.dbMutex.Lock()
+		delete(s.closing, db.path)
+		s.dbMutex.Unlock()
+		close(done)
+	}()
+}
+
+// evictor closes idle databases until Close.
+func (s *DBManager) evictor() {
+	ticker := time.NewTicker(s.idleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			s.evictIdle(now.Add(-s.idleTimeout))
+		case <-s.stopEvictor:
+			return
+		}
+	}
+}
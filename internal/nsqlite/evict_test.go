@@ -0,0 +1,189 @@
+package nsqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDBManager_MaxOpen(t *testing.T) {
+	m := nsqlite.NewManager(t.TempDir(), nsqlite.WithMaxOpen(2))
+	defer m.Close(context.Background())
+	ctx := context.Background()
+
+	exec := func(dbName, query string) {
+		lease, err := m.Acquire(ctx, dbName, nsqlite.Write)
+		require.NoError(t, err)
+		defer lease.Release()
+		_, err = lease.Conn().ExecContext(ctx, query)
+		require.NoError(t, err)
+	}
+
+	held, err := m.Acquire(ctx, "held.db", nsqlite.Read)
+	require.NoError(t, err)
+	defer held.Release()
+	exec("first.db", `CREATE TABLE t (n INTEGER); INSERT INTO t VALUES (1)`)
+
+	// first.db is the only one nobody uses, so it makes room
+	exec("second.db", `CREATE TABLE t (n INTEGER)`)
+	assert.Equal(t, nsqlite.ManagerStats{Open: 2, Opened: 3, EvictedLRU: 1}, m.Stats())
+
+	// Every open database in use
+	busy, err := m.Acquire(ctx, "second.db", nsqlite.Read)
+	require.NoError(t, err)
+	_, err = m.Acquire(ctx, "first.db", nsqlite.Read)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, uint64(1), m.Stats().Rejected)
+	busy.Release()
+
+	// Evicted databases come back as they were
+	lease, err := m.Acquire(ctx, "first.db", nsqlite.Read)
+	require.NoError(t, err)
+	var n int
+	require.NoError(t, lease.Conn().QueryRowContext(ctx, `SELECT n FROM t`).Scan(&n))
+	assert.Equal(t, 1, n)
+	lease.Release()
+	held.Release()
+
+	// Both idle now, first.db was used last
+	exec("third.db", `CREATE TABLE t (n INTEGER)`)
+
+	assert.Equal(t, nsqlite.ManagerStats{Open: 2, Opened: 5, EvictedLRU: 3, Rejected: 1}, m.Stats())
+}
+
+func TestDBManager_IdleTimeout(t *testing.T) {
+	m := nsqlite.NewManager(t.TempDir(), nsqlite.WithIdleTimeout(20*time.Millisecond))
+	defer m.Close(context.Background())
+	ctx := context.Background()
+
+	_, err := m.Open("idle.db")
+	require.NoError(t, err)
+	lease, err := m.Acquire(ctx, "busy.db", nsqlite.Write)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return m.Stats().EvictedIdle == 1 }, time.Second, time.Millisecond)
+
+	// Leased out for longer than the timeout, but not idle
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, nsqlite.ManagerStats{Open: 1, Opened: 2, EvictedIdle: 1}, m.Stats())
+	require.NoError(t, lease.Conn().PingContext(ctx))
+
+	lease.Release()
+	assert.Eventually(t, func() bool { return m.Stats().Open == 0 }, time.Second, time.Millisecond)
+}
+
+func TestDBManager_EvictUnderLoad(t *testing.T) {
+	// Far more databases than may be open, evicted while others use them
+	dir := t.TempDir()
+	m := nsqlite.NewManager(dir, nsqlite.WithMaxOpen(4), nsqlite.WithIdleTimeout(5*time.Millisecond))
+	ctx := context.Background()
+
+	const databases = 12
+	for i := range databases {
+		lease, err := m.Acquire(ctx, fmt.Sprintf("db%d.db", i), nsqlite.Write)
+		require.NoError(t, err)
+		_, err = lease.Conn().ExecContext(ctx, `CREATE TABLE t (n INTEGER)`)
+		require.NoError(t, err)
+		lease.Release()
+	}
+
+	var (
+		wg       sync.WaitGroup
+		inserted [databases]atomic.Int64
+	)
+	for g := range 40 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 25 {
+				db := (g + i) % databases
+				lease, err := m.Acquire(ctx, fmt.Sprintf("db%d.db", db), nsqlite.Write)
+				if status.Code(err) == codes.ResourceExhausted {
+					continue
+				}
+				if !assert.NoError(t, err) {
+					return
+				}
+				_, err = lease.Conn().ExecContext(ctx, `INSERT INTO t VALUES (?)`, i)
+				lease.Release()
+				if assert.NoError(t, err) {
+					inserted[db].Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	stats := m.Stats()
+	assert.LessOrEqual(t, stats.Open, 4)
+	assert.NotZero(t, stats.EvictedLRU+stats.EvictedIdle)
+
+	// Every insert made it to disk
+	require.NoError(t, m.Close(ctx))
+	m = nsqlite.NewManager(dir)
+	defer m.Close(ctx)
+	for i := range databases {
+		lease, err := m.Acquire(ctx, fmt.Sprintf("db%d.db", i), nsqlite.Read)
+		require.NoError(t, err)
+		var n int64
+		require.NoError(t, lease.Conn().QueryRowContext(ctx, `SELECT count(*) FROM t`).Scan(&n))
+		lease.Release()
+		assert.Equal(t, inserted[i].Load(), n, "db%d.db", i)
+	}
+}
+
+func TestDBManager_SlowOpen(t *testing.T) {
+	dir := t.TempDir()
+	m := nsqlite.NewManager(dir, nsqlite.WithSettings(nsqlite.Settings{BusyTimeout: 2 * time.Second}, nil))
+	defer m.Close(context.Background())
+	ctx := context.Background()
+
+	// Someone else holds the write lock, so opening waits on it
+	other, err := sql.Open("sqlite3", filepath.Join(dir, "locked.db"))
+	require.NoError(t, err)
+	defer other.Close()
+	locker, err := other.Conn(ctx)
+	require.NoError(t, err)
+	defer locker.Close()
+	_, err = locker.ExecContext(ctx, `CREATE TABLE t (n INTEGER); BEGIN EXCLUSIVE`)
+	require.NoError(t, err)
+
+	opened := make(chan error, 1)
+	go func() {
+		lease, err := m.Acquire(ctx, "locked.db", nsqlite.Read)
+		if err == nil {
+			lease.Release()
+		}
+		opened <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// The other databases don't wait along
+	start := time.Now()
+	lease, err := m.Acquire(ctx, "free.db", nsqlite.Write)
+	require.NoError(t, err)
+	lease.Release()
+	_, err = m.List()
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	select {
+	case err := <-opened:
+		t.Fatalf("opened despite the lock: %v", err)
+	default:
+	}
+
+	_, err = locker.ExecContext(ctx, `COMMIT`)
+	require.NoError(t, err)
+	require.NoError(t, <-opened)
+	assert.Equal(t, 2, m.Stats().Open)
+}
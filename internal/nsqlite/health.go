@@ -0,0 +1,89 @@
+package nsqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// checkHealth verifies a freshly opened database got the journal mode it
+// was asked for and can be written to, without touching its schema.
+func checkHealth(ctx context.Context, db *sql.DB, settings Settings) error {
+	var journalMode string
+	if err := db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&journalMode); err != nil {
+		return fmt.Errorf("reading journal mode: %w", err)
+	}
+	if !strings.EqualFold(journalMode, settings.JournalMode) {
+		return fmt.Errorf("journal mode is %s instead of %s", journalMode, settings.JournalMode)
+	}
+
+	// Taking the write lock without writing anything proves the file (and
+	// the directory, for the WAL and journal files) can be written to.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("opening connection: %w", err)
+	}
+	defer conn.Close()
+	// Don't wait out the busy timeout when another connection is writing
+	if _, err := conn.ExecContext(ctx, "PRAGMA busy_timeout = 0"); err != nil {
+		return fmt.Errorf("disabling busy timeout: %w", err)
+	}
+	defer conn.ExecContext(ctx, fmt.Sprintf("PRAGMA busy_timeout = %d", settings.BusyTimeout.Milliseconds()))
+
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && (sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked) {
+			return nil // somebody else is writing to it
+		}
+		return fmt.Errorf("database is not writable: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "ROLLBACK"); err != nil {
+		return fmt.Errorf("rolling back write check: %w", err)
+	}
+	return nil
+}
+
+// legacyTestWAL is the table earlier versions created in every database to
+// check it was writable, exactly as they created it.
+const (
+	legacyTestWAL    = "_test_wal"
+	legacyTestWALSQL = "CREATE TABLE _test_wal (id INTEGER PRIMARY KEY)"
+)
+
+// dropLegacyTestWAL drops the _test_wal table left behind by earlier
+// versions. Tables by that name that don't look exactly like it, or hold
+// rows, are left alone since they must be somebody's.
+func dropLegacyTestWAL(ctx context.Context, db *sql.Conn, dbName string) error {
+	var schema string
+	err := db.QueryRowContext(ctx, "SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", legacyTestWAL).Scan(&schema)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("looking up %s: %w", legacyTestWAL, err)
+	}
+	if schema != legacyTestWALSQL {
+		slog.Warn("Keeping table that isn't the legacy one", "table", legacyTestWAL, "database", dbName, "sql", schema)
+		return nil
+	}
+
+	var rows bool
+	if err := db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM "+legacyTestWAL+")").Scan(&rows); err != nil {
+		return fmt.Errorf("checking %s is empty: %w", legacyTestWAL, err)
+	}
+	if rows {
+		slog.Warn("Keeping legacy table that has rows", "table", legacyTestWAL, "database", dbName)
+		return nil
+	}
+
+	if _, err := db.ExecContext(ctx, "DROP TABLE "+legacyTestWAL); err != nil {
+		return fmt.Errorf("dropping %s: %w", legacyTestWAL, err)
+	}
+	slog.Info("Dropped legacy table", "table", legacyTestWAL, "database", dbName)
+	return nil
+}
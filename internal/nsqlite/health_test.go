@@ -0,0 +1,57 @@
+package nsqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hasTestWAL opens dbName through the manager and reports whether it has a
+// _test_wal table.
+func hasTestWAL(t *testing.T, m *nsqlite.DBManager, dbName string) bool {
+	t.Helper()
+	lease, err := m.Acquire(context.Background(), dbName, nsqlite.Read)
+	require.NoError(t, err)
+	defer lease.Release()
+
+	var n int
+	require.NoError(t, lease.Conn().QueryRowContext(context.Background(), `SELECT count(*) FROM sqlite_master WHERE name = '_test_wal'`).Scan(&n))
+	return n == 1
+}
+
+func TestDBManager_SchemaUntouched(t *testing.T) {
+	m := nsqlite.NewManager(t.TempDir())
+	assert.False(t, hasTestWAL(t, m, "fresh.db"))
+}
+
+func TestDBManager_DropLegacyTestWAL(t *testing.T) {
+	dir := t.TempDir()
+	for name, stmts := range map[string][]string{
+		"legacy.db": {`CREATE TABLE IF NOT EXISTS _test_wal (id INTEGER PRIMARY KEY);`},
+		"mine.db":   {`CREATE TABLE _test_wal (id INTEGER PRIMARY KEY, note TEXT)`},
+		"used.db":   {`CREATE TABLE IF NOT EXISTS _test_wal (id INTEGER PRIMARY KEY);`, `INSERT INTO _test_wal VALUES (1)`},
+	} {
+		db, err := sql.Open("sqlite3", filepath.Join(dir, name))
+		require.NoError(t, err)
+		for _, stmt := range stmts {
+			_, err := db.Exec(stmt)
+			require.NoError(t, err)
+		}
+		require.NoError(t, db.Close())
+	}
+
+	// Nothing is dropped unless asked to
+	assert.True(t, hasTestWAL(t, nsqlite.NewManager(dir), "legacy.db"))
+
+	m := nsqlite.NewManager(dir, nsqlite.WithDropLegacyTestWAL(true))
+	assert.False(t, hasTestWAL(t, m, "legacy.db"))
+	// Tables that only share the name, or hold data, are somebody's
+	assert.True(t, hasTestWAL(t, m, "mine.db"))
+	assert.True(t, hasTestWAL(t, m, "used.db"))
+	assert.False(t, hasTestWAL(t, m, "fresh.db"))
+}
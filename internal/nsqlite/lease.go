@@ -0,0 +1,122 @@
+package nsqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+
+	"github.com/jackc/puddle/v2"
+	"github.com/mattn/go-sqlite3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Lease is a connection checked out of a database. It must be released
+// exactly once, usually deferred right after Acquire, so the database never
+// runs out of connections.
+type Lease struct {
+	res      *puddle.Resource[*sql.Conn]
+	access   Access
+	unref    func() // lets the database be evicted again, if set
+	discard  atomic.Bool
+	released atomic.Bool
+}
+
+// Acquire leases a connection of dbName for access, opening the database
+// first if needed. It waits for a free connection until ctx is done. The
+// database isn't evicted while the lease is out.
+func (s *DBManager) Acquire(ctx context.Context, dbName string, access Access) (*Lease, error) {
+	return s.acquire(ctx, dbName, access, true)
+}
+
+// acquire is Acquire, counting as a use of the database only if use is set,
+// see hold.
+func (s *DBManager) acquire(ctx context.Context, dbName string, access Access, use bool) (*Lease, error) {
+	db, err := s.hold(dbName, use)
+	if err != nil {
+		return nil, err
+	}
+	release := s.unref
+	if !use {
+		release = s.unpeek
+	}
+	lease, err := db.Acquire(ctx, access)
+	if err != nil {
+		release(db)
+		return nil, err
+	}
+	lease.unref = func() { release(db) }
+	return lease, nil
+}
+
+func acquireError(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.Is(err, puddle.ErrClosedPool):
+		return status.Error(codes.Unavailable, "database is closed")
+	default:
+		return status.Errorf(codes.Unavailable, "failed to acquire database: %v", err)
+	}
+}
+
+// Access reports which connections of the database the lease is from.
+func (l *Lease) Access() Access {
+	return l.access
+}
+
+// Conn returns the leased connection.
+func (l *Lease) Conn() *sql.Conn {
+	return l.res.Value()
+}
+
+// Check makes Release close the connection if err shows it can't be trusted
+// anymore, and returns err.
+func (l *Lease) Check(err error) error {
+	if broken(err) {
+		l.Discard()
+	}
+	return err
+}
+
+// Discard makes Release close the connection rather than hand it back, for
+// when it was left in an unknown state.
+func (l *Lease) Discard() {
+	l.discard.Store(true)
+}
+
+// Release hands the connection back, or closes it if it was discarded, in
+// which case a new one takes its place. Only the first call does anything.
+func (l *Lease) Release() {
+	if !l.released.CompareAndSwap(false, true) {
+		return
+	}
+	if l.unref != nil {
+		defer l.unref()
+	}
+	if l.discard.Load() {
+		// Makes database/sql throw the connection away instead of reusing it
+		l.res.Value().Raw(func(any) error { return driver.ErrBadConn })
+		l.res.Destroy()
+		return
+	}
+	l.res.Release()
+}
+
+// broken reports whether err leaves the connection it came from unfit for
+// further use.
+func broken(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code {
+		case sqlite3.ErrCorrupt, sqlite3.ErrNotADB, sqlite3.ErrIoErr, sqlite3.ErrCantOpen:
+			return true
+		}
+	}
+	return false
+}
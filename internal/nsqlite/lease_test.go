@@ -0,0 +1,59 @@
+package nsqlite_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDBManager_Acquire(t *testing.T) {
+	m := nsqlite.NewManager(t.TempDir(), nsqlite.WithSettings(nsqlite.Settings{PoolSize: 1}, nil))
+	db, err := m.Open("lease.db")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	lease, err := m.Acquire(ctx, "lease.db", nsqlite.Read)
+	require.NoError(t, err)
+	require.NoError(t, lease.Conn().PingContext(ctx))
+
+	// The only read-only connection is leased out
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = m.Acquire(waitCtx, "lease.db", nsqlite.Read)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	// Releasing twice doesn't hand it back twice
+	lease.Release()
+	lease.Release()
+	assert.Equal(t, int32(0), db.Stats().ReadersBusy)
+
+	// Ordinary errors keep the connection, broken ones close it
+	lease, err = m.Acquire(ctx, "lease.db", nsqlite.Read)
+	require.NoError(t, err)
+	assert.Error(t, lease.Check(errors.New("no such table: t")))
+	lease.Release()
+	assert.Equal(t, int32(1), db.Stats().Readers)
+
+	lease, err = m.Acquire(ctx, "lease.db", nsqlite.Read)
+	require.NoError(t, err)
+	assert.ErrorIs(t, lease.Check(driver.ErrBadConn), driver.ErrBadConn)
+	lease.Release()
+	assert.Eventually(t, func() bool { return db.Stats().Readers == 0 }, time.Second, time.Millisecond)
+
+	// A fresh one takes its place
+	lease, err = m.Acquire(ctx, "lease.db", nsqlite.Read)
+	require.NoError(t, err)
+	lease.Release()
+
+	require.NoError(t, db.Close(context.Background()))
+	_, err = m.Acquire(ctx, "lease.db", nsqlite.Read)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
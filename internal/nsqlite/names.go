@@ -0,0 +1,58 @@
+package nsqlite
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxNameLength keeps database names well under filesystem limits.
+const maxNameLength = 128
+
+// sidecarSuffixes are the files SQLite keeps next to a database, a database
+// named after one would clobber another database's journal.
+var sidecarSuffixes = []string{"-wal", "-shm", "-journal"}
+
+// reservedNames can't be used as file names on Windows.
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// ValidateName checks that a client supplied database name is a plain file
+// name that stays inside the data directory: letters, digits, '.', '_' and
+// '-' only, starting with a letter or digit, and no "..".
+func ValidateName(name string) error {
+	if name == "" {
+		return status.Error(codes.InvalidArgument, "database_name is required")
+	}
+	if len(name) > maxNameLength {
+		return status.Errorf(codes.InvalidArgument, "database name is longer than %d characters", maxNameLength)
+	}
+
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case i > 0 && (r == '.' || r == '_' || r == '-'):
+		default:
+			return status.Errorf(codes.InvalidArgument, "invalid database name %q: only letters, digits, '.', '_' and '-' are allowed, starting with a letter or digit", name)
+		}
+	}
+	if strings.Contains(name, "..") {
+		return status.Errorf(codes.InvalidArgument, `invalid database name %q: must not contain ".."`, name)
+	}
+
+	lower := strings.ToLower(name)
+	for _, suffix := range sidecarSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return status.Errorf(codes.InvalidArgument, "invalid database name %q: %s files are reserved for SQLite", name, suffix)
+		}
+	}
+	base, _, _ := strings.Cut(name, ".")
+	if reservedNames[strings.ToUpper(base)] {
+		return status.Errorf(codes.InvalidArgument, "invalid database name %q: reserved name", name)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package nsqlite_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateName(t *testing.T) {
+	valid := []string{"wowdb.db", "analytics-2024", "a", "Sales_EU.sqlite3", "1st.db"}
+	for _, name := range valid {
+		assert.NoError(t, nsqlite.ValidateName(name), name)
+	}
+
+	invalid := []string{
+		"",
+		"../../etc/passwd",
+		"/etc/passwd",
+		"dir/db",
+		`dir\db`,
+		"..",
+		"a..b",
+		".hidden",
+		"-flag",
+		"db name",
+		"db\x00",
+		"données.db",
+		"wowdb.db-wal",
+		"wowdb.db-journal",
+		"CON",
+		"nul.db",
+		string(make([]byte, 200)),
+	}
+	for _, name := range invalid {
+		err := nsqlite.ValidateName(name)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "%q", name)
+	}
+}
+
+func TestDBManager_MustExist(t *testing.T) {
+	dir := t.TempDir()
+	m := nsqlite.NewManager(dir, nsqlite.WithAutoCreate(false))
+
+	_, err := m.Open("missing.db")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, statErr := os.Stat(filepath.Join(dir, "missing.db"))
+	assert.ErrorIs(t, statErr, os.ErrNotExist, "the database must not have been created")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "present.db"), nil, 0o644))
+	db, err := m.Open("present.db")
+	require.NoError(t, err)
+	require.NoError(t, db.Close(context.Background()))
+
+	_, err = m.Open("../present.db")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -1,56 +1,239 @@
 package nsqlite
 
 import (
+	"container/list"
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/jackc/puddle/v2"
+	"github.com/mattn/go-sqlite3"
 )
 
-func CreateOrOpen(path string) (*sql.DB, error) {
-	db, err := sql.Open("sqlite3", path+"?_journal_mode=WAL&_busy_timeout=5000")
-	if err != nil {
-		slog.Error("Failed to open database", "error", err)
+// connector opens connections to a database with the pragmas of its
+// settings applied, before database/sql ever hands them out.
+type connector struct {
+	driver *sqlite3.SQLiteDriver
+	dsn    string
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// Access picks which connections of a database a lease comes from.
+type Access int
+
+const (
+	// Read leases one of the read-only connections, many can be out at once.
+	Read Access = iota
+	// Write leases the writer connection, one lease at a time in the order
+	// they were asked for.
+	Write
+)
+
+// Database is an open SQLite database. Every write goes through its single
+// writer connection, so writers queue up here rather than retrying on
+// SQLITE_BUSY, while reads are spread over a bounded pool of read-only
+// connections that never block it in WAL mode.
+type Database struct {
+	path string
+	wal  bool
+
+	writeDB *sql.DB
+	writer  *puddle.Pool[*sql.Conn] // of size 1, queues writers first come first served
+	readDB  *sql.DB
+	readers *puddle.Pool[*sql.Conn]
+
+	mu     sync.Mutex
+	closed bool
+
+	// Guarded by the dbMutex of the DBManager
+	refs     int // leases out, the database isn't evicted until none are
+	lastUsed time.Time
+	elem     *list.Element // in the LRU list of the DBManager
+}
+
+// openDatabase opens the database at path, checking it can be written to.
+// settings.PoolSize is the number of read-only connections.
+func openDatabase(ctx context.Context, path string, settings Settings) (*Database, error) {
+	writeDB := sql.OpenDB(&connector{
+		driver: &sqlite3.SQLiteDriver{ConnectHook: settings.connectHook},
+		dsn:    settings.dsn(path),
+	})
+	writeDB.SetMaxOpenConns(1)
+
+	if err := checkHealth(ctx, writeDB, settings); err != nil {
+		slog.Error("Database failed its health check", "database", path, "error", err)
+		writeDB.Close()
 		return nil, err
 	}
 
-	// Verify WAL mode is enabled
-	var journalMode string
-	err = db.QueryRow("PRAGMA journal_mode;").Scan(&journalMode)
+	readDB := sql.OpenDB(&connector{
+		driver: &sqlite3.SQLiteDriver{ConnectHook: settings.readerHook},
+		dsn:    settings.dsn(path),
+	})
+	readDB.SetMaxOpenConns(int(settings.PoolSize))
+	readDB.SetMaxIdleConns(int(settings.PoolSize))
+
+	d := &Database{
+		path:    path,
+		wal:     strings.EqualFold(settings.JournalMode, "WAL"),
+		writeDB: writeDB,
+		readDB:  readDB,
+	}
+	var err error
+	if d.writer, err = connPool(writeDB, 1); err == nil {
+		d.readers, err = connPool(readDB, settings.PoolSize)
+	}
+	if err == nil {
+		err = readDB.PingContext(ctx)
+	}
 	if err != nil {
-		slog.Error("Failed to query PRAGMA Journal mode", "error", err)
-		return nil, err
+		d.closePools()
+		d.closeDBs()
+		return nil, fmt.Errorf("opening read-only connections: %w", err)
+	}
+
+	slog.Info("Opened database", "database", path, "journal_mode", settings.JournalMode, "readers", settings.PoolSize)
+	return d, nil
+}
+
+// connPool pins up to size connections of db, for leases to take turns on.
+func connPool(db *sql.DB, size int32) (*puddle.Pool[*sql.Conn], error) {
+	return puddle.NewPool(&puddle.Config[*sql.Conn]{
+		Constructor: func(ctx context.Context) (*sql.Conn, error) {
+			return db.Conn(ctx)
+		},
+		Destructor: func(conn *sql.Conn) {
+			if err := conn.Close(); err != nil {
+				slog.Error("Failed to close connection", "error", err)
+			}
+		},
+		MaxSize: size,
+	})
+}
+
+// Acquire leases a connection of the database, waiting until one is free or
+// ctx is done. It fails with Unavailable once the database is closing.
+func (d *Database) Acquire(ctx context.Context, access Access) (*Lease, error) {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil, acquireError(puddle.ErrClosedPool)
 	}
-	slog.Info("Database journal mode", "mode", journalMode, "database", path)
+	d.mu.Unlock()
 
-	_, err = db.Exec("CREATE TABLE IF NOT EXISTS _test_wal (id INTEGER PRIMARY KEY);")
+	pool := d.readers
+	if access == Write {
+		pool = d.writer
+	}
+	res, err := pool.Acquire(ctx)
 	if err != nil {
-		slog.Error("Failed to query PRAGMA Journal mode", "error", err)
-		return nil, err
+		return nil, acquireError(err)
 	}
+	return &Lease{res: res, access: access}, nil
+}
 
-	slog.Info("Created database successfully", "database", path)
-	return db, nil
+// Stats is a snapshot of how the connections of a database are used.
+type Stats struct {
+	Readers     int32 // read-only connections open
+	ReadersBusy int32 // read-only connections leased out
+	MaxReaders  int32
+	WriterBusy  bool // whether the writer connection is leased out
 }
 
-func NewPool(ctx context.Context, dbPath string) (*puddle.Pool[*sql.DB], error) {
-	slog.Info("Creating new pool", "database", dbPath)
+// Stats reports how the connections of the database are used right now.
+func (d *Database) Stats() Stats {
+	readers := d.readers.Stat()
+	return Stats{
+		Readers:     readers.TotalResources(),
+		ReadersBusy: readers.AcquiredResources(),
+		MaxReaders:  readers.MaxResources(),
+		WriterBusy:  d.writer.Stat().AcquiredResources() > 0,
+	}
+}
 
-	constructor := func(context.Context) (*sql.DB, error) {
-		return CreateOrOpen(dbPath)
+// Close stops handing out connections and waits for the leased ones to be
+// released, or for ctx to be done. It then checkpoints the WAL into the
+// database file and closes every connection. Only the first call does
+// anything.
+func (d *Database) Close(ctx context.Context) error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
 	}
-	destructor := func(value *sql.DB) {
-		if err := value.Close(); err != nil {
-			slog.Error("Failed to close db", "error", err)
-		}
+	d.closed = true
+	d.mu.Unlock()
+
+	// Closing the pools fails the acquisitions still waiting for a
+	// connection right away, but only returns once every lease is released
+	drained := make(chan struct{})
+	go func() {
+		d.closePools()
+		close(drained)
+	}()
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = fmt.Errorf("connections still leased out: %w", ctx.Err())
 	}
 
-	// TODO: Make it configurable
-	return puddle.NewPool(&puddle.Config[*sql.DB]{
-		Constructor: constructor,
-		Destructor:  destructor,
-		MaxSize:     int32(5),
-	})
+	// Readers in the middle of a read transaction would keep the
+	// checkpoint from truncating the WAL
+	if closeErr := d.readDB.Close(); closeErr != nil {
+		err = errors.Join(err, closeErr)
+	}
+	if err == nil && d.wal {
+		err = d.checkpoint(ctx)
+	}
+	if closeErr := d.writeDB.Close(); closeErr != nil {
+		err = errors.Join(err, closeErr)
+	}
+	return err
+}
+
+// checkpoint copies every frame of the WAL into the database file and
+// truncates the WAL.
+func (d *Database) checkpoint(ctx context.Context) error {
+	var busy, frames, checkpointed int
+	err := d.writeDB.QueryRowContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &frames, &checkpointed)
+	if err != nil {
+		return fmt.Errorf("checkpointing: %w", err)
+	}
+	if busy != 0 {
+		return errors.New("checkpoint blocked by another process using the database")
+	}
+	slog.Info("Checkpointed database", "database", d.path, "frames", checkpointed)
+	return nil
+}
 
+func (d *Database) closePools() {
+	if d.readers != nil {
+		d.readers.Close()
+	}
+	if d.writer != nil {
+		d.writer.Close()
+	}
+}
+
+func (d *Database) closeDBs() {
+	if err := d.readDB.Close(); err != nil {
+		slog.Error("Failed to close db", "database", d.path, "error", err)
+	}
+	if err := d.writeDB.Close(); err != nil {
+		slog.Error("Failed to close db", "database", d.path, "error", err)
+	}
 }
@@ -0,0 +1,100 @@
+package nsqlite_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDatabase_SingleWriter(t *testing.T) {
+	// Writers would give up on each other almost right away if they raced
+	// for the SQLite lock instead of queueing for the writer
+	m := nsqlite.NewManager(t.TempDir(), nsqlite.WithSettings(nsqlite.Settings{BusyTimeout: time.Millisecond, PoolSize: 2}, nil))
+	db, err := m.Open("queue.db")
+	require.NoError(t, err)
+	defer db.Close(context.Background())
+	ctx := context.Background()
+
+	writer, err := db.Acquire(ctx, nsqlite.Write)
+	require.NoError(t, err)
+	_, err = writer.Conn().ExecContext(ctx, `CREATE TABLE t (id INTEGER PRIMARY KEY, n INTEGER)`)
+	require.NoError(t, err)
+	_, err = writer.Conn().ExecContext(ctx, `BEGIN IMMEDIATE`)
+	require.NoError(t, err)
+	assert.True(t, db.Stats().WriterBusy)
+
+	// Other writers wait their turn
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = db.Acquire(waitCtx, nsqlite.Write)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	// Readers don't, and can't write
+	reader, err := db.Acquire(ctx, nsqlite.Read)
+	require.NoError(t, err)
+	var n int
+	require.NoError(t, reader.Conn().QueryRowContext(ctx, `SELECT count(*) FROM t`).Scan(&n))
+	_, err = reader.Conn().ExecContext(ctx, `INSERT INTO t (n) VALUES (1)`)
+	var sqliteErr sqlite3.Error
+	require.True(t, errors.As(err, &sqliteErr), "%v", err)
+	assert.Equal(t, sqlite3.ErrReadonly, sqliteErr.Code)
+	reader.Release()
+
+	_, err = writer.Conn().ExecContext(ctx, `COMMIT`)
+	require.NoError(t, err)
+	writer.Release()
+
+	const workers = 100
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*workers)
+	for i := range workers {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			lease, err := db.Acquire(ctx, nsqlite.Write)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer lease.Release()
+			_, err = lease.Conn().ExecContext(ctx, `INSERT INTO t (n) VALUES (?)`, i)
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			lease, err := db.Acquire(ctx, nsqlite.Read)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer lease.Release()
+			var n int
+			errs <- lease.Conn().QueryRowContext(ctx, `SELECT count(*) FROM t`).Scan(&n)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	stats := db.Stats()
+	assert.LessOrEqual(t, stats.Readers, int32(2))
+	assert.False(t, stats.WriterBusy)
+	assert.Zero(t, stats.ReadersBusy)
+
+	reader, err = db.Acquire(ctx, nsqlite.Read)
+	require.NoError(t, err)
+	defer reader.Release()
+	require.NoError(t, reader.Conn().QueryRowContext(ctx, `SELECT count(*) FROM t`).Scan(&n))
+	assert.Equal(t, workers, n)
+}
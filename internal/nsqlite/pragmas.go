@@ -0,0 +1,69 @@
+package nsqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// reportedPragmas are always reported by ReadPragmas, configured or not.
+var reportedPragmas = []string{
+	"busy_timeout",
+	"cache_size",
+	"foreign_keys",
+	"journal_mode",
+	"mmap_size",
+	"synchronous",
+	"temp_store",
+}
+
+// Pragma is the value of a pragma on a connection, next to the value the
+// settings asked for.
+type Pragma struct {
+	Name       string
+	Value      string // empty if SQLite reports nothing
+	Configured string // empty if the settings leave it alone
+}
+
+// ReadPragmas reports the common pragmas and every pragma configured in
+// settings as conn sees them, sorted by name.
+func ReadPragmas(ctx context.Context, conn *sql.Conn, settings Settings) ([]Pragma, error) {
+	configured := map[string]string{
+		"journal_mode": strings.ToLower(settings.JournalMode),
+		"busy_timeout": strconv.FormatInt(settings.BusyTimeout.Milliseconds(), 10),
+	}
+	for name, value := range settings.Pragmas {
+		configured[name] = value
+	}
+
+	names := append([]string(nil), reportedPragmas...)
+	for name := range configured {
+		if !slices.Contains(reportedPragmas, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	pragmas := make([]Pragma, 0, len(names))
+	for _, name := range names {
+		var value any
+		err := conn.QueryRowContext(ctx, "PRAGMA "+name).Scan(&value)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("reading pragma %s: %w", name, err)
+		}
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		p := Pragma{Name: name, Configured: configured[name]}
+		if value != nil {
+			p.Value = fmt.Sprint(value)
+		}
+		pragmas = append(pragmas, p)
+	}
+	return pragmas, nil
+}
@@ -0,0 +1,223 @@
+package nsqlite
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// restoreDrainTimeout is how long Restore waits for the requests,
+// transactions and cursors using the database to be done before giving up.
+const restoreDrainTimeout = 10 * time.Second
+
+// restorePollInterval is how often Restore checks whether the database is
+// still in use.
+const restorePollInterval = 10 * time.Millisecond
+
+// Restore replaces the database named dbName, or creates it, with the
+// SQLite file at src, which it takes over. src must be in the data
+// directory, see CreateTemp, for the swap to be a rename.
+//
+// src is checked first. New requests for the database then fail with
+// Unavailable while the ones using it finish, after which it's closed, its
+// files replaced and it's opened again. If it's still in use after
+// restoreDrainTimeout, Restore fails with Aborted and leaves it as it was.
+// So does failing to close it cleanly or to swap the files, with Internal.
+func (s *DBManager) Restore(ctx context.Context, dbName, src string) error {
+	if err := ValidateName(dbName); err != nil {
+		return err
+	}
+	if err := checkSnapshot(ctx, src); err != nil {
+		return err
+	}
+	dbpath := s.path(dbName)
+
+	s.dbMutex.Lock()
+	s.waitPending(dbpath)
+	if s.restoring[dbpath] {
+		s.dbMutex.Unlock()
+		return status.Errorf(codes.Aborted, "database %s is already being restored", dbName)
+	}
+	s.restoring[dbpath] = true
+	defer func() {
+		s.dbMutex.Lock()
+		delete(s.restoring, dbpath)
+		s.dbMutex.Unlock()
+	}()
+
+	// Nothing is touched until the database is no longer in use, so giving
+	// up leaves it open and working for the leases still out
+	drainCtx, cancel := context.WithTimeout(ctx, restoreDrainTimeout)
+	defer cancel()
+	var db *Database
+	var open bool
+	for {
+		s.waitPending(dbpath)
+		if s.closed {
+			s.dbMutex.Unlock()
+			return status.Error(codes.Unavailable, "server is shutting down")
+		}
+		db, open = s.dbHandles[dbpath]
+		if !open || db.refs == 0 {
+			break
+		}
+		s.dbMutex.Unlock()
+		select {
+		case <-drainCtx.Done():
+			slog.Error("Restore aborted", "database", dbName, "error", drainCtx.Err())
+			return status.Errorf(codes.Aborted, "database %s is still in use, restore aborted", dbName)
+		case <-time.After(restorePollInterval):
+		}
+		s.dbMutex.Lock()
+	}
+	if open {
+		delete(s.dbHandles, dbpath)
+		s.lru.Remove(db.elem)
+		s.lastUsed[dbpath] = db.lastUsed
+	}
+	done := make(chan struct{})
+	s.closing[dbpath] = done
+	s.dbMutex.Unlock()
+
+	defer func() {
+		s.dbMutex.Lock()
+		delete(s.closing, dbpath)
+		s.dbMutex.Unlock()
+		close(done)
+	}()
+
+	if open {
+		// Nothing is leased out, so this only waits for the checkpoint. The
+		// old files are only replaced once it's done
+		if err := db.Close(ctx); err != nil {
+			slog.Error("Restore aborted, failed to close database", "database", dbName, "error", err)
+			s.dbMutex.Lock()
+			defer s.dbMutex.Unlock()
+			if _, openErr := s.open(dbName, dbpath); openErr != nil {
+				slog.Error("Failed to reopen database", "database", dbName, "error", openErr)
+			}
+			return status.Errorf(codes.Internal, "failed to close database %s, restore aborted: %v", dbName, err)
+		}
+	}
+
+	if err := replaceFiles(s.datadir, src, dbpath); err != nil {
+		slog.Error("Restore failed", "database", dbName, "error", err)
+		return status.Errorf(codes.Internal, "failed to replace database %s: %v", dbName, err)
+	}
+	slog.Info("Restored database", "database", dbName)
+
+	// Opened right away, so a file SQLite can't use shows up here rather
+	// than on the next request
+	s.dbMutex.Lock()
+	defer s.dbMutex.Unlock()
+	_, err := s.open(dbName, dbpath)
+	return err
+}
+
+// checkSnapshot makes sure the file at path is a sound SQLite database,
+// InvalidArgument otherwise.
+func checkSnapshot(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to read snapshot: %v", err)
+	}
+	magic := make([]byte, len(headerMagic))
+	_, err = io.ReadFull(f, magic)
+	f.Close()
+	if err != nil || !bytes.Equal(magic, headerMagic) {
+		return status.Error(codes.InvalidArgument, "snapshot is not an SQLite database")
+	}
+
+	db := sql.OpenDB(&connector{driver: &sqlite3.SQLiteDriver{}, dsn: path})
+	defer db.Close()
+	rows, err := db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "snapshot failed its integrity check: %v", err)
+	}
+	defer rows.Close()
+	var problems []string
+	for rows.Next() {
+		var problem string
+		if err := rows.Scan(&problem); err != nil {
+			return status.Errorf(codes.InvalidArgument, "snapshot failed its integrity check: %v", err)
+		}
+		problems = append(problems, problem)
+	}
+	if err := rows.Err(); err != nil {
+		return status.Errorf(codes.InvalidArgument, "snapshot failed its integrity check: %v", err)
+	}
+	if len(problems) != 1 || problems[0] != "ok" {
+		return status.Errorf(codes.InvalidArgument, "snapshot failed its integrity check: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// replaceFiles swaps the file at src in as the database at dbpath, in the
+// data directory dir. The old database and its journals are moved aside
+// rather than deleted, any journal left next to the new file would be
+// applied to it, and only deleted once the new file is durably in place.
+// They are put back if anything fails; a crash midway leaves them aside,
+// under names starting with '.'.
+func replaceFiles(dir, src, dbpath string) error {
+	aside := func(suffix string) string {
+		return filepath.Join(dir, "."+filepath.Base(dbpath)+suffix+".old")
+	}
+	var moved []string
+	putBack := func() {
+		for _, suffix := range moved {
+			if err := os.Rename(aside(suffix), dbpath+suffix); err != nil {
+				slog.Error("Failed to put back database file", "file", dbpath+suffix, "error", err)
+			}
+		}
+		if err := syncDir(dir); err != nil {
+			slog.Warn("Failed to sync directory", "dir", dir, "error", err)
+		}
+	}
+
+	for _, suffix := range append([]string{""}, sidecarSuffixes...) {
+		err := os.Rename(dbpath+suffix, aside(suffix))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			putBack()
+			return err
+		}
+		moved = append(moved, suffix)
+	}
+	err := os.Rename(src, dbpath)
+	if err == nil {
+		err = syncDir(dir)
+	}
+	if err != nil {
+		putBack()
+		return err
+	}
+
+	for _, suffix := range moved {
+		if err := os.Remove(aside(suffix)); err != nil {
+			slog.Warn("Failed to delete replaced database file", "file", aside(suffix), "error", err)
+		}
+	}
+	return nil
+}
+
+// syncDir makes a rename in dir durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,119 @@
+package nsqlite_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alfredosa/netsqlite/internal/nsqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDBManager_Restore(t *testing.T) {
+	dir := t.TempDir()
+	m := nsqlite.NewManager(dir)
+	defer m.Close(context.Background())
+	ctx := context.Background()
+
+	count := func() int {
+		lease, err := m.Acquire(ctx, "live.db", nsqlite.Read)
+		require.NoError(t, err)
+		defer lease.Release()
+		var n int
+		require.NoError(t, lease.Conn().QueryRowContext(ctx, `SELECT count(*) FROM t`).Scan(&n))
+		return n
+	}
+	exec := func(query string) {
+		lease, err := m.Acquire(ctx, "live.db", nsqlite.Write)
+		require.NoError(t, err)
+		defer lease.Release()
+		_, err = lease.Conn().ExecContext(ctx, query)
+		require.NoError(t, err)
+	}
+	snapshot := func() string {
+		f, err := m.CreateTemp(".restore-*")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		require.NoError(t, os.Remove(f.Name()))
+		require.NoError(t, m.Backup(ctx, "live.db", f.Name()))
+		return f.Name()
+	}
+
+	exec(`CREATE TABLE t (n INTEGER); INSERT INTO t VALUES (1), (2)`)
+	src := snapshot()
+	exec(`INSERT INTO t VALUES (3), (4), (5)`)
+	require.Equal(t, 5, count())
+
+	// The database is open with rows in its WAL, none of them survive
+	require.NoError(t, m.Restore(ctx, "live.db", src))
+	assert.NoFileExists(t, src)
+	assert.Equal(t, 2, count())
+	// The old files moved aside are gone once the new one is in place
+	aside, err := filepath.Glob(filepath.Join(dir, ".live.db*"))
+	require.NoError(t, err)
+	assert.Empty(t, aside)
+
+	// Files that aren't sound databases are turned down, the database stays
+	garbage, err := m.CreateTemp(".restore-*")
+	require.NoError(t, err)
+	_, err = garbage.WriteString("definitely not a database")
+	require.NoError(t, err)
+	require.NoError(t, garbage.Close())
+	err = m.Restore(ctx, "live.db", garbage.Name())
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	truncated := snapshot()
+	require.NoError(t, os.Truncate(truncated, 4096+100))
+	err = m.Restore(ctx, "live.db", truncated)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, 2, count())
+
+	// Requests during the swap fail rather than wait, and the swap waits for
+	// the ones in progress
+	held, err := m.Acquire(ctx, "live.db", nsqlite.Read)
+	require.NoError(t, err)
+	src = snapshot()
+	restored := make(chan error, 1)
+	go func() { restored <- m.Restore(ctx, "live.db", src) }()
+	require.Eventually(t, func() bool {
+		lease, err := m.Acquire(ctx, "live.db", nsqlite.Read)
+		if err == nil {
+			lease.Release()
+		}
+		return status.Code(err) == codes.Unavailable
+	}, 5*time.Second, 10*time.Millisecond)
+	held.Release()
+	require.NoError(t, <-restored)
+	assert.Equal(t, 2, count())
+
+	// Unless they take too long, then the database is left as it was, for
+	// the leases still out as for new ones
+	held, err = m.Acquire(ctx, "live.db", nsqlite.Write)
+	require.NoError(t, err)
+	defer held.Release()
+	src = snapshot()
+	_, err = held.Conn().ExecContext(ctx, `INSERT INTO t VALUES (3)`)
+	require.NoError(t, err)
+	opened := m.Stats().Opened
+	short, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	err = m.Restore(short, "live.db", src)
+	assert.Equal(t, codes.Aborted, status.Code(err))
+	_, err = held.Conn().ExecContext(ctx, `INSERT INTO t VALUES (4)`)
+	require.NoError(t, err)
+	held.Release()
+	assert.Equal(t, 4, count())
+	assert.Equal(t, opened, m.Stats().Opened)
+
+	// A database that doesn't exist yet is created
+	src = snapshot()
+	require.NoError(t, m.Restore(ctx, "copy.db", src))
+	info, err := m.Info(ctx, "copy.db")
+	require.NoError(t, err)
+	assert.True(t, info.Open)
+}
@@ -0,0 +1,150 @@
+package nsqlite
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Settings configure the connections a database opens.
+// Zero fields fall back to the defaults.
+type Settings struct {
+	PoolSize    int32         // Max read-only connections, writes always share a single one
+	BusyTimeout time.Duration // How long to wait on a locked database
+	JournalMode string        // e.g. WAL or DELETE
+
+	// Pragmas are run on every new connection, e.g. foreign_keys: ON.
+	// Per database pragmas are merged over the default ones.
+	Pragmas map[string]string
+}
+
+// DefaultSettings are used for every database unless configured otherwise.
+func DefaultSettings() Settings {
+	return Settings{
+		PoolSize:    5,
+		BusyTimeout: 5 * time.Second,
+		JournalMode: "WAL",
+	}
+}
+
+var journalModes = map[string]bool{
+	"DELETE":   true,
+	"TRUNCATE": true,
+	"PERSIST":  true,
+	"MEMORY":   true,
+	"WAL":      true,
+	"OFF":      true,
+}
+
+// Or returns s with its zero fields taken from fallback.
+func (s Settings) Or(fallback Settings) Settings {
+	if s.PoolSize == 0 {
+		s.PoolSize = fallback.PoolSize
+	}
+	if s.BusyTimeout == 0 {
+		s.BusyTimeout = fallback.BusyTimeout
+	}
+	if s.JournalMode == "" {
+		s.JournalMode = fallback.JournalMode
+	}
+	if len(fallback.Pragmas) > 0 {
+		pragmas := make(map[string]string, len(fallback.Pragmas)+len(s.Pragmas))
+		for name, value := range fallback.Pragmas {
+			pragmas[name] = value
+		}
+		for name, value := range s.Pragmas {
+			pragmas[name] = value
+		}
+		s.Pragmas = pragmas
+	}
+	return s
+}
+
+// Validate reports every invalid field of s, zero fields are valid.
+func (s Settings) Validate() error {
+	var errs []error
+	if s.PoolSize < 0 {
+		errs = append(errs, fmt.Errorf("pool size %d must not be negative", s.PoolSize))
+	}
+	if s.BusyTimeout < 0 {
+		errs = append(errs, fmt.Errorf("busy timeout %s must not be negative", s.BusyTimeout))
+	}
+	if s.JournalMode != "" && !journalModes[strings.ToUpper(s.JournalMode)] {
+		errs = append(errs, fmt.Errorf("unknown journal mode %q", s.JournalMode))
+	}
+	for _, name := range s.pragmaNames() {
+		if err := validatePragma(name, s.Pragmas[name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+var (
+	pragmaName  = regexp.MustCompile(`^[a-z_]+$`)
+	pragmaValue = regexp.MustCompile(`^[A-Za-z0-9_+-]+$`)
+)
+
+// managedPragmas are set through other settings or by the server itself.
+var managedPragmas = map[string]string{
+	"journal_mode": "use the journal mode setting",
+	"busy_timeout": "use the busy timeout setting",
+	"query_only":   "the server sets it on read-only connections",
+}
+
+// validatePragma only lets through plain names and values, since they end
+// up in the PRAGMA statement as they are.
+func validatePragma(name, value string) error {
+	if !pragmaName.MatchString(name) {
+		return fmt.Errorf("invalid pragma name %q", name)
+	}
+	if reason, ok := managedPragmas[name]; ok {
+		return fmt.Errorf("pragma %s can't be set, %s", name, reason)
+	}
+	if !pragmaValue.MatchString(value) {
+		return fmt.Errorf("pragma %s: invalid value %q", name, value)
+	}
+	return nil
+}
+
+// pragmaNames returns the names of the configured pragmas, sorted so they
+// always run in the same order.
+func (s Settings) pragmaNames() []string {
+	names := make([]string, 0, len(s.Pragmas))
+	for name := range s.Pragmas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// connectHook runs the configured pragmas on a new connection.
+func (s Settings) connectHook(conn *sqlite3.SQLiteConn) error {
+	for _, name := range s.pragmaNames() {
+		if _, err := conn.Exec(fmt.Sprintf("PRAGMA %s = %s", name, s.Pragmas[name]), nil); err != nil {
+			return fmt.Errorf("setting pragma %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// readerHook is connectHook for read-only connections, which refuse to write.
+func (s Settings) readerHook(conn *sqlite3.SQLiteConn) error {
+	if err := s.connectHook(conn); err != nil {
+		return err
+	}
+	if _, err := conn.Exec("PRAGMA query_only = ON", nil); err != nil {
+		return fmt.Errorf("setting pragma query_only: %w", err)
+	}
+	return nil
+}
+
+// dsn is the go-sqlite3 data source opening path with s.
+func (s Settings) dsn(path string) string {
+	return fmt.Sprintf("%s?_journal_mode=%s&_busy_timeout=%d", path, strings.ToUpper(s.JournalMode), s.BusyTimeout.Milliseconds())
+}
@@ -0,0 +1,154 @@
+package drivers
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+)
+
+// restoreChunkBytes is the size of the chunks Restore uploads.
+const restoreChunkBytes = 1 << 20
+
+// ErrBackupChecksum is returned by Backup when the bytes received don't
+// match the checksum the server sent along.
+var ErrBackupChecksum = errors.New("netsqlite: backup checksum mismatch")
+
+// Backup writes a consistent snapshot of the database of conn to w, a copy
+// of the SQLite file that can be opened as is. It returns the number of
+// bytes written once they are checked against the checksum sent by the
+// server. Only admin tokens can take backups.
+func Backup(ctx context.Context, conn *sql.Conn, w io.Writer) (int64, error) {
+	var n int64
+	err := conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*SQLConn)
+		if !ok {
+			return fmt.Errorf("netsqlite: Backup needs a netsqlite connection, got %T", driverConn)
+		}
+		var err error
+		n, err = c.backup(ctx, w)
+		return err
+	})
+	return n, err
+}
+
+func (c *SQLConn) backup(ctx context.Context, w io.Writer) (int64, error) {
+	if c.closed || c.grpcConn == nil {
+		return 0, driver.ErrBadConn
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := pb.NewAdminServiceClient(c.grpcConn).Backup(ctx, &pb.BackupRequest{DatabaseName: c.dbName})
+	if err != nil {
+		return 0, rpcError("Backup", err)
+	}
+
+	hash := sha256.New()
+	w = io.MultiWriter(w, hash)
+	var n int64
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return n, fmt.Errorf("netsqlite: backup ended before its summary: %w", io.ErrUnexpectedEOF)
+		} else if err != nil {
+			return n, rpcError("Backup", err)
+		}
+
+		switch payload := resp.Payload.(type) {
+		case *pb.BackupResponse_Chunk:
+			written, err := w.Write(payload.Chunk)
+			n += int64(written)
+			if err != nil {
+				return n, err
+			}
+		case *pb.BackupResponse_Summary:
+			if payload.Summary.SizeBytes != n || payload.Summary.Sha256 != hex.EncodeToString(hash.Sum(nil)) {
+				return n, fmt.Errorf("%w: got %d bytes, the server sent %d", ErrBackupChecksum, n, payload.Summary.SizeBytes)
+			}
+			return n, nil
+		}
+	}
+}
+
+// Restore replaces the database of conn with the SQLite file read from r,
+// such as one written by Backup. The server checks the file before swapping
+// it in; meanwhile requests for the database fail with codes.Unavailable.
+// If r is an io.ReadSeeker, such as an *os.File, it's read twice so the
+// server can also check the upload against its checksum. Only admin tokens
+// can restore.
+func Restore(ctx context.Context, conn *sql.Conn, r io.Reader) (int64, error) {
+	var n int64
+	err := conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*SQLConn)
+		if !ok {
+			return fmt.Errorf("netsqlite: Restore needs a netsqlite connection, got %T", driverConn)
+		}
+		var err error
+		n, err = c.restore(ctx, r)
+		return err
+	})
+	return n, err
+}
+
+func (c *SQLConn) restore(ctx context.Context, r io.Reader) (int64, error) {
+	if c.closed || c.grpcConn == nil {
+		return 0, driver.ErrBadConn
+	}
+
+	header := &pb.RestoreHeader{DatabaseName: c.dbName}
+	if seeker, ok := r.(io.ReadSeeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, err
+		}
+		hash := sha256.New()
+		if _, err := io.Copy(hash, seeker); err != nil {
+			return 0, err
+		}
+		if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+			return 0, err
+		}
+		header.Sha256 = hex.EncodeToString(hash.Sum(nil))
+	}
+
+	// Cancelling the stream is how the server learns the upload is incomplete
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := pb.NewAdminServiceClient(c.grpcConn).Restore(ctx)
+	if err != nil {
+		return 0, rpcError("Restore", err)
+	}
+	if err := stream.Send(&pb.RestoreRequest{Payload: &pb.RestoreRequest_Header{Header: header}}); err != nil {
+		return 0, sendError("Restore", stream, err)
+	}
+
+	var n int64
+	for {
+		// A fresh buffer each time, the sent message may still hold the last
+		buf := make([]byte, restoreChunkBytes)
+		read, err := io.ReadFull(r, buf)
+		if read > 0 {
+			chunk := &pb.RestoreRequest{Payload: &pb.RestoreRequest_Chunk{Chunk: buf[:read]}}
+			if err := stream.Send(chunk); err != nil {
+				return n, sendError("Restore", stream, err)
+			}
+			n += int64(read)
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		} else if err != nil {
+			return n, err
+		}
+	}
+	if _, err := stream.CloseAndRecv(); err != nil {
+		return n, rpcError("Restore", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,125 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+)
+
+// Statement is a single statement of a batch run with ExecBatch.
+type Statement struct {
+	SQL  string
+	Args []any
+}
+
+// BatchOptions tweak how ExecBatch runs a batch.
+type BatchOptions struct {
+	// ContinueOnError runs every statement on its own instead of in a single
+	// transaction, so a failure doesn't stop the rest. Not allowed inside a transaction.
+	ContinueOnError bool
+}
+
+// BatchResult is the outcome of a single statement of a batch.
+type BatchResult struct {
+	RowsAffected int64
+	LastInsertID int64
+	Err          error // Set if the statement failed
+}
+
+// BatchError is returned by ExecBatch when a statement of the batch fails.
+type BatchError struct {
+	Index int // Index of the first failing statement
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("netsqlite: batch statement %d failed: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// ExecBatch runs stmts in order in a single round trip. Unless
+// opts.ContinueOnError is set they are applied atomically, if one fails none
+// of them is. If a transaction is open on conn the batch runs inside it.
+//
+// When a statement fails the error is a *BatchError, the results cover every
+// statement that ran up to and including the failing one (all of them with
+// ContinueOnError).
+func ExecBatch(ctx context.Context, conn *sql.Conn, stmts []Statement, opts BatchOptions) ([]BatchResult, error) {
+	var results []BatchResult
+	err := conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*SQLConn)
+		if !ok {
+			return fmt.Errorf("netsqlite: ExecBatch needs a netsqlite connection, got %T", driverConn)
+		}
+		var err error
+		results, err = c.execBatch(ctx, stmts, opts)
+		return err
+	})
+	return results, err
+}
+
+func (c *SQLConn) execBatch(ctx context.Context, stmts []Statement, opts BatchOptions) ([]BatchResult, error) {
+	if c.closed || c.client == nil {
+		return nil, driver.ErrBadConn
+	}
+	if opts.ContinueOnError && c.txID != "" {
+		return nil, errors.New("netsqlite: ContinueOnError can't be used inside a transaction")
+	}
+
+	req := &pb.ExecBatchRequest{
+		DatabaseName:    c.dbName,
+		Statements:      make([]*pb.BatchStatement, len(stmts)),
+		ContinueOnError: opts.ContinueOnError,
+		TransactionId:   c.txID,
+	}
+	for i, st := range stmts {
+		args, err := anyArgsToProto(st.Args)
+		if err != nil {
+			return nil, fmt.Errorf("netsqlite: batch statement %d: %w", i, err)
+		}
+		req.Statements[i] = &pb.BatchStatement{Sql: st.SQL, Args: args}
+	}
+
+	resp, err := c.client.ExecBatch(ctx, req)
+	if err != nil {
+		return nil, rpcError("ExecBatch", err)
+	}
+
+	results := make([]BatchResult, len(resp.Results))
+	for i, r := range resp.Results {
+		results[i] = BatchResult{RowsAffected: r.RowsAffected, LastInsertID: r.LastInsertId}
+		if r.Error != "" {
+			results[i].Err = errors.New(r.Error)
+		}
+	}
+	if resp.Failed {
+		idx := int(resp.FailedIndex)
+		var cause error = errors.New("unknown error")
+		if idx < len(results) && results[idx].Err != nil {
+			cause = results[idx].Err
+		}
+		return results, &BatchError{Index: idx, Err: cause}
+	}
+	return results, nil
+}
+
+// anyArgsToProto converts plain Go arguments the way database/sql would
+// before handing them to the driver.
+func anyArgsToProto(args []any) ([]*pb.SqlValue, error) {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		v, err := driver.DefaultParameterConverter.ConvertValue(arg)
+		if err != nil {
+			return nil, fmt.Errorf("converting arg %d: %w", i, err)
+		}
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return driverNamedValueToProtoValue(named)
+}
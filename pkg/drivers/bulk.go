@@ -0,0 +1,120 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"iter"
+
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Rows are sent to the server in batches cut at whichever limit is hit first.
+const (
+	bulkBatchRows  = 1000
+	bulkBatchBytes = 1 << 20
+)
+
+// BulkInsert streams rows into the given columns of table. The server
+// inserts them with a single prepared INSERT, all in one transaction: an
+// error from rows or from any insert rolls back every row. If a transaction
+// is open on conn the rows are inserted inside it.
+//
+// It returns the number of rows inserted.
+func BulkInsert(ctx context.Context, conn *sql.Conn, table string, columns []string, rows iter.Seq2[[]any, error]) (int64, error) {
+	var n int64
+	err := conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*SQLConn)
+		if !ok {
+			return fmt.Errorf("netsqlite: BulkInsert needs a netsqlite connection, got %T", driverConn)
+		}
+		var err error
+		n, err = c.bulkInsert(ctx, table, columns, rows)
+		return err
+	})
+	return n, err
+}
+
+// BulkInsertRows is BulkInsert for rows already in memory.
+func BulkInsertRows(ctx context.Context, conn *sql.Conn, table string, columns []string, rows [][]any) (int64, error) {
+	return BulkInsert(ctx, conn, table, columns, func(yield func([]any, error) bool) {
+		for _, row := range rows {
+			if !yield(row, nil) {
+				return
+			}
+		}
+	})
+}
+
+func (c *SQLConn) bulkInsert(ctx context.Context, table string, columns []string, rows iter.Seq2[[]any, error]) (int64, error) {
+	if c.closed || c.client == nil {
+		return 0, driver.ErrBadConn
+	}
+
+	// Cancelling the stream is how the server learns it has to roll back
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.BulkInsert(ctx)
+	if err != nil {
+		return 0, rpcError("BulkInsert", err)
+	}
+
+	err = stream.Send(&pb.BulkInsertRequest{Payload: &pb.BulkInsertRequest_Header{Header: &pb.BulkInsertHeader{
+		DatabaseName:  c.dbName,
+		Table:         table,
+		Columns:       columns,
+		TransactionId: c.txID,
+	}}})
+	if err != nil {
+		return 0, sendError("BulkInsert", stream, err)
+	}
+
+	batch := &pb.RowBatch{}
+	batchBytes := 0
+	flush := func() error {
+		if len(batch.Rows) == 0 {
+			return nil
+		}
+		err := stream.Send(&pb.BulkInsertRequest{Payload: &pb.BulkInsertRequest_Rows{Rows: batch}})
+		batch = &pb.RowBatch{}
+		batchBytes = 0
+		return err
+	}
+
+	i := 0
+	for row, err := range rows {
+		if err != nil {
+			return 0, fmt.Errorf("netsqlite: bulk insert row %d: %w", i, err)
+		}
+		if len(row) != len(columns) {
+			return 0, fmt.Errorf("netsqlite: bulk insert row %d has %d values, expected %d", i, len(row), len(columns))
+		}
+		values, err := anyArgsToProto(row)
+		if err != nil {
+			return 0, fmt.Errorf("netsqlite: bulk insert row %d: %w", i, err)
+		}
+
+		pbRow := &pb.Row{Values: values}
+		batch.Rows = append(batch.Rows, pbRow)
+		batchBytes += proto.Size(pbRow)
+		if len(batch.Rows) >= bulkBatchRows || batchBytes >= bulkBatchBytes {
+			if err := flush(); err != nil {
+				return 0, sendError("BulkInsert", stream, err)
+			}
+		}
+		i++
+	}
+	if err := flush(); err != nil {
+		return 0, sendError("BulkInsert", stream, err)
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return 0, rpcError("BulkInsert", err)
+	}
+	return resp.RowsInserted, nil
+}
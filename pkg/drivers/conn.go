@@ -3,14 +3,13 @@ package drivers
 import (
 	"context"
 	"database/sql/driver"
-	"errors"
 	"fmt"
-	"io"
 
 	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
 
 	"google.golang.org/grpc"
-	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // SQLConn represents an active gRPC connection.
@@ -19,6 +18,17 @@ type SQLConn struct {
 	client   pb.NetsqliteServiceClient
 	dbName   string
 	closed   bool
+
+	// sessionID scopes the statements this connection prepares on the server
+	sessionID string
+	// txID is the server side transaction this connection is in, if any
+	txID string
+	// batch is sent along with every query
+	batch *pb.BatchOptions
+	// useCursor pulls query results fetchSize rows at a time from a server
+	// side cursor instead of streaming them
+	useCursor bool
+	fetchSize int32
 }
 
 // Compile-time interface checks
@@ -26,22 +36,8 @@ var _ driver.Conn = &SQLConn{}
 var _ driver.Pinger = &SQLConn{}
 var _ driver.ExecerContext = &SQLConn{}
 var _ driver.QueryerContext = &SQLConn{}
-
-// TODO: var _ driver.ConnPrepareContext = &SQLConn{}
-// TODO: var _ driver.ConnBeginTx = &SQLConn{}
-
-// Helper to convert driver args to proto args
-func driverNamedValueToProtoValue(args []driver.NamedValue) ([]*structpb.Value, error) {
-	protoArgs := make([]*structpb.Value, len(args))
-	var err error
-	for i, arg := range args {
-		protoArgs[i], err = structpb.NewValue(arg.Value)
-		if err != nil {
-			return nil, fmt.Errorf("netsqlite: unsupported arg type %T at index %d: %w", arg.Value, i, err)
-		}
-	}
-	return protoArgs, nil
-}
+var _ driver.ConnBeginTx = &SQLConn{}
+var _ driver.ConnPrepareContext = &SQLConn{}
 
 // Ping verifies the connection via gRPC Ping RPC.
 func (c *SQLConn) Ping(ctx context.Context) error {
@@ -53,7 +49,12 @@ func (c *SQLConn) Ping(ctx context.Context) error {
 	_, err := c.client.Ping(ctx, req)
 	if err != nil {
 		fmt.Printf("Driver: gRPC Ping failed: %v\n", err)
-		return driver.ErrBadConn
+		// Only a broken link is worth retrying on another connection, a bad
+		// token or database name won't get any better
+		if status.Code(err) == codes.Unavailable {
+			return driver.ErrBadConn
+		}
+		return rpcError("Ping", err)
 	}
 	return nil
 }
@@ -71,15 +72,15 @@ func (c *SQLConn) ExecContext(ctx context.Context, query string, args []driver.N
 	}
 
 	req := &pb.ExecRequest{
-		DatabaseName: c.dbName,
-		Sql:          query,
-		Args:         protoArgs,
-		// TODO: Add TransactionID if applicable
+		DatabaseName:  c.dbName,
+		Sql:           query,
+		Args:          protoArgs,
+		TransactionId: c.txID,
 	}
 
 	resp, err := c.client.Exec(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("netsqlite: gRPC Exec failed: %w", err)
+		return nil, rpcError("Exec", err)
 	}
 
 	return &SQLResult{
@@ -100,37 +101,29 @@ func (c *SQLConn) QueryContext(ctx context.Context, query string, args []driver.
 		return nil, err
 	}
 
-	req := &pb.QueryRequest{
-		DatabaseName: c.dbName,
-		Sql:          query,
-		Args:         protoArgs,
-		// TODO: Add TransactionID if applicable
+	// Cursors don't take part in transactions, those queries are always streamed
+	if c.useCursor && c.txID == "" {
+		return c.queryCursor(ctx, query, protoArgs)
 	}
 
-	stream, err := c.client.Query(ctx, req)
-	if err != nil {
-		return nil, fmt.Errorf("netsqlite: gRPC Query failed: %w", err)
+	req := &pb.QueryRequest{
+		DatabaseName:  c.dbName,
+		Sql:           query,
+		Args:          protoArgs,
+		TransactionId: c.txID,
+		Batch:         c.batch,
 	}
 
-	// Receive first message (must be columns or EOF)
-	firstResp, err := stream.Recv()
+	// The stream gets its own context so closing the rows early tells the
+	// server to stop, and frees up the transaction if we're in one.
+	streamCtx, cancel := context.WithCancel(ctx)
+	stream, err := c.client.Query(streamCtx, req)
 	if err != nil {
-		if err == io.EOF { // No rows returned
-			return &SQLRows{closed: true, columns: []string{}}, nil
-		}
-		return nil, fmt.Errorf("netsqlite: failed receiving columns: %w", err)
-	}
-
-	colsResult := firstResp.GetColumns()
-	if colsResult == nil {
-		return nil, errors.New("netsqlite: protocol error - expected Columns first")
+		cancel()
+		return nil, rpcError("Query", err)
 	}
 
-	return &SQLRows{
-		stream:  stream,
-		columns: colsResult.Names,
-		closed:  false,
-	}, nil
+	return newRows(stream, cancel)
 }
 
 // Close terminates the gRPC connection.
@@ -149,17 +142,6 @@ func (c *SQLConn) Close() error {
 	return nil
 }
 
-// --- Stubs for Future Implementation ---
-// TODO: Let's see how it goes. and implement them if it is necessary.
-
-func (c *SQLConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
-	return nil, errors.New("netsqlite: PrepareContext not implemented")
-}
-
-func (c *SQLConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
-	return nil, errors.New("netsqlite: BeginTx not implemented")
-}
-
 func (c *SQLConn) Prepare(query string) (driver.Stmt, error) {
 	return c.PrepareContext(context.Background(), query)
 }
@@ -2,15 +2,17 @@ package drivers
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql/driver"
+	"encoding/hex"
 	"fmt"
 	"time"
 
 	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure" // Use insecure for now
-	// "google.golang.org/grpc/credentials" // Needed for TLS
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 // SQLConnector creates connections.
@@ -31,9 +33,11 @@ func (c *SQLConnector) Connect(ctx context.Context) (driver.Conn, error) {
 
 	var opts []grpc.DialOption
 	if creds.RequireTLS {
-		// TODO: Implement secure TLS credential loading based on Config
-		// Use credentials.NewClientTLSFromCert(...)
-		return nil, fmt.Errorf("netsqlite: TLS configured via DSN but client TLS loading not implemented")
+		tlsConfig, err := c.config.tlsConfig()
+		if err != nil {
+			return nil, fmt.Errorf("netsqlite: %w", err)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	} else {
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
@@ -47,12 +51,25 @@ func (c *SQLConnector) Connect(ctx context.Context) (driver.Conn, error) {
 
 	grpcClient := pb.NewNetsqliteServiceClient(grpcConn)
 
+	sessionID, err := newSessionID()
+	if err != nil {
+		grpcConn.Close()
+		return nil, fmt.Errorf("netsqlite: failed to generate session id: %w", err)
+	}
+
 	// Create SQLConn wrapper BEFORE pinging
 	sqlConn := &SQLConn{
-		grpcConn: grpcConn,
-		client:   grpcClient,
-		dbName:   c.config.DBName,
-		closed:   false,
+		grpcConn:  grpcConn,
+		client:    grpcClient,
+		dbName:    c.config.DBName,
+		sessionID: sessionID,
+		batch: &pb.BatchOptions{
+			MaxRows:  int32(c.config.BatchRows),
+			MaxBytes: c.config.BatchBytes,
+		},
+		useCursor: c.config.Cursor,
+		fetchSize: int32(c.config.FetchSize),
+		closed:    false,
 	}
 
 	// Ping using the connection context to verify auth/connectivity
@@ -70,3 +87,12 @@ func (c *SQLConnector) Connect(ctx context.Context) (driver.Conn, error) {
 func (c *SQLConnector) Driver() driver.Driver {
 	return c.driver
 }
+
+// newSessionID returns a random identifier for a connection's server side state.
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -17,16 +17,20 @@ const (
 type staticCredentials struct {
 	Token        string
 	DatabaseName string
-	// RequireTLS is not yet fully implemented, and this can't be properly used right now
+	// RequireTLS keeps the token from being sent over plaintext connections
 	RequireTLS bool
 }
 
-// GetRequestMetadata attaches auth token and db name to each RPC.
+// GetRequestMetadata attaches auth token and db name to each RPC. Without a
+// token the server authenticates the client certificate instead.
 func (c *staticCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
-	return map[string]string{
-		clientAuthTokenHeader: fmt.Sprintf("Bearer %s", c.Token),
-		clientDatabaseHeader:  c.DatabaseName,
-	}, nil
+	md := map[string]string{
+		clientDatabaseHeader: c.DatabaseName,
+	}
+	if c.Token != "" {
+		md[clientAuthTokenHeader] = fmt.Sprintf("Bearer %s", c.Token)
+	}
+	return md, nil
 }
 
 // RequireTransportSecurity dictates if TLS is mandatory.
@@ -0,0 +1,111 @@
+package drivers
+
+import (
+	"context"
+	"io"
+	"time"
+
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxFetchAttempts bounds how many times a fetch is tried when the link to
+// the server drops. Fetches are safe to retry, the server replays the last
+// batch if it was lost on the way.
+const maxFetchAttempts = 5
+
+// queryCursor runs the query as a server side cursor, rows are pulled in
+// batches of fetchSize instead of being streamed.
+func (c *SQLConn) queryCursor(ctx context.Context, query string, args []*pb.SqlValue) (*SQLRows, error) {
+	resp, err := c.client.OpenCursor(ctx, &pb.OpenCursorRequest{
+		DatabaseName: c.dbName,
+		Sql:          query,
+		Args:         args,
+	})
+	if err != nil {
+		return nil, rpcError("OpenCursor", err)
+	}
+
+	return &SQLRows{
+		src: &cursorSource{
+			ctx:       ctx,
+			conn:      c,
+			id:        resp.CursorId,
+			fetchSize: c.fetchSize,
+		},
+		columns: resp.Columns.GetNames(),
+		types:   resp.Columns.GetTypes(),
+	}, nil
+}
+
+// cursorSource pulls row batches from a server side cursor with FetchCursor.
+type cursorSource struct {
+	ctx       context.Context
+	conn      *SQLConn
+	id        string
+	fetchSize int32
+	seq       int64 // batches received so far
+	done      bool
+}
+
+func (s *cursorSource) recv() ([]*pb.Row, error) {
+	if s.done {
+		return nil, io.EOF
+	}
+	if s.conn.closed || s.conn.client == nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	req := &pb.FetchCursorRequest{
+		DatabaseName: s.conn.dbName,
+		CursorId:     s.id,
+		MaxRows:      s.fetchSize,
+		Sequence:     s.seq,
+	}
+
+	var resp *pb.FetchCursorResponse
+	backoff := 100 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		var err error
+		resp, err = s.conn.client.FetchCursor(s.ctx, req)
+		if err == nil {
+			break
+		}
+		if status.Code(err) != codes.Unavailable || attempt == maxFetchAttempts {
+			return nil, rpcError("FetchCursor", err)
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-s.ctx.Done():
+			return nil, s.ctx.Err()
+		}
+	}
+
+	s.seq++
+	s.done = resp.Done
+	rows := resp.Batch.GetRows()
+	if len(rows) == 0 && s.done {
+		return nil, io.EOF
+	}
+	return rows, nil
+}
+
+// close releases the cursor on the server. An exhausted cursor has already
+// released its connection, this only drops what the server kept for replays.
+func (s *cursorSource) close() error {
+	if s.conn.closed || s.conn.client == nil {
+		return nil
+	}
+	_, err := s.conn.client.CloseCursor(context.Background(), &pb.CloseCursorRequest{
+		DatabaseName: s.conn.dbName,
+		CursorId:     s.id,
+	})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return rpcError("CloseCursor", err)
+	}
+	return nil
+}
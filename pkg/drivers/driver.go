@@ -6,6 +6,7 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -15,11 +16,26 @@ const DriverName = "netsqlite"
 type Config struct {
 	Addr   string // Host:Port of the gRPC server
 	DBName string // Database identifier passed to server
-	Token  string // Auth token
-
-	// UseTLS is not yet implemented
-	UseTLS   bool   // TODO: Flag for enabling TLS (requires more config)
-	RawQuery string // Original query params if needed
+	Token  string // Auth token, may be empty when authenticating with a client certificate
+
+	// UseTLS connects over TLS, verifying the server against TLSCAFile or
+	// the system roots
+	UseTLS        bool
+	TLSCAFile     string // PEM CA of the server certificate
+	TLSCertFile   string // PEM client certificate for mutual TLS
+	TLSKeyFile    string // PEM private key of TLSCertFile
+	TLSServerName string // Name to verify the server certificate against, the host by default
+	RawQuery      string // Original query params if needed
+
+	// Query results come in batches of up to BatchRows rows / BatchBytes bytes,
+	// 0 leaves it to the server
+	BatchRows  int
+	BatchBytes int64
+
+	// Cursor pulls query results FetchSize rows at a time from a server side
+	// cursor rather than streaming them, for long running exports over flaky links
+	Cursor    bool
+	FetchSize int
 }
 
 // SQLDriver implements driver.DriverContext.
@@ -32,8 +48,8 @@ func init() {
 }
 
 // ParseDSN parses the netsqlite DSN string.
-// Format: netsqlite://[host]/[token]?database=[dbname]&tls=[bool]
-// Where tls is optional
+// Format: netsqlite://[host]/[token]?database=[dbname]&tls=[bool]&ca=[file]&cert=[file]&key=[file]&servername=[name]&batch_rows=[int]&batch_bytes=[int]&cursor=[bool]&fetch_size=[int]
+// Where everything but database is optional
 func ParseDSN(dsn string) (*Config, error) {
 	u, err := url.Parse(dsn)
 	if err != nil {
@@ -55,11 +71,6 @@ func ParseDSN(dsn string) (*Config, error) {
 		return nil, fmt.Errorf("gRPC server address (host:port) missing or invalid in DSN host part")
 	}
 
-	token := strings.TrimPrefix(u.Path, "/")
-	if token == "" {
-		return nil, fmt.Errorf("authentication token missing in DSN path")
-	}
-
 	dbName := u.Query().Get("database")
 	if dbName == "" {
 		return nil, fmt.Errorf("database name missing in DSN (use ?database=name)")
@@ -69,16 +80,66 @@ func ParseDSN(dsn string) (*Config, error) {
 	if u.Query().Get("tls") == "true" {
 		useTLS = true
 	}
+	caFile := u.Query().Get("ca")
+	certFile := u.Query().Get("cert")
+	keyFile := u.Query().Get("key")
+	serverName := u.Query().Get("servername")
+	if !useTLS && (caFile != "" || certFile != "" || keyFile != "" || serverName != "") {
+		return nil, fmt.Errorf("ca, cert, key and servername in DSN need tls=true")
+	}
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("cert and key in DSN must be given together")
+	}
+
+	// a client certificate can authenticate on its own
+	token := strings.TrimPrefix(u.Path, "/")
+	if token == "" && certFile == "" {
+		return nil, fmt.Errorf("authentication token missing in DSN path")
+	}
+
+	batchRows, err := parseDSNInt(u.Query(), "batch_rows")
+	if err != nil {
+		return nil, err
+	}
+	batchBytes, err := parseDSNInt(u.Query(), "batch_bytes")
+	if err != nil {
+		return nil, err
+	}
+	fetchSize, err := parseDSNInt(u.Query(), "fetch_size")
+	if err != nil {
+		return nil, err
+	}
 
 	return &Config{
-		Addr:     addr,
-		DBName:   dbName,
-		Token:    token,
-		UseTLS:   useTLS,
-		RawQuery: u.RawQuery,
+		Addr:          addr,
+		DBName:        dbName,
+		Token:         token,
+		UseTLS:        useTLS,
+		TLSCAFile:     caFile,
+		TLSCertFile:   certFile,
+		TLSKeyFile:    keyFile,
+		TLSServerName: serverName,
+		RawQuery:      u.RawQuery,
+		BatchRows:     int(batchRows),
+		BatchBytes:    batchBytes,
+		Cursor:        u.Query().Get("cursor") == "true",
+		FetchSize:     int(fetchSize),
 	}, nil
 }
 
+// parseDSNInt reads an optional, non negative integer DSN parameter.
+func parseDSNInt(query url.Values, name string) (int64, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s in DSN: %q", name, raw)
+	}
+	return n, nil
+}
+
 // OpenConnector parses DSN and returns a connector.
 func (d *SQLDriver) OpenConnector(dsn string) (driver.Connector, error) {
 	cfg, err := ParseDSN(dsn)
@@ -30,6 +30,48 @@ func TestParseDSN(t *testing.T) {
 			want:    &drivers.Config{DBName: "database1", Addr: "0.0.0.0:8080", Token: "token123", UseTLS: true},
 			wantErr: false,
 		},
+		{
+			name:    "with_batching",
+			dsn:     "netsqlite://0.0.0.0:8080/token123?database=database1&batch_rows=500&batch_bytes=65536",
+			want:    &drivers.Config{DBName: "database1", Addr: "0.0.0.0:8080", Token: "token123", BatchRows: 500, BatchBytes: 65536},
+			wantErr: false,
+		},
+		{
+			name:    "with_cursor",
+			dsn:     "netsqlite://0.0.0.0:8080/token123?database=database1&cursor=true&fetch_size=100",
+			want:    &drivers.Config{DBName: "database1", Addr: "0.0.0.0:8080", Token: "token123", Cursor: true, FetchSize: 100},
+			wantErr: false,
+		},
+		{
+			name:    "with_mtls",
+			dsn:     "netsqlite://0.0.0.0:8080/?database=database1&tls=true&ca=ca.pem&cert=client.pem&key=client-key.pem&servername=db.internal",
+			want:    &drivers.Config{DBName: "database1", Addr: "0.0.0.0:8080", UseTLS: true, TLSCAFile: "ca.pem", TLSCertFile: "client.pem", TLSKeyFile: "client-key.pem", TLSServerName: "db.internal"},
+			wantErr: false,
+		},
+		{
+			name:    "cert_without_key",
+			dsn:     "netsqlite://0.0.0.0:8080/token123?database=database1&tls=true&cert=client.pem",
+			want:    &drivers.Config{},
+			wantErr: true,
+		},
+		{
+			name:    "ca_without_tls",
+			dsn:     "netsqlite://0.0.0.0:8080/token123?database=database1&ca=ca.pem",
+			want:    &drivers.Config{},
+			wantErr: true,
+		},
+		{
+			name:    "no_token_no_cert",
+			dsn:     "netsqlite://0.0.0.0:8080/?database=database1&tls=true",
+			want:    &drivers.Config{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_batching",
+			dsn:     "netsqlite://0.0.0.0:8080/token123?database=database1&batch_rows=-1",
+			want:    &drivers.Config{},
+			wantErr: true,
+		},
 		{
 			name:    "should_not_parse",
 			dsn:     "netsqlite://test:localhost:8080/123",
@@ -54,6 +96,19 @@ func TestParseDSN(t *testing.T) {
 			if tt.want.Addr != got.Addr || tt.want.DBName != got.DBName {
 				t.Fatal("It's not what it was supposed to be")
 			}
+			if tt.want.BatchRows != got.BatchRows || tt.want.BatchBytes != got.BatchBytes {
+				t.Fatalf("batching mismatch: want %d/%d got %d/%d", tt.want.BatchRows, tt.want.BatchBytes, got.BatchRows, got.BatchBytes)
+			}
+			if tt.want.Cursor != got.Cursor || tt.want.FetchSize != got.FetchSize {
+				t.Fatalf("cursor mismatch: want %t/%d got %t/%d", tt.want.Cursor, tt.want.FetchSize, got.Cursor, got.FetchSize)
+			}
+			if tt.want.Token != got.Token || tt.want.UseTLS != got.UseTLS {
+				t.Fatalf("token/tls mismatch: want %q/%t got %q/%t", tt.want.Token, tt.want.UseTLS, got.Token, got.UseTLS)
+			}
+			if tt.want.TLSCAFile != got.TLSCAFile || tt.want.TLSCertFile != got.TLSCertFile ||
+				tt.want.TLSKeyFile != got.TLSKeyFile || tt.want.TLSServerName != got.TLSServerName {
+				t.Fatalf("tls files mismatch: want %+v got %+v", tt.want, got)
+			}
 		})
 	}
 }
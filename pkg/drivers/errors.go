@@ -0,0 +1,126 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// sqliteErrorDomain must match the ErrorInfo domain the server uses for SQLite errors.
+const sqliteErrorDomain = "sqlite.netsqlite"
+
+// ErrNo is a primary SQLite result code, see https://www.sqlite.org/rescode.html
+type ErrNo int
+
+// ErrNoExtended is an extended SQLite result code.
+type ErrNoExtended int
+
+// Primary result codes, named like their go-sqlite3 counterparts.
+const (
+	ErrError      ErrNo = 1  // SQL error or missing database
+	ErrInternal   ErrNo = 2  // Internal logic error in SQLite
+	ErrPerm       ErrNo = 3  // Access permission denied
+	ErrAbort      ErrNo = 4  // Callback routine requested an abort
+	ErrBusy       ErrNo = 5  // The database file is locked
+	ErrLocked     ErrNo = 6  // A table in the database is locked
+	ErrNomem      ErrNo = 7  // A malloc() failed
+	ErrReadonly   ErrNo = 8  // Attempt to write a readonly database
+	ErrInterrupt  ErrNo = 9  // Operation terminated by sqlite3_interrupt()
+	ErrIoErr      ErrNo = 10 // Some kind of disk I/O error occurred
+	ErrCorrupt    ErrNo = 11 // The database disk image is malformed
+	ErrNotFound   ErrNo = 12 // Unknown opcode in sqlite3_file_control()
+	ErrFull       ErrNo = 13 // Insertion failed because database is full
+	ErrCantOpen   ErrNo = 14 // Unable to open the database file
+	ErrProtocol   ErrNo = 15 // Database lock protocol error
+	ErrEmpty      ErrNo = 16 // Database is empty
+	ErrSchema     ErrNo = 17 // The database schema changed
+	ErrTooBig     ErrNo = 18 // String or BLOB exceeds size limit
+	ErrConstraint ErrNo = 19 // Abort due to constraint violation
+	ErrMismatch   ErrNo = 20 // Data type mismatch
+	ErrMisuse     ErrNo = 21 // Library used incorrectly
+	ErrNoLFS      ErrNo = 22 // Uses OS features not supported on host
+	ErrAuth       ErrNo = 23 // Authorization denied
+	ErrFormat     ErrNo = 24 // Auxiliary database format error
+	ErrRange      ErrNo = 25 // 2nd parameter to sqlite3_bind out of range
+	ErrNotADB     ErrNo = 26 // File opened that is not a database file
+)
+
+// Extended result codes of constraint violations.
+const (
+	ErrConstraintCheck      = ErrNoExtended(ErrConstraint) | 1<<8
+	ErrConstraintForeignKey = ErrNoExtended(ErrConstraint) | 3<<8
+	ErrConstraintNotNull    = ErrNoExtended(ErrConstraint) | 5<<8
+	ErrConstraintPrimaryKey = ErrNoExtended(ErrConstraint) | 6<<8
+	ErrConstraintTrigger    = ErrNoExtended(ErrConstraint) | 7<<8
+	ErrConstraintUnique     = ErrNoExtended(ErrConstraint) | 8<<8
+	ErrConstraintRowID      = ErrNoExtended(ErrConstraint) | 10<<8
+)
+
+// Error is a SQLite error reported by the server. Use errors.As to get at it:
+//
+//	var sqliteErr *drivers.Error
+//	if errors.As(err, &sqliteErr) && sqliteErr.Code == drivers.ErrConstraint {
+//		// handle the constraint violation
+//	}
+type Error struct {
+	Code         ErrNo
+	ExtendedCode ErrNoExtended
+	Reason       string // Name of the primary code, e.g. "SQLITE_CONSTRAINT"
+
+	op     string
+	status *status.Status
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("netsqlite: %s failed: %s", e.op, e.status.Message())
+}
+
+// GRPCStatus returns the status the server replied with, so status.Code
+// keeps working on the error.
+func (e *Error) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// StatusCode is the gRPC code the server mapped the SQLite error to.
+func (e *Error) StatusCode() codes.Code {
+	return e.status.Code()
+}
+
+// sendError digs out why a send on a client stream of the op RPC failed,
+// io.EOF means the server already ended the stream and has the actual error.
+func sendError[Req, Resp any](op string, stream grpc.ClientStreamingClient[Req, Resp], err error) error {
+	if errors.Is(err, io.EOF) {
+		if _, err = stream.CloseAndRecv(); err == nil {
+			err = errors.New("server ended the stream early")
+		}
+	}
+	return rpcError(op, err)
+}
+
+// rpcError wraps an error returned by the op RPC, SQLite errors become an *Error.
+func rpcError(op string, err error) error {
+	if st, ok := status.FromError(err); ok {
+		for _, detail := range st.Details() {
+			info, ok := detail.(*errdetails.ErrorInfo)
+			if !ok || info.Domain != sqliteErrorDomain {
+				continue
+			}
+			code, _ := strconv.Atoi(info.Metadata["code"])
+			extended, _ := strconv.Atoi(info.Metadata["extended_code"])
+			return &Error{
+				Code:         ErrNo(code),
+				ExtendedCode: ErrNoExtended(extended),
+				Reason:       info.Reason,
+				op:           op,
+				status:       st,
+			}
+		}
+	}
+	return fmt.Errorf("netsqlite: gRPC %s failed: %w", op, err)
+}
@@ -1,10 +1,13 @@
 package drivers
 
 import (
+	"context"
+	"database/sql"
 	"database/sql/driver"
 	"errors"
 	"fmt"
 	"io"
+	"reflect"
 
 	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
 	"google.golang.org/grpc/codes"
@@ -13,12 +16,92 @@ import (
 
 // interface check, just in case hehe
 var _ driver.Rows = &SQLRows{}
+var _ driver.RowsColumnTypeDatabaseTypeName = &SQLRows{}
+var _ driver.RowsColumnTypeNullable = &SQLRows{}
+var _ driver.RowsColumnTypeScanType = &SQLRows{}
 
-// SQLRows iterates over gRPC query stream results.
+// SQLRows iterates over query results, either streamed by the server or
+// pulled from a server side cursor.
 type SQLRows struct {
-	stream  pb.NetsqliteService_QueryClient
+	src     rowSource
 	columns []string
+	types   []*pb.ColumnType // may be empty if the server doesn't send them
 	closed  bool
+
+	// batch holds rows received but not yet handed out by Next
+	batch []*pb.Row
+}
+
+// rowSource hands out batches of rows, io.EOF once there are no more.
+type rowSource interface {
+	recv() ([]*pb.Row, error)
+	close() error
+}
+
+// newRows reads the leading Columns message of a query stream. cancel is
+// called once the rows are done with the stream.
+func newRows(stream pb.NetsqliteService_QueryClient, cancel context.CancelFunc) (*SQLRows, error) {
+	// Receive first message (must be columns or EOF)
+	firstResp, err := stream.Recv()
+	if err != nil {
+		cancel()
+		if err == io.EOF { // No rows returned
+			return &SQLRows{closed: true, columns: []string{}}, nil
+		}
+		return nil, rpcError("Query", err)
+	}
+
+	colsResult := firstResp.GetColumns()
+	if colsResult == nil {
+		cancel()
+		return nil, errors.New("netsqlite: protocol error - expected Columns first")
+	}
+
+	return &SQLRows{
+		src:     &streamSource{stream: stream, cancel: cancel},
+		columns: colsResult.Names,
+		types:   colsResult.Types,
+		closed:  false,
+	}, nil
+}
+
+// streamSource reads row batches off a Query stream.
+type streamSource struct {
+	stream pb.NetsqliteService_QueryClient
+	cancel context.CancelFunc
+}
+
+func (s *streamSource) recv() ([]*pb.Row, error) {
+	resp, err := s.stream.Recv()
+	if err != nil {
+		s.cancel()
+		if err == io.EOF {
+			fmt.Println("Driver: SQLRows received EOF.")
+			return nil, io.EOF
+		}
+		if status.Code(err) == codes.Canceled {
+			fmt.Println("Driver: SQLRows stream context canceled.")
+			return nil, driver.ErrBadConn
+		}
+		fmt.Printf("Driver: SQLRows stream Recv error: %v\n", err)
+		return nil, rpcError("Query", err)
+	}
+
+	switch result := resp.Result.(type) {
+	case *pb.QueryResponse_Batch:
+		return result.Batch.Rows, nil
+	case *pb.QueryResponse_Row:
+		return []*pb.Row{result.Row}, nil
+	default:
+		s.cancel()
+		return nil, errors.New("netsqlite: protocol error - expected Row data")
+	}
+}
+
+// close tells the server to stop streaming, and frees up the transaction if we're in one.
+func (s *streamSource) close() error {
+	s.cancel()
+	return nil
 }
 
 // Columns returns column names.
@@ -26,43 +109,81 @@ func (r *SQLRows) Columns() []string {
 	return r.columns
 }
 
-// Close marks the iterator as closed.
+func (r *SQLRows) columnType(index int) *pb.ColumnType {
+	if index < 0 || index >= len(r.types) {
+		return nil
+	}
+	return r.types[index]
+}
+
+// ColumnTypeDatabaseTypeName returns the declared type of the column, e.g. "INTEGER".
+func (r *SQLRows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.columnType(index).GetDatabaseTypeName()
+}
+
+// ColumnTypeNullable reports whether the column may be NULL.
+func (r *SQLRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	ct := r.columnType(index)
+	return ct.GetNullable(), ct.GetNullableKnown()
+}
+
+// ColumnTypeScanType returns the Go type suited to scan the column into,
+// the same one go-sqlite3 would suggest.
+func (r *SQLRows) ColumnTypeScanType(index int) reflect.Type {
+	switch r.columnType(index).GetScanType() {
+	case pb.ScanType_SCAN_TYPE_INT64:
+		return reflect.TypeFor[sql.NullInt64]()
+	case pb.ScanType_SCAN_TYPE_FLOAT64:
+		return reflect.TypeFor[sql.NullFloat64]()
+	case pb.ScanType_SCAN_TYPE_STRING:
+		return reflect.TypeFor[sql.NullString]()
+	case pb.ScanType_SCAN_TYPE_BYTES:
+		return reflect.TypeFor[sql.RawBytes]()
+	case pb.ScanType_SCAN_TYPE_BOOL:
+		return reflect.TypeFor[sql.NullBool]()
+	case pb.ScanType_SCAN_TYPE_TIME:
+		return reflect.TypeFor[sql.NullTime]()
+	default:
+		return reflect.TypeFor[any]()
+	}
+}
+
+// Close marks the iterator as closed and lets the server know.
 func (r *SQLRows) Close() error {
 	if r.closed {
 		return nil
 	}
 	r.closed = true
-	r.stream = nil // Allow GC
+	r.batch = nil
 	fmt.Println("Driver: SQLRows closed.")
+	if r.src != nil {
+		err := r.src.close()
+		r.src = nil // Allow GC
+		return err
+	}
 	return nil
 }
 
-// Next fetches the next row from the gRPC stream.
+// Next returns the next row, receiving a new batch from the source
+// once the current one is drained.
 func (r *SQLRows) Next(dest []driver.Value) error {
-	if r.closed || r.stream == nil {
-		return io.EOF
-	}
-
-	resp, err := r.stream.Recv()
-	if err != nil {
-		r.closed = true
-		if err == io.EOF {
-			fmt.Println("Driver: SQLRows received EOF.")
+	for len(r.batch) == 0 {
+		if r.closed || r.src == nil {
 			return io.EOF
 		}
-		if status.Code(err) == codes.Canceled {
-			fmt.Println("Driver: SQLRows stream context canceled.")
-			return driver.ErrBadConn
+		batch, err := r.src.recv()
+		if err != nil {
+			r.closed = true
+			r.src.close()
+			r.src = nil
+			return err
 		}
-		fmt.Printf("Driver: SQLRows stream Recv error: %v\n", err)
-		return fmt.Errorf("netsqlite: receiving row data failed: %w", err)
+		r.batch = batch
 	}
 
-	rowData := resp.GetRow()
-	if rowData == nil {
-		r.closed = true
-		return errors.New("netsqlite: protocol error - expected Row data")
-	}
+	rowData := r.batch[0]
+	r.batch[0] = nil // Allow GC
+	r.batch = r.batch[1:]
 
 	if len(rowData.Values) != len(r.columns) {
 		r.closed = true
@@ -70,8 +191,13 @@ func (r *SQLRows) Next(dest []driver.Value) error {
 	}
 
 	// Convert proto values to driver values
+	var err error
 	for i, pv := range rowData.Values {
-		dest[i] = pv.AsInterface()
+		dest[i], err = protoValueToDriverValue(pv)
+		if err != nil {
+			r.closed = true
+			return err
+		}
 	}
 
 	return nil
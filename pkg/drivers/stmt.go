@@ -0,0 +1,181 @@
+package drivers
+
+import (
+	"context"
+	"database/sql/driver"
+
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SQLStmt is a statement prepared on the server, identified by its statement ID.
+type SQLStmt struct {
+	conn     *SQLConn
+	query    string
+	id       string
+	numInput int
+	columns  []string
+	closed   bool
+}
+
+var _ driver.Stmt = &SQLStmt{}
+var _ driver.StmtExecContext = &SQLStmt{}
+var _ driver.StmtQueryContext = &SQLStmt{}
+
+// PrepareContext prepares the statement on the server.
+func (c *SQLConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if c.closed || c.client == nil {
+		return nil, driver.ErrBadConn
+	}
+
+	stmt := &SQLStmt{conn: c, query: query}
+	if err := stmt.prepare(ctx); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+func (s *SQLStmt) prepare(ctx context.Context) error {
+	resp, err := s.conn.client.Prepare(ctx, &pb.PrepareRequest{
+		DatabaseName:  s.conn.dbName,
+		SessionId:     s.conn.sessionID,
+		Sql:           s.query,
+		TransactionId: s.conn.txID,
+	})
+	if err != nil {
+		return rpcError("Prepare", err)
+	}
+
+	s.id = resp.StatementId
+	s.numInput = int(resp.NumInput)
+	s.columns = resp.Columns
+	return nil
+}
+
+// retry runs fn, re-preparing the statement once if the server no longer
+// knows about it (e.g. it expired after being idle for too long).
+func (s *SQLStmt) retry(ctx context.Context, fn func() error) error {
+	err := fn()
+	if status.Code(err) != codes.NotFound || s.conn.txID != "" {
+		return err
+	}
+	if err := s.prepare(ctx); err != nil {
+		return err
+	}
+	return fn()
+}
+
+// NumInput returns the number of placeholder parameters.
+func (s *SQLStmt) NumInput() int {
+	return s.numInput
+}
+
+// ExecContext executes the prepared statement via gRPC ExecPrepared RPC.
+func (s *SQLStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if s.closed || s.conn.closed || s.conn.client == nil {
+		return nil, driver.ErrBadConn
+	}
+
+	protoArgs, err := driverNamedValueToProtoValue(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *pb.ExecResponse
+	err = s.retry(ctx, func() error {
+		var err error
+		resp, err = s.conn.client.ExecPrepared(ctx, &pb.ExecPreparedRequest{
+			DatabaseName:  s.conn.dbName,
+			SessionId:     s.conn.sessionID,
+			StatementId:   s.id,
+			Args:          protoArgs,
+			TransactionId: s.conn.txID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, rpcError("ExecPrepared", err)
+	}
+
+	return &SQLResult{
+		rowsAffected: resp.RowsAffected,
+		lastInsertId: resp.LastInsertId,
+	}, nil
+}
+
+// QueryContext executes the prepared query via gRPC QueryPrepared RPC stream.
+func (s *SQLStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if s.closed || s.conn.closed || s.conn.client == nil {
+		return nil, driver.ErrBadConn
+	}
+
+	protoArgs, err := driverNamedValueToProtoValue(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows *SQLRows
+	err = s.retry(ctx, func() error {
+		streamCtx, cancel := context.WithCancel(ctx)
+		stream, err := s.conn.client.QueryPrepared(streamCtx, &pb.QueryPreparedRequest{
+			DatabaseName:  s.conn.dbName,
+			SessionId:     s.conn.sessionID,
+			StatementId:   s.id,
+			Args:          protoArgs,
+			TransactionId: s.conn.txID,
+			Batch:         s.conn.batch,
+		})
+		if err != nil {
+			cancel()
+			return err
+		}
+		// Errors from a server stream only show up once we start receiving
+		rows, err = newRows(stream, cancel)
+		return err
+	})
+	if err != nil {
+		return nil, rpcError("QueryPrepared", err)
+	}
+	return rows, nil
+}
+
+// Exec implements the deprecated driver.Stmt method.
+func (s *SQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), valuesToNamedValues(args))
+}
+
+// Query implements the deprecated driver.Stmt method.
+func (s *SQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), valuesToNamedValues(args))
+}
+
+// Close releases the statement on the server.
+func (s *SQLStmt) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.conn.closed || s.conn.client == nil {
+		return nil
+	}
+
+	_, err := s.conn.client.CloseStmt(context.Background(), &pb.CloseStmtRequest{
+		DatabaseName: s.conn.dbName,
+		SessionId:    s.conn.sessionID,
+		StatementId:  s.id,
+	})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return rpcError("CloseStmt", err)
+	}
+	return nil
+}
+
+func valuesToNamedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return named
+}
@@ -0,0 +1,110 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+)
+
+// SQLTx is a transaction living on the server, identified by the ID returned by BeginTx.
+type SQLTx struct {
+	conn *SQLConn
+	id   string
+}
+
+var _ driver.Tx = &SQLTx{}
+
+// txModeFromIsolation maps database/sql isolation levels to SQLite BEGIN modes.
+// SQLite transactions are always serializable, the level only decides when
+// the write lock is taken.
+func txModeFromIsolation(level driver.IsolationLevel) (pb.TxMode, error) {
+	switch sql.IsolationLevel(level) {
+	case sql.LevelDefault, sql.LevelReadUncommitted, sql.LevelReadCommitted:
+		return pb.TxMode_TX_MODE_DEFERRED, nil
+	case sql.LevelWriteCommitted, sql.LevelRepeatableRead, sql.LevelSnapshot:
+		return pb.TxMode_TX_MODE_IMMEDIATE, nil
+	case sql.LevelSerializable, sql.LevelLinearizable:
+		return pb.TxMode_TX_MODE_EXCLUSIVE, nil
+	default:
+		return 0, fmt.Errorf("netsqlite: unsupported isolation level %d", level)
+	}
+}
+
+// BeginTx starts a transaction on the server, every statement on this
+// connection runs inside it until Commit or Rollback. Unless opts.ReadOnly,
+// it holds the writer of the database until then, so other writes wait for
+// it, and the server rolls it back if left idle for write_tx_idle_timeout.
+func (c *SQLConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if c.closed || c.client == nil {
+		return nil, driver.ErrBadConn
+	}
+	if c.txID != "" {
+		return nil, errors.New("netsqlite: connection already has an open transaction")
+	}
+
+	mode, err := txModeFromIsolation(opts.Isolation)
+	if err != nil {
+		return nil, err
+	}
+	// A read-only transaction never needs the write lock upfront
+	if opts.ReadOnly {
+		mode = pb.TxMode_TX_MODE_DEFERRED
+	}
+
+	resp, err := c.client.BeginTx(ctx, &pb.BeginTxRequest{
+		DatabaseName: c.dbName,
+		ReadOnly:     opts.ReadOnly,
+		Mode:         mode,
+	})
+	if err != nil {
+		return nil, rpcError("BeginTx", err)
+	}
+
+	c.txID = resp.TransactionId
+	return &SQLTx{conn: c, id: resp.TransactionId}, nil
+}
+
+// Commit commits the server side transaction.
+func (t *SQLTx) Commit() error {
+	if t.conn.closed || t.conn.client == nil {
+		return driver.ErrBadConn
+	}
+	// The transaction is over whatever the outcome, the server reaps it if need be
+	defer t.done()
+
+	_, err := t.conn.client.Commit(context.Background(), &pb.CommitRequest{
+		DatabaseName:  t.conn.dbName,
+		TransactionId: t.id,
+	})
+	if err != nil {
+		return rpcError("Commit", err)
+	}
+	return nil
+}
+
+// Rollback aborts the server side transaction.
+func (t *SQLTx) Rollback() error {
+	if t.conn.closed || t.conn.client == nil {
+		return driver.ErrBadConn
+	}
+	defer t.done()
+
+	_, err := t.conn.client.Rollback(context.Background(), &pb.RollbackRequest{
+		DatabaseName:  t.conn.dbName,
+		TransactionId: t.id,
+	})
+	if err != nil {
+		return rpcError("Rollback", err)
+	}
+	return nil
+}
+
+func (t *SQLTx) done() {
+	if t.conn.txID == t.id {
+		t.conn.txID = ""
+	}
+}
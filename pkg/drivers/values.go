@@ -0,0 +1,66 @@
+package drivers
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	pb "github.com/alfredosa/netsqlite/proto/netsqlite/v1"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Helper to convert driver args to proto args.
+// database/sql has already converted args to one of the driver.Value types.
+func driverNamedValueToProtoValue(args []driver.NamedValue) ([]*pb.SqlValue, error) {
+	protoArgs := make([]*pb.SqlValue, len(args))
+	for i, arg := range args {
+		switch v := arg.Value.(type) {
+		case nil:
+			protoArgs[i] = &pb.SqlValue{Value: &pb.SqlValue_NullValue{NullValue: structpb.NullValue_NULL_VALUE}}
+		case int64:
+			protoArgs[i] = &pb.SqlValue{Value: &pb.SqlValue_Int64Value{Int64Value: v}}
+		case float64:
+			protoArgs[i] = &pb.SqlValue{Value: &pb.SqlValue_DoubleValue{DoubleValue: v}}
+		case string:
+			protoArgs[i] = &pb.SqlValue{Value: &pb.SqlValue_TextValue{TextValue: v}}
+		case []byte:
+			protoArgs[i] = &pb.SqlValue{Value: &pb.SqlValue_BlobValue{BlobValue: v}}
+		case bool:
+			protoArgs[i] = &pb.SqlValue{Value: &pb.SqlValue_BoolValue{BoolValue: v}}
+		case time.Time:
+			protoArgs[i] = &pb.SqlValue{Value: &pb.SqlValue_TimestampValue{TimestampValue: timestamppb.New(v)}}
+		default:
+			return nil, fmt.Errorf("netsqlite: unsupported arg type %T at index %d", arg.Value, i)
+		}
+	}
+	return protoArgs, nil
+}
+
+// protoValueToDriverValue converts a column value back to the Go type
+// go-sqlite3 would have returned.
+func protoValueToDriverValue(val *pb.SqlValue) (driver.Value, error) {
+	switch v := val.GetValue().(type) {
+	case nil, *pb.SqlValue_NullValue:
+		return nil, nil
+	case *pb.SqlValue_Int64Value:
+		return v.Int64Value, nil
+	case *pb.SqlValue_DoubleValue:
+		return v.DoubleValue, nil
+	case *pb.SqlValue_TextValue:
+		return v.TextValue, nil
+	case *pb.SqlValue_BlobValue:
+		// Never hand out nil for an empty (but not NULL) blob
+		if v.BlobValue == nil {
+			return []byte{}, nil
+		}
+		return v.BlobValue, nil
+	case *pb.SqlValue_BoolValue:
+		return v.BoolValue, nil
+	case *pb.SqlValue_TimestampValue:
+		return v.TimestampValue.AsTime(), nil
+	default:
+		return nil, fmt.Errorf("netsqlite: unsupported column value type %T", v)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -24,6 +25,119 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// ScanType is the Go type suited to scan a column into.
+type ScanType int32
+
+const (
+	ScanType_SCAN_TYPE_ANY     ScanType = 0 // any
+	ScanType_SCAN_TYPE_INT64   ScanType = 1 // sql.NullInt64
+	ScanType_SCAN_TYPE_FLOAT64 ScanType = 2 // sql.NullFloat64
+	ScanType_SCAN_TYPE_STRING  ScanType = 3 // sql.NullString
+	ScanType_SCAN_TYPE_BYTES   ScanType = 4 // sql.RawBytes
+	ScanType_SCAN_TYPE_BOOL    ScanType = 5 // sql.NullBool
+	ScanType_SCAN_TYPE_TIME    ScanType = 6 // sql.NullTime
+)
+
+// Enum value maps for ScanType.
+var (
+	ScanType_name = map[int32]string{
+		0: "SCAN_TYPE_ANY",
+		1: "SCAN_TYPE_INT64",
+		2: "SCAN_TYPE_FLOAT64",
+		3: "SCAN_TYPE_STRING",
+		4: "SCAN_TYPE_BYTES",
+		5: "SCAN_TYPE_BOOL",
+		6: "SCAN_TYPE_TIME",
+	}
+	ScanType_value = map[string]int32{
+		"SCAN_TYPE_ANY":     0,
+		"SCAN_TYPE_INT64":   1,
+		"SCAN_TYPE_FLOAT64": 2,
+		"SCAN_TYPE_STRING":  3,
+		"SCAN_TYPE_BYTES":   4,
+		"SCAN_TYPE_BOOL":    5,
+		"SCAN_TYPE_TIME":    6,
+	}
+)
+
+func (x ScanType) Enum() *ScanType {
+	p := new(ScanType)
+	*p = x
+	return p
+}
+
+func (x ScanType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ScanType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_netsqlite_v1_netsqlite_proto_enumTypes[0].Descriptor()
+}
+
+func (ScanType) Type() protoreflect.EnumType {
+	return &file_proto_netsqlite_v1_netsqlite_proto_enumTypes[0]
+}
+
+func (x ScanType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ScanType.Descriptor instead.
+func (ScanType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{0}
+}
+
+// TxMode maps to the SQLite BEGIN variants.
+// See https://www.sqlite.org/lang_transaction.html
+type TxMode int32
+
+const (
+	TxMode_TX_MODE_DEFERRED  TxMode = 0 // BEGIN DEFERRED, locks are taken on first read/write
+	TxMode_TX_MODE_IMMEDIATE TxMode = 1 // BEGIN IMMEDIATE, takes the write lock right away
+	TxMode_TX_MODE_EXCLUSIVE TxMode = 2 // BEGIN EXCLUSIVE
+)
+
+// Enum value maps for TxMode.
+var (
+	TxMode_name = map[int32]string{
+		0: "TX_MODE_DEFERRED",
+		1: "TX_MODE_IMMEDIATE",
+		2: "TX_MODE_EXCLUSIVE",
+	}
+	TxMode_value = map[string]int32{
+		"TX_MODE_DEFERRED":  0,
+		"TX_MODE_IMMEDIATE": 1,
+		"TX_MODE_EXCLUSIVE": 2,
+	}
+)
+
+func (x TxMode) Enum() *TxMode {
+	p := new(TxMode)
+	*p = x
+	return p
+}
+
+func (x TxMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TxMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_netsqlite_v1_netsqlite_proto_enumTypes[1].Descriptor()
+}
+
+func (TxMode) Type() protoreflect.EnumType {
+	return &file_proto_netsqlite_v1_netsqlite_proto_enumTypes[1]
+}
+
+func (x TxMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TxMode.Descriptor instead.
+func (TxMode) EnumDescriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{1}
+}
+
 type PingRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"` // MUST specify the target database for the ping
@@ -114,9 +228,10 @@ func (x *PingResponse) GetMessage() string {
 
 type ExecRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"` // Identify the target database
-	Sql           string                 `protobuf:"bytes,2,opt,name=sql,proto3" json:"sql,omitempty"`                                       // The SQL statement
-	Args          []*structpb.Value      `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty"`                                     // Arguments
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`    // Identify the target database
+	Sql           string                 `protobuf:"bytes,2,opt,name=sql,proto3" json:"sql,omitempty"`                                          // The SQL statement
+	TransactionId string                 `protobuf:"bytes,4,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // Optional, runs the statement inside this transaction
+	Args          []*SqlValue            `protobuf:"bytes,5,rep,name=args,proto3" json:"args,omitempty"`                                        // Arguments
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -165,7 +280,14 @@ func (x *ExecRequest) GetSql() string {
 	return ""
 }
 
-func (x *ExecRequest) GetArgs() []*structpb.Value {
+func (x *ExecRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *ExecRequest) GetArgs() []*SqlValue {
 	if x != nil {
 		return x.Args
 	}
@@ -226,9 +348,11 @@ func (x *ExecResponse) GetLastInsertId() int64 {
 
 type QueryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"` // Identify the target database
-	Sql           string                 `protobuf:"bytes,2,opt,name=sql,proto3" json:"sql,omitempty"`                                       // The SQL query
-	Args          []*structpb.Value      `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty"`                                     // Arguments
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`    // Identify the target database
+	Sql           string                 `protobuf:"bytes,2,opt,name=sql,proto3" json:"sql,omitempty"`                                          // The SQL query
+	TransactionId string                 `protobuf:"bytes,4,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // Optional, runs the query inside this transaction
+	Args          []*SqlValue            `protobuf:"bytes,5,rep,name=args,proto3" json:"args,omitempty"`                                        // Arguments
+	Batch         *BatchOptions          `protobuf:"bytes,6,opt,name=batch,proto3" json:"batch,omitempty"`                                      // Optional, server defaults apply otherwise
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -277,19 +401,88 @@ func (x *QueryRequest) GetSql() string {
 	return ""
 }
 
-func (x *QueryRequest) GetArgs() []*structpb.Value {
+func (x *QueryRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetArgs() []*SqlValue {
 	if x != nil {
 		return x.Args
 	}
 	return nil
 }
 
+func (x *QueryRequest) GetBatch() *BatchOptions {
+	if x != nil {
+		return x.Batch
+	}
+	return nil
+}
+
+// BatchOptions bounds how many rows the server packs in a single RowBatch.
+// A batch is sent as soon as either limit is reached, 0 means server default.
+type BatchOptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaxRows       int32                  `protobuf:"varint,1,opt,name=max_rows,json=maxRows,proto3" json:"max_rows,omitempty"`
+	MaxBytes      int64                  `protobuf:"varint,2,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchOptions) Reset() {
+	*x = BatchOptions{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchOptions) ProtoMessage() {}
+
+func (x *BatchOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchOptions.ProtoReflect.Descriptor instead.
+func (*BatchOptions) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BatchOptions) GetMaxRows() int32 {
+	if x != nil {
+		return x.MaxRows
+	}
+	return 0
+}
+
+func (x *BatchOptions) GetMaxBytes() int64 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
 type QueryResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Result:
 	//
 	//	*QueryResponse_Columns
 	//	*QueryResponse_Row
+	//	*QueryResponse_Batch
 	Result        isQueryResponse_Result `protobuf_oneof:"result"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -297,7 +490,7 @@ type QueryResponse struct {
 
 func (x *QueryResponse) Reset() {
 	*x = QueryResponse{}
-	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[5]
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -309,7 +502,7 @@ func (x *QueryResponse) String() string {
 func (*QueryResponse) ProtoMessage() {}
 
 func (x *QueryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[5]
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -322,7 +515,7 @@ func (x *QueryResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QueryResponse.ProtoReflect.Descriptor instead.
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{5}
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *QueryResponse) GetResult() isQueryResponse_Result {
@@ -350,6 +543,15 @@ func (x *QueryResponse) GetRow() *Row {
 	return nil
 }
 
+func (x *QueryResponse) GetBatch() *RowBatch {
+	if x != nil {
+		if x, ok := x.Result.(*QueryResponse_Batch); ok {
+			return x.Batch
+		}
+	}
+	return nil
+}
+
 type isQueryResponse_Result interface {
 	isQueryResponse_Result()
 }
@@ -359,23 +561,30 @@ type QueryResponse_Columns struct {
 }
 
 type QueryResponse_Row struct {
-	Row *Row `protobuf:"bytes,2,opt,name=row,proto3,oneof"`
+	Row *Row `protobuf:"bytes,2,opt,name=row,proto3,oneof"` // Single row, only sent by older servers
+}
+
+type QueryResponse_Batch struct {
+	Batch *RowBatch `protobuf:"bytes,3,opt,name=batch,proto3,oneof"`
 }
 
 func (*QueryResponse_Columns) isQueryResponse_Result() {}
 
 func (*QueryResponse_Row) isQueryResponse_Result() {}
 
+func (*QueryResponse_Batch) isQueryResponse_Result() {}
+
 type Columns struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Names         []string               `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	Types         []*ColumnType          `protobuf:"bytes,2,rep,name=types,proto3" json:"types,omitempty"` // One per name, same order
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Columns) Reset() {
 	*x = Columns{}
-	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[6]
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -387,7 +596,7 @@ func (x *Columns) String() string {
 func (*Columns) ProtoMessage() {}
 
 func (x *Columns) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[6]
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -400,7 +609,7 @@ func (x *Columns) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Columns.ProtoReflect.Descriptor instead.
 func (*Columns) Descriptor() ([]byte, []int) {
-	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{6}
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *Columns) GetNames() []string {
@@ -410,16 +619,92 @@ func (x *Columns) GetNames() []string {
 	return nil
 }
 
+func (x *Columns) GetTypes() []*ColumnType {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+// ColumnType describes a result column, as reported by go-sqlite3.
+type ColumnType struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseTypeName string                 `protobuf:"bytes,1,opt,name=database_type_name,json=databaseTypeName,proto3" json:"database_type_name,omitempty"` // Declared type, e.g. "INTEGER" or "VARCHAR(255)", empty for expressions
+	Nullable         bool                   `protobuf:"varint,2,opt,name=nullable,proto3" json:"nullable,omitempty"`
+	NullableKnown    bool                   `protobuf:"varint,3,opt,name=nullable_known,json=nullableKnown,proto3" json:"nullable_known,omitempty"` // False if nullability can't be told
+	ScanType         ScanType               `protobuf:"varint,4,opt,name=scan_type,json=scanType,proto3,enum=netsqlite.v1.ScanType" json:"scan_type,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ColumnType) Reset() {
+	*x = ColumnType{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ColumnType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ColumnType) ProtoMessage() {}
+
+func (x *ColumnType) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ColumnType.ProtoReflect.Descriptor instead.
+func (*ColumnType) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ColumnType) GetDatabaseTypeName() string {
+	if x != nil {
+		return x.DatabaseTypeName
+	}
+	return ""
+}
+
+func (x *ColumnType) GetNullable() bool {
+	if x != nil {
+		return x.Nullable
+	}
+	return false
+}
+
+func (x *ColumnType) GetNullableKnown() bool {
+	if x != nil {
+		return x.NullableKnown
+	}
+	return false
+}
+
+func (x *ColumnType) GetScanType() ScanType {
+	if x != nil {
+		return x.ScanType
+	}
+	return ScanType_SCAN_TYPE_ANY
+}
+
 type Row struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Values        []*structpb.Value      `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	Values        []*SqlValue            `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Row) Reset() {
 	*x = Row{}
-	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[7]
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -431,7 +716,7 @@ func (x *Row) String() string {
 func (*Row) ProtoMessage() {}
 
 func (x *Row) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[7]
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -444,113 +729,3247 @@ func (x *Row) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Row.ProtoReflect.Descriptor instead.
 func (*Row) Descriptor() ([]byte, []int) {
-	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{7}
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *Row) GetValues() []*structpb.Value {
+func (x *Row) GetValues() []*SqlValue {
 	if x != nil {
 		return x.Values
 	}
 	return nil
 }
 
-var File_proto_netsqlite_v1_netsqlite_proto protoreflect.FileDescriptor
+type RowBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          []*Row                 `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_netsqlite_v1_netsqlite_proto_rawDesc = "" +
-	"\n" +
-	"\"proto/netsqlite/v1/netsqlite.proto\x12\fnetsqlite.v1\x1a\x1cgoogle/protobuf/struct.proto\"2\n" +
-	"\vPingRequest\x12#\n" +
-	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\"(\n" +
-	"\fPingResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"p\n" +
-	"\vExecRequest\x12#\n" +
-	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12\x10\n" +
-	"\x03sql\x18\x02 \x01(\tR\x03sql\x12*\n" +
-	"\x04args\x18\x03 \x03(\v2\x16.google.protobuf.ValueR\x04args\"Y\n" +
-	"\fExecResponse\x12#\n" +
-	"\rrows_affected\x18\x01 \x01(\x03R\frowsAffected\x12$\n" +
-	"\x0elast_insert_id\x18\x02 \x01(\x03R\flastInsertId\"q\n" +
-	"\fQueryRequest\x12#\n" +
-	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12\x10\n" +
-	"\x03sql\x18\x02 \x01(\tR\x03sql\x12*\n" +
-	"\x04args\x18\x03 \x03(\v2\x16.google.protobuf.ValueR\x04args\"s\n" +
-	"\rQueryResponse\x121\n" +
-	"\acolumns\x18\x01 \x01(\v2\x15.netsqlite.v1.ColumnsH\x00R\acolumns\x12%\n" +
-	"\x03row\x18\x02 \x01(\v2\x11.netsqlite.v1.RowH\x00R\x03rowB\b\n" +
-	"\x06result\"\x1f\n" +
-	"\aColumns\x12\x14\n" +
-	"\x05names\x18\x01 \x03(\tR\x05names\"5\n" +
-	"\x03Row\x12.\n" +
-	"\x06values\x18\x01 \x03(\v2\x16.google.protobuf.ValueR\x06values2\xda\x01\n" +
-	"\x10NetsqliteService\x12?\n" +
-	"\x04Ping\x12\x19.netsqlite.v1.PingRequest\x1a\x1a.netsqlite.v1.PingResponse\"\x00\x12?\n" +
-	"\x04Exec\x12\x19.netsqlite.v1.ExecRequest\x1a\x1a.netsqlite.v1.ExecResponse\"\x00\x12D\n" +
-	"\x05Query\x12\x1a.netsqlite.v1.QueryRequest\x1a\x1b.netsqlite.v1.QueryResponse\"\x000\x01B!Z\x1f/proto/netsqlite/v1;netsqlitev1b\x06proto3"
+func (x *RowBatch) Reset() {
+	*x = RowBatch{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
-var (
-	file_proto_netsqlite_v1_netsqlite_proto_rawDescOnce sync.Once
-	file_proto_netsqlite_v1_netsqlite_proto_rawDescData []byte
-)
+func (x *RowBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
 
-func file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP() []byte {
-	file_proto_netsqlite_v1_netsqlite_proto_rawDescOnce.Do(func() {
-		file_proto_netsqlite_v1_netsqlite_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_netsqlite_v1_netsqlite_proto_rawDesc), len(file_proto_netsqlite_v1_netsqlite_proto_rawDesc)))
-	})
-	return file_proto_netsqlite_v1_netsqlite_proto_rawDescData
+func (*RowBatch) ProtoMessage() {}
+
+func (x *RowBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var file_proto_netsqlite_v1_netsqlite_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
-var file_proto_netsqlite_v1_netsqlite_proto_goTypes = []any{
-	(*PingRequest)(nil),    // 0: netsqlite.v1.PingRequest
-	(*PingResponse)(nil),   // 1: netsqlite.v1.PingResponse
-	(*ExecRequest)(nil),    // 2: netsqlite.v1.ExecRequest
-	(*ExecResponse)(nil),   // 3: netsqlite.v1.ExecResponse
-	(*QueryRequest)(nil),   // 4: netsqlite.v1.QueryRequest
-	(*QueryResponse)(nil),  // 5: netsqlite.v1.QueryResponse
-	(*Columns)(nil),        // 6: netsqlite.v1.Columns
-	(*Row)(nil),            // 7: netsqlite.v1.Row
-	(*structpb.Value)(nil), // 8: google.protobuf.Value
+// Deprecated: Use RowBatch.ProtoReflect.Descriptor instead.
+func (*RowBatch) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{10}
 }
-var file_proto_netsqlite_v1_netsqlite_proto_depIdxs = []int32{
-	8, // 0: netsqlite.v1.ExecRequest.args:type_name -> google.protobuf.Value
-	8, // 1: netsqlite.v1.QueryRequest.args:type_name -> google.protobuf.Value
-	6, // 2: netsqlite.v1.QueryResponse.columns:type_name -> netsqlite.v1.Columns
-	7, // 3: netsqlite.v1.QueryResponse.row:type_name -> netsqlite.v1.Row
-	8, // 4: netsqlite.v1.Row.values:type_name -> google.protobuf.Value
-	0, // 5: netsqlite.v1.NetsqliteService.Ping:input_type -> netsqlite.v1.PingRequest
-	2, // 6: netsqlite.v1.NetsqliteService.Exec:input_type -> netsqlite.v1.ExecRequest
-	4, // 7: netsqlite.v1.NetsqliteService.Query:input_type -> netsqlite.v1.QueryRequest
-	1, // 8: netsqlite.v1.NetsqliteService.Ping:output_type -> netsqlite.v1.PingResponse
-	3, // 9: netsqlite.v1.NetsqliteService.Exec:output_type -> netsqlite.v1.ExecResponse
-	5, // 10: netsqlite.v1.NetsqliteService.Query:output_type -> netsqlite.v1.QueryResponse
-	8, // [8:11] is the sub-list for method output_type
-	5, // [5:8] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+
+func (x *RowBatch) GetRows() []*Row {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
 }
 
-func init() { file_proto_netsqlite_v1_netsqlite_proto_init() }
-func file_proto_netsqlite_v1_netsqlite_proto_init() {
-	if File_proto_netsqlite_v1_netsqlite_proto != nil {
-		return
+// SqlValue is a single argument or column value, keeping the exact type
+// SQLite (and go-sqlite3) works with. An unset value is NULL.
+type SqlValue struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*SqlValue_NullValue
+	//	*SqlValue_Int64Value
+	//	*SqlValue_DoubleValue
+	//	*SqlValue_TextValue
+	//	*SqlValue_BlobValue
+	//	*SqlValue_BoolValue
+	//	*SqlValue_TimestampValue
+	Value         isSqlValue_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SqlValue) Reset() {
+	*x = SqlValue{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SqlValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SqlValue) ProtoMessage() {}
+
+func (x *SqlValue) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	file_proto_netsqlite_v1_netsqlite_proto_msgTypes[5].OneofWrappers = []any{
-		(*QueryResponse_Columns)(nil),
-		(*QueryResponse_Row)(nil),
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SqlValue.ProtoReflect.Descriptor instead.
+func (*SqlValue) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SqlValue) GetValue() isSqlValue_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *SqlValue) GetNullValue() structpb.NullValue {
+	if x != nil {
+		if x, ok := x.Value.(*SqlValue_NullValue); ok {
+			return x.NullValue
+		}
+	}
+	return structpb.NullValue(0)
+}
+
+func (x *SqlValue) GetInt64Value() int64 {
+	if x != nil {
+		if x, ok := x.Value.(*SqlValue_Int64Value); ok {
+			return x.Int64Value
+		}
+	}
+	return 0
+}
+
+func (x *SqlValue) GetDoubleValue() float64 {
+	if x != nil {
+		if x, ok := x.Value.(*SqlValue_DoubleValue); ok {
+			return x.DoubleValue
+		}
+	}
+	return 0
+}
+
+func (x *SqlValue) GetTextValue() string {
+	if x != nil {
+		if x, ok := x.Value.(*SqlValue_TextValue); ok {
+			return x.TextValue
+		}
+	}
+	return ""
+}
+
+func (x *SqlValue) GetBlobValue() []byte {
+	if x != nil {
+		if x, ok := x.Value.(*SqlValue_BlobValue); ok {
+			return x.BlobValue
+		}
+	}
+	return nil
+}
+
+func (x *SqlValue) GetBoolValue() bool {
+	if x != nil {
+		if x, ok := x.Value.(*SqlValue_BoolValue); ok {
+			return x.BoolValue
+		}
+	}
+	return false
+}
+
+func (x *SqlValue) GetTimestampValue() *timestamppb.Timestamp {
+	if x != nil {
+		if x, ok := x.Value.(*SqlValue_TimestampValue); ok {
+			return x.TimestampValue
+		}
+	}
+	return nil
+}
+
+type isSqlValue_Value interface {
+	isSqlValue_Value()
+}
+
+type SqlValue_NullValue struct {
+	NullValue structpb.NullValue `protobuf:"varint,1,opt,name=null_value,json=nullValue,proto3,enum=google.protobuf.NullValue,oneof"`
+}
+
+type SqlValue_Int64Value struct {
+	Int64Value int64 `protobuf:"varint,2,opt,name=int64_value,json=int64Value,proto3,oneof"`
+}
+
+type SqlValue_DoubleValue struct {
+	DoubleValue float64 `protobuf:"fixed64,3,opt,name=double_value,json=doubleValue,proto3,oneof"`
+}
+
+type SqlValue_TextValue struct {
+	TextValue string `protobuf:"bytes,4,opt,name=text_value,json=textValue,proto3,oneof"`
+}
+
+type SqlValue_BlobValue struct {
+	BlobValue []byte `protobuf:"bytes,5,opt,name=blob_value,json=blobValue,proto3,oneof"`
+}
+
+type SqlValue_BoolValue struct {
+	BoolValue bool `protobuf:"varint,6,opt,name=bool_value,json=boolValue,proto3,oneof"`
+}
+
+type SqlValue_TimestampValue struct {
+	TimestampValue *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp_value,json=timestampValue,proto3,oneof"`
+}
+
+func (*SqlValue_NullValue) isSqlValue_Value() {}
+
+func (*SqlValue_Int64Value) isSqlValue_Value() {}
+
+func (*SqlValue_DoubleValue) isSqlValue_Value() {}
+
+func (*SqlValue_TextValue) isSqlValue_Value() {}
+
+func (*SqlValue_BlobValue) isSqlValue_Value() {}
+
+func (*SqlValue_BoolValue) isSqlValue_Value() {}
+
+func (*SqlValue_TimestampValue) isSqlValue_Value() {}
+
+type BatchStatement struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sql           string                 `protobuf:"bytes,1,opt,name=sql,proto3" json:"sql,omitempty"`
+	Args          []*SqlValue            `protobuf:"bytes,2,rep,name=args,proto3" json:"args,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchStatement) Reset() {
+	*x = BatchStatement{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchStatement) ProtoMessage() {}
+
+func (x *BatchStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchStatement.ProtoReflect.Descriptor instead.
+func (*BatchStatement) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BatchStatement) GetSql() string {
+	if x != nil {
+		return x.Sql
+	}
+	return ""
+}
+
+func (x *BatchStatement) GetArgs() []*SqlValue {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+type ExecBatchRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	Statements   []*BatchStatement      `protobuf:"bytes,2,rep,name=statements,proto3" json:"statements,omitempty"`
+	// Run every statement on its own instead of in a single transaction, a
+	// failing statement is reported and the rest still run. Not allowed
+	// together with transaction_id.
+	ContinueOnError bool   `protobuf:"varint,3,opt,name=continue_on_error,json=continueOnError,proto3" json:"continue_on_error,omitempty"`
+	TransactionId   string `protobuf:"bytes,4,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // Optional, runs the batch inside this transaction
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ExecBatchRequest) Reset() {
+	*x = ExecBatchRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecBatchRequest) ProtoMessage() {}
+
+func (x *ExecBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecBatchRequest.ProtoReflect.Descriptor instead.
+func (*ExecBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ExecBatchRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *ExecBatchRequest) GetStatements() []*BatchStatement {
+	if x != nil {
+		return x.Statements
+	}
+	return nil
+}
+
+func (x *ExecBatchRequest) GetContinueOnError() bool {
+	if x != nil {
+		return x.ContinueOnError
+	}
+	return false
+}
+
+func (x *ExecBatchRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+type StatementResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RowsAffected  int64                  `protobuf:"varint,1,opt,name=rows_affected,json=rowsAffected,proto3" json:"rows_affected,omitempty"`
+	LastInsertId  int64                  `protobuf:"varint,2,opt,name=last_insert_id,json=lastInsertId,proto3" json:"last_insert_id,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"` // Set if the statement failed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatementResult) Reset() {
+	*x = StatementResult{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatementResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatementResult) ProtoMessage() {}
+
+func (x *StatementResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatementResult.ProtoReflect.Descriptor instead.
+func (*StatementResult) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StatementResult) GetRowsAffected() int64 {
+	if x != nil {
+		return x.RowsAffected
+	}
+	return 0
+}
+
+func (x *StatementResult) GetLastInsertId() int64 {
+	if x != nil {
+		return x.LastInsertId
+	}
+	return 0
+}
+
+func (x *StatementResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ExecBatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One result per statement that ran, in order. When the batch stops on
+	// an error the last one is the failing statement.
+	Results []*StatementResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Failed  bool               `protobuf:"varint,2,opt,name=failed,proto3" json:"failed,omitempty"`
+	// Index of the first failing statement, only meaningful if failed is set.
+	// Without continue_on_error nothing in the batch was applied.
+	FailedIndex   int32 `protobuf:"varint,3,opt,name=failed_index,json=failedIndex,proto3" json:"failed_index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecBatchResponse) Reset() {
+	*x = ExecBatchResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecBatchResponse) ProtoMessage() {}
+
+func (x *ExecBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecBatchResponse.ProtoReflect.Descriptor instead.
+func (*ExecBatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ExecBatchResponse) GetResults() []*StatementResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *ExecBatchResponse) GetFailed() bool {
+	if x != nil {
+		return x.Failed
+	}
+	return false
+}
+
+func (x *ExecBatchResponse) GetFailedIndex() int32 {
+	if x != nil {
+		return x.FailedIndex
+	}
+	return 0
+}
+
+type BulkInsertHeader struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	Table         string                 `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"` // Optionally qualified with its schema, as in main.items
+	Columns       []string               `protobuf:"bytes,3,rep,name=columns,proto3" json:"columns,omitempty"`
+	TransactionId string                 `protobuf:"bytes,4,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // Optional, inserts inside this transaction
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkInsertHeader) Reset() {
+	*x = BulkInsertHeader{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkInsertHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkInsertHeader) ProtoMessage() {}
+
+func (x *BulkInsertHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkInsertHeader.ProtoReflect.Descriptor instead.
+func (*BulkInsertHeader) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BulkInsertHeader) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *BulkInsertHeader) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *BulkInsertHeader) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *BulkInsertHeader) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+type BulkInsertRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*BulkInsertRequest_Header
+	//	*BulkInsertRequest_Rows
+	Payload       isBulkInsertRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkInsertRequest) Reset() {
+	*x = BulkInsertRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkInsertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkInsertRequest) ProtoMessage() {}
+
+func (x *BulkInsertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkInsertRequest.ProtoReflect.Descriptor instead.
+func (*BulkInsertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BulkInsertRequest) GetPayload() isBulkInsertRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *BulkInsertRequest) GetHeader() *BulkInsertHeader {
+	if x != nil {
+		if x, ok := x.Payload.(*BulkInsertRequest_Header); ok {
+			return x.Header
+		}
+	}
+	return nil
+}
+
+func (x *BulkInsertRequest) GetRows() *RowBatch {
+	if x != nil {
+		if x, ok := x.Payload.(*BulkInsertRequest_Rows); ok {
+			return x.Rows
+		}
+	}
+	return nil
+}
+
+type isBulkInsertRequest_Payload interface {
+	isBulkInsertRequest_Payload()
+}
+
+type BulkInsertRequest_Header struct {
+	Header *BulkInsertHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+
+type BulkInsertRequest_Rows struct {
+	Rows *RowBatch `protobuf:"bytes,2,opt,name=rows,proto3,oneof"` // Each row has one value per header column
+}
+
+func (*BulkInsertRequest_Header) isBulkInsertRequest_Payload() {}
+
+func (*BulkInsertRequest_Rows) isBulkInsertRequest_Payload() {}
+
+type BulkInsertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RowsInserted  int64                  `protobuf:"varint,1,opt,name=rows_inserted,json=rowsInserted,proto3" json:"rows_inserted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkInsertResponse) Reset() {
+	*x = BulkInsertResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkInsertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkInsertResponse) ProtoMessage() {}
+
+func (x *BulkInsertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkInsertResponse.ProtoReflect.Descriptor instead.
+func (*BulkInsertResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *BulkInsertResponse) GetRowsInserted() int64 {
+	if x != nil {
+		return x.RowsInserted
+	}
+	return 0
+}
+
+type OpenCursorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	Sql           string                 `protobuf:"bytes,2,opt,name=sql,proto3" json:"sql,omitempty"`
+	Args          []*SqlValue            `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenCursorRequest) Reset() {
+	*x = OpenCursorRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenCursorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenCursorRequest) ProtoMessage() {}
+
+func (x *OpenCursorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenCursorRequest.ProtoReflect.Descriptor instead.
+func (*OpenCursorRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *OpenCursorRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *OpenCursorRequest) GetSql() string {
+	if x != nil {
+		return x.Sql
+	}
+	return ""
+}
+
+func (x *OpenCursorRequest) GetArgs() []*SqlValue {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+type OpenCursorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CursorId      string                 `protobuf:"bytes,1,opt,name=cursor_id,json=cursorId,proto3" json:"cursor_id,omitempty"`
+	Columns       *Columns               `protobuf:"bytes,2,opt,name=columns,proto3" json:"columns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenCursorResponse) Reset() {
+	*x = OpenCursorResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenCursorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenCursorResponse) ProtoMessage() {}
+
+func (x *OpenCursorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenCursorResponse.ProtoReflect.Descriptor instead.
+func (*OpenCursorResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *OpenCursorResponse) GetCursorId() string {
+	if x != nil {
+		return x.CursorId
+	}
+	return ""
+}
+
+func (x *OpenCursorResponse) GetColumns() *Columns {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+type FetchCursorRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	CursorId     string                 `protobuf:"bytes,2,opt,name=cursor_id,json=cursorId,proto3" json:"cursor_id,omitempty"`
+	MaxRows      int32                  `protobuf:"varint,3,opt,name=max_rows,json=maxRows,proto3" json:"max_rows,omitempty"` // 0 means server default
+	// Number of batches received so far. Asking again for the last one
+	// (sequence - 1) replays it, so a fetch lost on the wire can be retried.
+	Sequence      int64 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchCursorRequest) Reset() {
+	*x = FetchCursorRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchCursorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchCursorRequest) ProtoMessage() {}
+
+func (x *FetchCursorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchCursorRequest.ProtoReflect.Descriptor instead.
+func (*FetchCursorRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *FetchCursorRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *FetchCursorRequest) GetCursorId() string {
+	if x != nil {
+		return x.CursorId
+	}
+	return ""
+}
+
+func (x *FetchCursorRequest) GetMaxRows() int32 {
+	if x != nil {
+		return x.MaxRows
+	}
+	return 0
+}
+
+func (x *FetchCursorRequest) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type FetchCursorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Batch         *RowBatch              `protobuf:"bytes,1,opt,name=batch,proto3" json:"batch,omitempty"`
+	Done          bool                   `protobuf:"varint,2,opt,name=done,proto3" json:"done,omitempty"` // No more rows, the cursor has been closed by the server
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchCursorResponse) Reset() {
+	*x = FetchCursorResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchCursorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchCursorResponse) ProtoMessage() {}
+
+func (x *FetchCursorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchCursorResponse.ProtoReflect.Descriptor instead.
+func (*FetchCursorResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *FetchCursorResponse) GetBatch() *RowBatch {
+	if x != nil {
+		return x.Batch
+	}
+	return nil
+}
+
+func (x *FetchCursorResponse) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+type CloseCursorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	CursorId      string                 `protobuf:"bytes,2,opt,name=cursor_id,json=cursorId,proto3" json:"cursor_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseCursorRequest) Reset() {
+	*x = CloseCursorRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseCursorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseCursorRequest) ProtoMessage() {}
+
+func (x *CloseCursorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseCursorRequest.ProtoReflect.Descriptor instead.
+func (*CloseCursorRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CloseCursorRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *CloseCursorRequest) GetCursorId() string {
+	if x != nil {
+		return x.CursorId
+	}
+	return ""
+}
+
+type CloseCursorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseCursorResponse) Reset() {
+	*x = CloseCursorResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseCursorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseCursorResponse) ProtoMessage() {}
+
+func (x *CloseCursorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseCursorResponse.ProtoReflect.Descriptor instead.
+func (*CloseCursorResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{24}
+}
+
+type BeginTxRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	ReadOnly      bool                   `protobuf:"varint,2,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`  // Rejects writes for the lifetime of the transaction
+	Mode          TxMode                 `protobuf:"varint,3,opt,name=mode,proto3,enum=netsqlite.v1.TxMode" json:"mode,omitempty"` // DEFERRED too holds the writer, see BeginTx
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginTxRequest) Reset() {
+	*x = BeginTxRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginTxRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginTxRequest) ProtoMessage() {}
+
+func (x *BeginTxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginTxRequest.ProtoReflect.Descriptor instead.
+func (*BeginTxRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *BeginTxRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *BeginTxRequest) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+func (x *BeginTxRequest) GetMode() TxMode {
+	if x != nil {
+		return x.Mode
+	}
+	return TxMode_TX_MODE_DEFERRED
+}
+
+type BeginTxResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // Server issued, pass it along in Exec/Query/Commit/Rollback
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginTxResponse) Reset() {
+	*x = BeginTxResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginTxResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginTxResponse) ProtoMessage() {}
+
+func (x *BeginTxResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginTxResponse.ProtoReflect.Descriptor instead.
+func (*BeginTxResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *BeginTxResponse) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+type CommitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	TransactionId string                 `protobuf:"bytes,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitRequest) Reset() {
+	*x = CommitRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitRequest) ProtoMessage() {}
+
+func (x *CommitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitRequest.ProtoReflect.Descriptor instead.
+func (*CommitRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CommitRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *CommitRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+type CommitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitResponse) Reset() {
+	*x = CommitResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitResponse) ProtoMessage() {}
+
+func (x *CommitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitResponse.ProtoReflect.Descriptor instead.
+func (*CommitResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{28}
+}
+
+type RollbackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	TransactionId string                 `protobuf:"bytes,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackRequest) Reset() {
+	*x = RollbackRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackRequest) ProtoMessage() {}
+
+func (x *RollbackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackRequest.ProtoReflect.Descriptor instead.
+func (*RollbackRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RollbackRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *RollbackRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+type RollbackResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackResponse) Reset() {
+	*x = RollbackResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackResponse) ProtoMessage() {}
+
+func (x *RollbackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackResponse.ProtoReflect.Descriptor instead.
+func (*RollbackResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{30}
+}
+
+type PrepareRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // Client chosen, identifies the connection owning the statement
+	Sql           string                 `protobuf:"bytes,3,opt,name=sql,proto3" json:"sql,omitempty"`
+	TransactionId string                 `protobuf:"bytes,4,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // Optional, describe the statement inside this transaction
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PrepareRequest) Reset() {
+	*x = PrepareRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PrepareRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrepareRequest) ProtoMessage() {}
+
+func (x *PrepareRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrepareRequest.ProtoReflect.Descriptor instead.
+func (*PrepareRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *PrepareRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *PrepareRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *PrepareRequest) GetSql() string {
+	if x != nil {
+		return x.Sql
+	}
+	return ""
+}
+
+func (x *PrepareRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+type PrepareResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StatementId   string                 `protobuf:"bytes,1,opt,name=statement_id,json=statementId,proto3" json:"statement_id,omitempty"`
+	NumInput      int32                  `protobuf:"varint,2,opt,name=num_input,json=numInput,proto3" json:"num_input,omitempty"` // Number of parameters the statement expects
+	Columns       []string               `protobuf:"bytes,3,rep,name=columns,proto3" json:"columns,omitempty"`                    // Result columns, empty for statements without results
+	ReadOnly      bool                   `protobuf:"varint,4,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"` // True if the statement does not write to the database
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PrepareResponse) Reset() {
+	*x = PrepareResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PrepareResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrepareResponse) ProtoMessage() {}
+
+func (x *PrepareResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrepareResponse.ProtoReflect.Descriptor instead.
+func (*PrepareResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *PrepareResponse) GetStatementId() string {
+	if x != nil {
+		return x.StatementId
+	}
+	return ""
+}
+
+func (x *PrepareResponse) GetNumInput() int32 {
+	if x != nil {
+		return x.NumInput
+	}
+	return 0
+}
+
+func (x *PrepareResponse) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *PrepareResponse) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+type ExecPreparedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	StatementId   string                 `protobuf:"bytes,3,opt,name=statement_id,json=statementId,proto3" json:"statement_id,omitempty"`
+	Args          []*SqlValue            `protobuf:"bytes,6,rep,name=args,proto3" json:"args,omitempty"`
+	TransactionId string                 `protobuf:"bytes,5,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // Optional, runs the statement inside this transaction
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecPreparedRequest) Reset() {
+	*x = ExecPreparedRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecPreparedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecPreparedRequest) ProtoMessage() {}
+
+func (x *ExecPreparedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecPreparedRequest.ProtoReflect.Descriptor instead.
+func (*ExecPreparedRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ExecPreparedRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *ExecPreparedRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ExecPreparedRequest) GetStatementId() string {
+	if x != nil {
+		return x.StatementId
+	}
+	return ""
+}
+
+func (x *ExecPreparedRequest) GetArgs() []*SqlValue {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *ExecPreparedRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+type QueryPreparedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	StatementId   string                 `protobuf:"bytes,3,opt,name=statement_id,json=statementId,proto3" json:"statement_id,omitempty"`
+	Args          []*SqlValue            `protobuf:"bytes,6,rep,name=args,proto3" json:"args,omitempty"`
+	TransactionId string                 `protobuf:"bytes,5,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // Optional, runs the query inside this transaction
+	Batch         *BatchOptions          `protobuf:"bytes,7,opt,name=batch,proto3" json:"batch,omitempty"`                                      // Optional, server defaults apply otherwise
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryPreparedRequest) Reset() {
+	*x = QueryPreparedRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryPreparedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryPreparedRequest) ProtoMessage() {}
+
+func (x *QueryPreparedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryPreparedRequest.ProtoReflect.Descriptor instead.
+func (*QueryPreparedRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *QueryPreparedRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *QueryPreparedRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *QueryPreparedRequest) GetStatementId() string {
+	if x != nil {
+		return x.StatementId
+	}
+	return ""
+}
+
+func (x *QueryPreparedRequest) GetArgs() []*SqlValue {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *QueryPreparedRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *QueryPreparedRequest) GetBatch() *BatchOptions {
+	if x != nil {
+		return x.Batch
+	}
+	return nil
+}
+
+type CloseStmtRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	StatementId   string                 `protobuf:"bytes,3,opt,name=statement_id,json=statementId,proto3" json:"statement_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseStmtRequest) Reset() {
+	*x = CloseStmtRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseStmtRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseStmtRequest) ProtoMessage() {}
+
+func (x *CloseStmtRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseStmtRequest.ProtoReflect.Descriptor instead.
+func (*CloseStmtRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *CloseStmtRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *CloseStmtRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *CloseStmtRequest) GetStatementId() string {
+	if x != nil {
+		return x.StatementId
+	}
+	return ""
+}
+
+type CloseStmtResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseStmtResponse) Reset() {
+	*x = CloseStmtResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseStmtResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseStmtResponse) ProtoMessage() {}
+
+func (x *CloseStmtResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseStmtResponse.ProtoReflect.Descriptor instead.
+func (*CloseStmtResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{36}
+}
+
+type GetPragmasRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPragmasRequest) Reset() {
+	*x = GetPragmasRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPragmasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPragmasRequest) ProtoMessage() {}
+
+func (x *GetPragmasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPragmasRequest.ProtoReflect.Descriptor instead.
+func (*GetPragmasRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetPragmasRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+type Pragma struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`           // As reported by SQLite, empty if it reports nothing
+	Configured    string                 `protobuf:"bytes,3,opt,name=configured,proto3" json:"configured,omitempty"` // Value set by the server config, empty if left alone
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pragma) Reset() {
+	*x = Pragma{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pragma) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pragma) ProtoMessage() {}
+
+func (x *Pragma) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pragma.ProtoReflect.Descriptor instead.
+func (*Pragma) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *Pragma) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Pragma) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Pragma) GetConfigured() string {
+	if x != nil {
+		return x.Configured
+	}
+	return ""
+}
+
+type GetPragmasResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pragmas       []*Pragma              `protobuf:"bytes,1,rep,name=pragmas,proto3" json:"pragmas,omitempty"` // Sorted by name
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPragmasResponse) Reset() {
+	*x = GetPragmasResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPragmasResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPragmasResponse) ProtoMessage() {}
+
+func (x *GetPragmasResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPragmasResponse.ProtoReflect.Descriptor instead.
+func (*GetPragmasResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *GetPragmasResponse) GetPragmas() []*Pragma {
+	if x != nil {
+		return x.Pragmas
+	}
+	return nil
+}
+
+type DatabaseInfo struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Name         string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	SizeBytes    int64                  `protobuf:"varint,2,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`            // Of the database file
+	WalSizeBytes int64                  `protobuf:"varint,3,opt,name=wal_size_bytes,json=walSizeBytes,proto3" json:"wal_size_bytes,omitempty"` // Of its WAL file, 0 if there is none
+	PageSize     int64                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Pages still in the WAL are only counted for open databases
+	PageCount int64 `protobuf:"varint,5,opt,name=page_count,json=pageCount,proto3" json:"page_count,omitempty"`
+	Open      bool  `protobuf:"varint,6,opt,name=open,proto3" json:"open,omitempty"`
+	// Unset if not used since the server started
+	LastAccess    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_access,json=lastAccess,proto3" json:"last_access,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DatabaseInfo) Reset() {
+	*x = DatabaseInfo{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DatabaseInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DatabaseInfo) ProtoMessage() {}
+
+func (x *DatabaseInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DatabaseInfo.ProtoReflect.Descriptor instead.
+func (*DatabaseInfo) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DatabaseInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DatabaseInfo) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *DatabaseInfo) GetWalSizeBytes() int64 {
+	if x != nil {
+		return x.WalSizeBytes
+	}
+	return 0
+}
+
+func (x *DatabaseInfo) GetPageSize() int64 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *DatabaseInfo) GetPageCount() int64 {
+	if x != nil {
+		return x.PageCount
+	}
+	return 0
+}
+
+func (x *DatabaseInfo) GetOpen() bool {
+	if x != nil {
+		return x.Open
+	}
+	return false
+}
+
+func (x *DatabaseInfo) GetLastAccess() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAccess
+	}
+	return nil
+}
+
+type ListDatabasesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDatabasesRequest) Reset() {
+	*x = ListDatabasesRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDatabasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDatabasesRequest) ProtoMessage() {}
+
+func (x *ListDatabasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDatabasesRequest.ProtoReflect.Descriptor instead.
+func (*ListDatabasesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{41}
+}
+
+type ListDatabasesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Databases     []*DatabaseInfo        `protobuf:"bytes,1,rep,name=databases,proto3" json:"databases,omitempty"` // Sorted by name
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDatabasesResponse) Reset() {
+	*x = ListDatabasesResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDatabasesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDatabasesResponse) ProtoMessage() {}
+
+func (x *ListDatabasesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDatabasesResponse.ProtoReflect.Descriptor instead.
+func (*ListDatabasesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ListDatabasesResponse) GetDatabases() []*DatabaseInfo {
+	if x != nil {
+		return x.Databases
+	}
+	return nil
+}
+
+type GetDatabaseInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDatabaseInfoRequest) Reset() {
+	*x = GetDatabaseInfoRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDatabaseInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDatabaseInfoRequest) ProtoMessage() {}
+
+func (x *GetDatabaseInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDatabaseInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetDatabaseInfoRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *GetDatabaseInfoRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+type GetDatabaseInfoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Database      *DatabaseInfo          `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDatabaseInfoResponse) Reset() {
+	*x = GetDatabaseInfoResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDatabaseInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDatabaseInfoResponse) ProtoMessage() {}
+
+func (x *GetDatabaseInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDatabaseInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetDatabaseInfoResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *GetDatabaseInfoResponse) GetDatabase() *DatabaseInfo {
+	if x != nil {
+		return x.Database
+	}
+	return nil
+}
+
+type CreateDatabaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDatabaseRequest) Reset() {
+	*x = CreateDatabaseRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDatabaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDatabaseRequest) ProtoMessage() {}
+
+func (x *CreateDatabaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDatabaseRequest.ProtoReflect.Descriptor instead.
+func (*CreateDatabaseRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *CreateDatabaseRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+type CreateDatabaseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Database      *DatabaseInfo          `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDatabaseResponse) Reset() {
+	*x = CreateDatabaseResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDatabaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDatabaseResponse) ProtoMessage() {}
+
+func (x *CreateDatabaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDatabaseResponse.ProtoReflect.Descriptor instead.
+func (*CreateDatabaseResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *CreateDatabaseResponse) GetDatabase() *DatabaseInfo {
+	if x != nil {
+		return x.Database
+	}
+	return nil
+}
+
+type DropDatabaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DropDatabaseRequest) Reset() {
+	*x = DropDatabaseRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DropDatabaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropDatabaseRequest) ProtoMessage() {}
+
+func (x *DropDatabaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropDatabaseRequest.ProtoReflect.Descriptor instead.
+func (*DropDatabaseRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *DropDatabaseRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+type DropDatabaseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DropDatabaseResponse) Reset() {
+	*x = DropDatabaseResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DropDatabaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropDatabaseResponse) ProtoMessage() {}
+
+func (x *DropDatabaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropDatabaseResponse.ProtoReflect.Descriptor instead.
+func (*DropDatabaseResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{48}
+}
+
+type BackupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	ChunkBytes    int32                  `protobuf:"varint,2,opt,name=chunk_bytes,json=chunkBytes,proto3" json:"chunk_bytes,omitempty"` // Size of the chunks, 0 or too large for the server uses 1 MiB
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupRequest) Reset() {
+	*x = BackupRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupRequest) ProtoMessage() {}
+
+func (x *BackupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupRequest.ProtoReflect.Descriptor instead.
+func (*BackupRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *BackupRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *BackupRequest) GetChunkBytes() int32 {
+	if x != nil {
+		return x.ChunkBytes
+	}
+	return 0
+}
+
+type BackupSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SizeBytes     int64                  `protobuf:"varint,1,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	Sha256        string                 `protobuf:"bytes,2,opt,name=sha256,proto3" json:"sha256,omitempty"` // Hex encoded, of the whole file
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupSummary) Reset() {
+	*x = BackupSummary{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupSummary) ProtoMessage() {}
+
+func (x *BackupSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupSummary.ProtoReflect.Descriptor instead.
+func (*BackupSummary) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *BackupSummary) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *BackupSummary) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+type BackupResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*BackupResponse_Chunk
+	//	*BackupResponse_Summary
+	Payload       isBackupResponse_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupResponse) Reset() {
+	*x = BackupResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupResponse) ProtoMessage() {}
+
+func (x *BackupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupResponse.ProtoReflect.Descriptor instead.
+func (*BackupResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *BackupResponse) GetPayload() isBackupResponse_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *BackupResponse) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*BackupResponse_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+func (x *BackupResponse) GetSummary() *BackupSummary {
+	if x != nil {
+		if x, ok := x.Payload.(*BackupResponse_Summary); ok {
+			return x.Summary
+		}
+	}
+	return nil
+}
+
+type isBackupResponse_Payload interface {
+	isBackupResponse_Payload()
+}
+
+type BackupResponse_Chunk struct {
+	Chunk []byte `protobuf:"bytes,1,opt,name=chunk,proto3,oneof"` // The next bytes of the database file
+}
+
+type BackupResponse_Summary struct {
+	Summary *BackupSummary `protobuf:"bytes,2,opt,name=summary,proto3,oneof"` // Sent last, once every chunk was
+}
+
+func (*BackupResponse_Chunk) isBackupResponse_Payload() {}
+
+func (*BackupResponse_Summary) isBackupResponse_Payload() {}
+
+type RestoreHeader struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	Sha256        string                 `protobuf:"bytes,2,opt,name=sha256,proto3" json:"sha256,omitempty"` // Optional, hex encoded, of the whole file
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreHeader) Reset() {
+	*x = RestoreHeader{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreHeader) ProtoMessage() {}
+
+func (x *RestoreHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreHeader.ProtoReflect.Descriptor instead.
+func (*RestoreHeader) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *RestoreHeader) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *RestoreHeader) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+type RestoreRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*RestoreRequest_Header
+	//	*RestoreRequest_Chunk
+	Payload       isRestoreRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreRequest) Reset() {
+	*x = RestoreRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreRequest) ProtoMessage() {}
+
+func (x *RestoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreRequest.ProtoReflect.Descriptor instead.
+func (*RestoreRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *RestoreRequest) GetPayload() isRestoreRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *RestoreRequest) GetHeader() *RestoreHeader {
+	if x != nil {
+		if x, ok := x.Payload.(*RestoreRequest_Header); ok {
+			return x.Header
+		}
+	}
+	return nil
+}
+
+func (x *RestoreRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*RestoreRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isRestoreRequest_Payload interface {
+	isRestoreRequest_Payload()
+}
+
+type RestoreRequest_Header struct {
+	Header *RestoreHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+
+type RestoreRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"` // The next bytes of the SQLite file
+}
+
+func (*RestoreRequest_Header) isRestoreRequest_Payload() {}
+
+func (*RestoreRequest_Chunk) isRestoreRequest_Payload() {}
+
+type RestoreResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Database      *DatabaseInfo          `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreResponse) Reset() {
+	*x = RestoreResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreResponse) ProtoMessage() {}
+
+func (x *RestoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreResponse.ProtoReflect.Descriptor instead.
+func (*RestoreResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *RestoreResponse) GetDatabase() *DatabaseInfo {
+	if x != nil {
+		return x.Database
+	}
+	return nil
+}
+
+type ListBackupsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Databases     []string               `protobuf:"bytes,1,rep,name=databases,proto3" json:"databases,omitempty"` // Only the backups of these, if any
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBackupsRequest) Reset() {
+	*x = ListBackupsRequest{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBackupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBackupsRequest) ProtoMessage() {}
+
+func (x *ListBackupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBackupsRequest.ProtoReflect.Descriptor instead.
+func (*ListBackupsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ListBackupsRequest) GetDatabases() []string {
+	if x != nil {
+		return x.Databases
+	}
+	return nil
+}
+
+type BackupInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseName  string                 `protobuf:"bytes,1,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	FileName      string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"` // In the backup directory of the server
+	TakenAt       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=taken_at,json=takenAt,proto3" json:"taken_at,omitempty"`
+	SizeBytes     int64                  `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"` // Of the file, once compressed if it is
+	Sha256        string                 `protobuf:"bytes,5,opt,name=sha256,proto3" json:"sha256,omitempty"`                         // Hex encoded, of the file
+	Compressed    bool                   `protobuf:"varint,6,opt,name=compressed,proto3" json:"compressed,omitempty"`                // gzip
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupInfo) Reset() {
+	*x = BackupInfo{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupInfo) ProtoMessage() {}
+
+func (x *BackupInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupInfo.ProtoReflect.Descriptor instead.
+func (*BackupInfo) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *BackupInfo) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *BackupInfo) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *BackupInfo) GetTakenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TakenAt
+	}
+	return nil
+}
+
+func (x *BackupInfo) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *BackupInfo) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *BackupInfo) GetCompressed() bool {
+	if x != nil {
+		return x.Compressed
+	}
+	return false
+}
+
+type ListBackupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Backups       []*BackupInfo          `protobuf:"bytes,1,rep,name=backups,proto3" json:"backups,omitempty"` // By database name, most recent first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBackupsResponse) Reset() {
+	*x = ListBackupsResponse{}
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBackupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBackupsResponse) ProtoMessage() {}
+
+func (x *ListBackupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netsqlite_v1_netsqlite_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBackupsResponse.ProtoReflect.Descriptor instead.
+func (*ListBackupsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ListBackupsResponse) GetBackups() []*BackupInfo {
+	if x != nil {
+		return x.Backups
+	}
+	return nil
+}
+
+var File_proto_netsqlite_v1_netsqlite_proto protoreflect.FileDescriptor
+
+const file_proto_netsqlite_v1_netsqlite_proto_rawDesc = "" +
+	"\n" +
+	"\"proto/netsqlite/v1/netsqlite.proto\x12\fnetsqlite.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"2\n" +
+	"\vPingRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\"(\n" +
+	"\fPingResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\x9d\x01\n" +
+	"\vExecRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12\x10\n" +
+	"\x03sql\x18\x02 \x01(\tR\x03sql\x12%\n" +
+	"\x0etransaction_id\x18\x04 \x01(\tR\rtransactionId\x12*\n" +
+	"\x04args\x18\x05 \x03(\v2\x16.netsqlite.v1.SqlValueR\x04argsJ\x04\b\x03\x10\x04\"Y\n" +
+	"\fExecResponse\x12#\n" +
+	"\rrows_affected\x18\x01 \x01(\x03R\frowsAffected\x12$\n" +
+	"\x0elast_insert_id\x18\x02 \x01(\x03R\flastInsertId\"\xd0\x01\n" +
+	"\fQueryRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12\x10\n" +
+	"\x03sql\x18\x02 \x01(\tR\x03sql\x12%\n" +
+	"\x0etransaction_id\x18\x04 \x01(\tR\rtransactionId\x12*\n" +
+	"\x04args\x18\x05 \x03(\v2\x16.netsqlite.v1.SqlValueR\x04args\x120\n" +
+	"\x05batch\x18\x06 \x01(\v2\x1a.netsqlite.v1.BatchOptionsR\x05batchJ\x04\b\x03\x10\x04\"F\n" +
+	"\fBatchOptions\x12\x19\n" +
+	"\bmax_rows\x18\x01 \x01(\x05R\amaxRows\x12\x1b\n" +
+	"\tmax_bytes\x18\x02 \x01(\x03R\bmaxBytes\"\xa3\x01\n" +
+	"\rQueryResponse\x121\n" +
+	"\acolumns\x18\x01 \x01(\v2\x15.netsqlite.v1.ColumnsH\x00R\acolumns\x12%\n" +
+	"\x03row\x18\x02 \x01(\v2\x11.netsqlite.v1.RowH\x00R\x03row\x12.\n" +
+	"\x05batch\x18\x03 \x01(\v2\x16.netsqlite.v1.RowBatchH\x00R\x05batchB\b\n" +
+	"\x06result\"O\n" +
+	"\aColumns\x12\x14\n" +
+	"\x05names\x18\x01 \x03(\tR\x05names\x12.\n" +
+	"\x05types\x18\x02 \x03(\v2\x18.netsqlite.v1.ColumnTypeR\x05types\"\xb2\x01\n" +
+	"\n" +
+	"ColumnType\x12,\n" +
+	"\x12database_type_name\x18\x01 \x01(\tR\x10databaseTypeName\x12\x1a\n" +
+	"\bnullable\x18\x02 \x01(\bR\bnullable\x12%\n" +
+	"\x0enullable_known\x18\x03 \x01(\bR\rnullableKnown\x123\n" +
+	"\tscan_type\x18\x04 \x01(\x0e2\x16.netsqlite.v1.ScanTypeR\bscanType\";\n" +
+	"\x03Row\x12.\n" +
+	"\x06values\x18\x02 \x03(\v2\x16.netsqlite.v1.SqlValueR\x06valuesJ\x04\b\x01\x10\x02\"1\n" +
+	"\bRowBatch\x12%\n" +
+	"\x04rows\x18\x01 \x03(\v2\x11.netsqlite.v1.RowR\x04rows\"\xc2\x02\n" +
+	"\bSqlValue\x12;\n" +
+	"\n" +
+	"null_value\x18\x01 \x01(\x0e2\x1a.google.protobuf.NullValueH\x00R\tnullValue\x12!\n" +
+	"\vint64_value\x18\x02 \x01(\x03H\x00R\n" +
+	"int64Value\x12#\n" +
+	"\fdouble_value\x18\x03 \x01(\x01H\x00R\vdoubleValue\x12\x1f\n" +
+	"\n" +
+	"text_value\x18\x04 \x01(\tH\x00R\ttextValue\x12\x1f\n" +
+	"\n" +
+	"blob_value\x18\x05 \x01(\fH\x00R\tblobValue\x12\x1f\n" +
+	"\n" +
+	"bool_value\x18\x06 \x01(\bH\x00R\tboolValue\x12E\n" +
+	"\x0ftimestamp_value\x18\a \x01(\v2\x1a.google.protobuf.TimestampH\x00R\x0etimestampValueB\a\n" +
+	"\x05value\"N\n" +
+	"\x0eBatchStatement\x12\x10\n" +
+	"\x03sql\x18\x01 \x01(\tR\x03sql\x12*\n" +
+	"\x04args\x18\x02 \x03(\v2\x16.netsqlite.v1.SqlValueR\x04args\"\xc8\x01\n" +
+	"\x10ExecBatchRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12<\n" +
+	"\n" +
+	"statements\x18\x02 \x03(\v2\x1c.netsqlite.v1.BatchStatementR\n" +
+	"statements\x12*\n" +
+	"\x11continue_on_error\x18\x03 \x01(\bR\x0fcontinueOnError\x12%\n" +
+	"\x0etransaction_id\x18\x04 \x01(\tR\rtransactionId\"r\n" +
+	"\x0fStatementResult\x12#\n" +
+	"\rrows_affected\x18\x01 \x01(\x03R\frowsAffected\x12$\n" +
+	"\x0elast_insert_id\x18\x02 \x01(\x03R\flastInsertId\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\x87\x01\n" +
+	"\x11ExecBatchResponse\x127\n" +
+	"\aresults\x18\x01 \x03(\v2\x1d.netsqlite.v1.StatementResultR\aresults\x12\x16\n" +
+	"\x06failed\x18\x02 \x01(\bR\x06failed\x12!\n" +
+	"\ffailed_index\x18\x03 \x01(\x05R\vfailedIndex\"\x8e\x01\n" +
+	"\x10BulkInsertHeader\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12\x14\n" +
+	"\x05table\x18\x02 \x01(\tR\x05table\x12\x18\n" +
+	"\acolumns\x18\x03 \x03(\tR\acolumns\x12%\n" +
+	"\x0etransaction_id\x18\x04 \x01(\tR\rtransactionId\"\x86\x01\n" +
+	"\x11BulkInsertRequest\x128\n" +
+	"\x06header\x18\x01 \x01(\v2\x1e.netsqlite.v1.BulkInsertHeaderH\x00R\x06header\x12,\n" +
+	"\x04rows\x18\x02 \x01(\v2\x16.netsqlite.v1.RowBatchH\x00R\x04rowsB\t\n" +
+	"\apayload\"9\n" +
+	"\x12BulkInsertResponse\x12#\n" +
+	"\rrows_inserted\x18\x01 \x01(\x03R\frowsInserted\"v\n" +
+	"\x11OpenCursorRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12\x10\n" +
+	"\x03sql\x18\x02 \x01(\tR\x03sql\x12*\n" +
+	"\x04args\x18\x03 \x03(\v2\x16.netsqlite.v1.SqlValueR\x04args\"b\n" +
+	"\x12OpenCursorResponse\x12\x1b\n" +
+	"\tcursor_id\x18\x01 \x01(\tR\bcursorId\x12/\n" +
+	"\acolumns\x18\x02 \x01(\v2\x15.netsqlite.v1.ColumnsR\acolumns\"\x8d\x01\n" +
+	"\x12FetchCursorRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12\x1b\n" +
+	"\tcursor_id\x18\x02 \x01(\tR\bcursorId\x12\x19\n" +
+	"\bmax_rows\x18\x03 \x01(\x05R\amaxRows\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x03R\bsequence\"W\n" +
+	"\x13FetchCursorResponse\x12,\n" +
+	"\x05batch\x18\x01 \x01(\v2\x16.netsqlite.v1.RowBatchR\x05batch\x12\x12\n" +
+	"\x04done\x18\x02 \x01(\bR\x04done\"V\n" +
+	"\x12CloseCursorRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12\x1b\n" +
+	"\tcursor_id\x18\x02 \x01(\tR\bcursorId\"\x15\n" +
+	"\x13CloseCursorResponse\"|\n" +
+	"\x0eBeginTxRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12\x1b\n" +
+	"\tread_only\x18\x02 \x01(\bR\breadOnly\x12(\n" +
+	"\x04mode\x18\x03 \x01(\x0e2\x14.netsqlite.v1.TxModeR\x04mode\"8\n" +
+	"\x0fBeginTxResponse\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\"[\n" +
+	"\rCommitRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12%\n" +
+	"\x0etransaction_id\x18\x02 \x01(\tR\rtransactionId\"\x10\n" +
+	"\x0eCommitResponse\"]\n" +
+	"\x0fRollbackRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12%\n" +
+	"\x0etransaction_id\x18\x02 \x01(\tR\rtransactionId\"\x12\n" +
+	"\x10RollbackResponse\"\x8d\x01\n" +
+	"\x0ePrepareRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x10\n" +
+	"\x03sql\x18\x03 \x01(\tR\x03sql\x12%\n" +
+	"\x0etransaction_id\x18\x04 \x01(\tR\rtransactionId\"\x88\x01\n" +
+	"\x0fPrepareResponse\x12!\n" +
+	"\fstatement_id\x18\x01 \x01(\tR\vstatementId\x12\x1b\n" +
+	"\tnum_input\x18\x02 \x01(\x05R\bnumInput\x12\x18\n" +
+	"\acolumns\x18\x03 \x03(\tR\acolumns\x12\x1b\n" +
+	"\tread_only\x18\x04 \x01(\bR\breadOnly\"\xd5\x01\n" +
+	"\x13ExecPreparedRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12!\n" +
+	"\fstatement_id\x18\x03 \x01(\tR\vstatementId\x12*\n" +
+	"\x04args\x18\x06 \x03(\v2\x16.netsqlite.v1.SqlValueR\x04args\x12%\n" +
+	"\x0etransaction_id\x18\x05 \x01(\tR\rtransactionIdJ\x04\b\x04\x10\x05\"\x88\x02\n" +
+	"\x14QueryPreparedRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12!\n" +
+	"\fstatement_id\x18\x03 \x01(\tR\vstatementId\x12*\n" +
+	"\x04args\x18\x06 \x03(\v2\x16.netsqlite.v1.SqlValueR\x04args\x12%\n" +
+	"\x0etransaction_id\x18\x05 \x01(\tR\rtransactionId\x120\n" +
+	"\x05batch\x18\a \x01(\v2\x1a.netsqlite.v1.BatchOptionsR\x05batchJ\x04\b\x04\x10\x05\"y\n" +
+	"\x10CloseStmtRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12!\n" +
+	"\fstatement_id\x18\x03 \x01(\tR\vstatementId\"\x13\n" +
+	"\x11CloseStmtResponse\"8\n" +
+	"\x11GetPragmasRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\"R\n" +
+	"\x06Pragma\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x1e\n" +
+	"\n" +
+	"configured\x18\x03 \x01(\tR\n" +
+	"configured\"D\n" +
+	"\x12GetPragmasResponse\x12.\n" +
+	"\apragmas\x18\x01 \x03(\v2\x14.netsqlite.v1.PragmaR\apragmas\"\xf4\x01\n" +
+	"\fDatabaseInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x02 \x01(\x03R\tsizeBytes\x12$\n" +
+	"\x0ewal_size_bytes\x18\x03 \x01(\x03R\fwalSizeBytes\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x03R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_count\x18\x05 \x01(\x03R\tpageCount\x12\x12\n" +
+	"\x04open\x18\x06 \x01(\bR\x04open\x12;\n" +
+	"\vlast_access\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastAccess\"\x16\n" +
+	"\x14ListDatabasesRequest\"Q\n" +
+	"\x15ListDatabasesResponse\x128\n" +
+	"\tdatabases\x18\x01 \x03(\v2\x1a.netsqlite.v1.DatabaseInfoR\tdatabases\"=\n" +
+	"\x16GetDatabaseInfoRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\"Q\n" +
+	"\x17GetDatabaseInfoResponse\x126\n" +
+	"\bdatabase\x18\x01 \x01(\v2\x1a.netsqlite.v1.DatabaseInfoR\bdatabase\"<\n" +
+	"\x15CreateDatabaseRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\"P\n" +
+	"\x16CreateDatabaseResponse\x126\n" +
+	"\bdatabase\x18\x01 \x01(\v2\x1a.netsqlite.v1.DatabaseInfoR\bdatabase\":\n" +
+	"\x13DropDatabaseRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\"\x16\n" +
+	"\x14DropDatabaseResponse\"U\n" +
+	"\rBackupRequest\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12\x1f\n" +
+	"\vchunk_bytes\x18\x02 \x01(\x05R\n" +
+	"chunkBytes\"F\n" +
+	"\rBackupSummary\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x01 \x01(\x03R\tsizeBytes\x12\x16\n" +
+	"\x06sha256\x18\x02 \x01(\tR\x06sha256\"l\n" +
+	"\x0eBackupResponse\x12\x16\n" +
+	"\x05chunk\x18\x01 \x01(\fH\x00R\x05chunk\x127\n" +
+	"\asummary\x18\x02 \x01(\v2\x1b.netsqlite.v1.BackupSummaryH\x00R\asummaryB\t\n" +
+	"\apayload\"L\n" +
+	"\rRestoreHeader\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12\x16\n" +
+	"\x06sha256\x18\x02 \x01(\tR\x06sha256\"j\n" +
+	"\x0eRestoreRequest\x125\n" +
+	"\x06header\x18\x01 \x01(\v2\x1b.netsqlite.v1.RestoreHeaderH\x00R\x06header\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\t\n" +
+	"\apayload\"I\n" +
+	"\x0fRestoreResponse\x126\n" +
+	"\bdatabase\x18\x01 \x01(\v2\x1a.netsqlite.v1.DatabaseInfoR\bdatabase\"2\n" +
+	"\x12ListBackupsRequest\x12\x1c\n" +
+	"\tdatabases\x18\x01 \x03(\tR\tdatabases\"\xdc\x01\n" +
+	"\n" +
+	"BackupInfo\x12#\n" +
+	"\rdatabase_name\x18\x01 \x01(\tR\fdatabaseName\x12\x1b\n" +
+	"\tfile_name\x18\x02 \x01(\tR\bfileName\x125\n" +
+	"\btaken_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\atakenAt\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x04 \x01(\x03R\tsizeBytes\x12\x16\n" +
+	"\x06sha256\x18\x05 \x01(\tR\x06sha256\x12\x1e\n" +
+	"\n" +
+	"compressed\x18\x06 \x01(\bR\n" +
+	"compressed\"I\n" +
+	"\x13ListBackupsResponse\x122\n" +
+	"\abackups\x18\x01 \x03(\v2\x18.netsqlite.v1.BackupInfoR\abackups*\x9c\x01\n" +
+	"\bScanType\x12\x11\n" +
+	"\rSCAN_TYPE_ANY\x10\x00\x12\x13\n" +
+	"\x0fSCAN_TYPE_INT64\x10\x01\x12\x15\n" +
+	"\x11SCAN_TYPE_FLOAT64\x10\x02\x12\x14\n" +
+	"\x10SCAN_TYPE_STRING\x10\x03\x12\x13\n" +
+	"\x0fSCAN_TYPE_BYTES\x10\x04\x12\x12\n" +
+	"\x0eSCAN_TYPE_BOOL\x10\x05\x12\x12\n" +
+	"\x0eSCAN_TYPE_TIME\x10\x06*L\n" +
+	"\x06TxMode\x12\x14\n" +
+	"\x10TX_MODE_DEFERRED\x10\x00\x12\x15\n" +
+	"\x11TX_MODE_IMMEDIATE\x10\x01\x12\x15\n" +
+	"\x11TX_MODE_EXCLUSIVE\x10\x022\x9d\t\n" +
+	"\x10NetsqliteService\x12?\n" +
+	"\x04Ping\x12\x19.netsqlite.v1.PingRequest\x1a\x1a.netsqlite.v1.PingResponse\"\x00\x12?\n" +
+	"\x04Exec\x12\x19.netsqlite.v1.ExecRequest\x1a\x1a.netsqlite.v1.ExecResponse\"\x00\x12N\n" +
+	"\tExecBatch\x12\x1e.netsqlite.v1.ExecBatchRequest\x1a\x1f.netsqlite.v1.ExecBatchResponse\"\x00\x12S\n" +
+	"\n" +
+	"BulkInsert\x12\x1f.netsqlite.v1.BulkInsertRequest\x1a .netsqlite.v1.BulkInsertResponse\"\x00(\x01\x12D\n" +
+	"\x05Query\x12\x1a.netsqlite.v1.QueryRequest\x1a\x1b.netsqlite.v1.QueryResponse\"\x000\x01\x12Q\n" +
+	"\n" +
+	"OpenCursor\x12\x1f.netsqlite.v1.OpenCursorRequest\x1a .netsqlite.v1.OpenCursorResponse\"\x00\x12T\n" +
+	"\vFetchCursor\x12 .netsqlite.v1.FetchCursorRequest\x1a!.netsqlite.v1.FetchCursorResponse\"\x00\x12T\n" +
+	"\vCloseCursor\x12 .netsqlite.v1.CloseCursorRequest\x1a!.netsqlite.v1.CloseCursorResponse\"\x00\x12H\n" +
+	"\aBeginTx\x12\x1c.netsqlite.v1.BeginTxRequest\x1a\x1d.netsqlite.v1.BeginTxResponse\"\x00\x12E\n" +
+	"\x06Commit\x12\x1b.netsqlite.v1.CommitRequest\x1a\x1c.netsqlite.v1.CommitResponse\"\x00\x12K\n" +
+	"\bRollback\x12\x1d.netsqlite.v1.RollbackRequest\x1a\x1e.netsqlite.v1.RollbackResponse\"\x00\x12H\n" +
+	"\aPrepare\x12\x1c.netsqlite.v1.PrepareRequest\x1a\x1d.netsqlite.v1.PrepareResponse\"\x00\x12O\n" +
+	"\fExecPrepared\x12!.netsqlite.v1.ExecPreparedRequest\x1a\x1a.netsqlite.v1.ExecResponse\"\x00\x12T\n" +
+	"\rQueryPrepared\x12\".netsqlite.v1.QueryPreparedRequest\x1a\x1b.netsqlite.v1.QueryResponse\"\x000\x01\x12N\n" +
+	"\tCloseStmt\x12\x1e.netsqlite.v1.CloseStmtRequest\x1a\x1f.netsqlite.v1.CloseStmtResponse\"\x002\xc2\x05\n" +
+	"\fAdminService\x12Q\n" +
+	"\n" +
+	"GetPragmas\x12\x1f.netsqlite.v1.GetPragmasRequest\x1a .netsqlite.v1.GetPragmasResponse\"\x00\x12Z\n" +
+	"\rListDatabases\x12\".netsqlite.v1.ListDatabasesRequest\x1a#.netsqlite.v1.ListDatabasesResponse\"\x00\x12`\n" +
+	"\x0fGetDatabaseInfo\x12$.netsqlite.v1.GetDatabaseInfoRequest\x1a%.netsqlite.v1.GetDatabaseInfoResponse\"\x00\x12]\n" +
+	"\x0eCreateDatabase\x12#.netsqlite.v1.CreateDatabaseRequest\x1a$.netsqlite.v1.CreateDatabaseResponse\"\x00\x12W\n" +
+	"\fDropDatabase\x12!.netsqlite.v1.DropDatabaseRequest\x1a\".netsqlite.v1.DropDatabaseResponse\"\x00\x12G\n" +
+	"\x06Backup\x12\x1b.netsqlite.v1.BackupRequest\x1a\x1c.netsqlite.v1.BackupResponse\"\x000\x01\x12J\n" +
+	"\aRestore\x12\x1c.netsqlite.v1.RestoreRequest\x1a\x1d.netsqlite.v1.RestoreResponse\"\x00(\x01\x12T\n" +
+	"\vListBackups\x12 .netsqlite.v1.ListBackupsRequest\x1a!.netsqlite.v1.ListBackupsResponse\"\x00B!Z\x1f/proto/netsqlite/v1;netsqlitev1b\x06proto3"
+
+var (
+	file_proto_netsqlite_v1_netsqlite_proto_rawDescOnce sync.Once
+	file_proto_netsqlite_v1_netsqlite_proto_rawDescData []byte
+)
+
+func file_proto_netsqlite_v1_netsqlite_proto_rawDescGZIP() []byte {
+	file_proto_netsqlite_v1_netsqlite_proto_rawDescOnce.Do(func() {
+		file_proto_netsqlite_v1_netsqlite_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_netsqlite_v1_netsqlite_proto_rawDesc), len(file_proto_netsqlite_v1_netsqlite_proto_rawDesc)))
+	})
+	return file_proto_netsqlite_v1_netsqlite_proto_rawDescData
+}
+
+var file_proto_netsqlite_v1_netsqlite_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_netsqlite_v1_netsqlite_proto_msgTypes = make([]protoimpl.MessageInfo, 58)
+var file_proto_netsqlite_v1_netsqlite_proto_goTypes = []any{
+	(ScanType)(0),                   // 0: netsqlite.v1.ScanType
+	(TxMode)(0),                     // 1: netsqlite.v1.TxMode
+	(*PingRequest)(nil),             // 2: netsqlite.v1.PingRequest
+	(*PingResponse)(nil),            // 3: netsqlite.v1.PingResponse
+	(*ExecRequest)(nil),             // 4: netsqlite.v1.ExecRequest
+	(*ExecResponse)(nil),            // 5: netsqlite.v1.ExecResponse
+	(*QueryRequest)(nil),            // 6: netsqlite.v1.QueryRequest
+	(*BatchOptions)(nil),            // 7: netsqlite.v1.BatchOptions
+	(*QueryResponse)(nil),           // 8: netsqlite.v1.QueryResponse
+	(*Columns)(nil),                 // 9: netsqlite.v1.Columns
+	(*ColumnType)(nil),              // 10: netsqlite.v1.ColumnType
+	(*Row)(nil),                     // 11: netsqlite.v1.Row
+	(*RowBatch)(nil),                // 12: netsqlite.v1.RowBatch
+	(*SqlValue)(nil),                // 13: netsqlite.v1.SqlValue
+	(*BatchStatement)(nil),          // 14: netsqlite.v1.BatchStatement
+	(*ExecBatchRequest)(nil),        // 15: netsqlite.v1.ExecBatchRequest
+	(*StatementResult)(nil),         // 16: netsqlite.v1.StatementResult
+	(*ExecBatchResponse)(nil),       // 17: netsqlite.v1.ExecBatchResponse
+	(*BulkInsertHeader)(nil),        // 18: netsqlite.v1.BulkInsertHeader
+	(*BulkInsertRequest)(nil),       // 19: netsqlite.v1.BulkInsertRequest
+	(*BulkInsertResponse)(nil),      // 20: netsqlite.v1.BulkInsertResponse
+	(*OpenCursorRequest)(nil),       // 21: netsqlite.v1.OpenCursorRequest
+	(*OpenCursorResponse)(nil),      // 22: netsqlite.v1.OpenCursorResponse
+	(*FetchCursorRequest)(nil),      // 23: netsqlite.v1.FetchCursorRequest
+	(*FetchCursorResponse)(nil),     // 24: netsqlite.v1.FetchCursorResponse
+	(*CloseCursorRequest)(nil),      // 25: netsqlite.v1.CloseCursorRequest
+	(*CloseCursorResponse)(nil),     // 26: netsqlite.v1.CloseCursorResponse
+	(*BeginTxRequest)(nil),          // 27: netsqlite.v1.BeginTxRequest
+	(*BeginTxResponse)(nil),         // 28: netsqlite.v1.BeginTxResponse
+	(*CommitRequest)(nil),           // 29: netsqlite.v1.CommitRequest
+	(*CommitResponse)(nil),          // 30: netsqlite.v1.CommitResponse
+	(*RollbackRequest)(nil),         // 31: netsqlite.v1.RollbackRequest
+	(*RollbackResponse)(nil),        // 32: netsqlite.v1.RollbackResponse
+	(*PrepareRequest)(nil),          // 33: netsqlite.v1.PrepareRequest
+	(*PrepareResponse)(nil),         // 34: netsqlite.v1.PrepareResponse
+	(*ExecPreparedRequest)(nil),     // 35: netsqlite.v1.ExecPreparedRequest
+	(*QueryPreparedRequest)(nil),    // 36: netsqlite.v1.QueryPreparedRequest
+	(*CloseStmtRequest)(nil),        // 37: netsqlite.v1.CloseStmtRequest
+	(*CloseStmtResponse)(nil),       // 38: netsqlite.v1.CloseStmtResponse
+	(*GetPragmasRequest)(nil),       // 39: netsqlite.v1.GetPragmasRequest
+	(*Pragma)(nil),                  // 40: netsqlite.v1.Pragma
+	(*GetPragmasResponse)(nil),      // 41: netsqlite.v1.GetPragmasResponse
+	(*DatabaseInfo)(nil),            // 42: netsqlite.v1.DatabaseInfo
+	(*ListDatabasesRequest)(nil),    // 43: netsqlite.v1.ListDatabasesRequest
+	(*ListDatabasesResponse)(nil),   // 44: netsqlite.v1.ListDatabasesResponse
+	(*GetDatabaseInfoRequest)(nil),  // 45: netsqlite.v1.GetDatabaseInfoRequest
+	(*GetDatabaseInfoResponse)(nil), // 46: netsqlite.v1.GetDatabaseInfoResponse
+	(*CreateDatabaseRequest)(nil),   // 47: netsqlite.v1.CreateDatabaseRequest
+	(*CreateDatabaseResponse)(nil),  // 48: netsqlite.v1.CreateDatabaseResponse
+	(*DropDatabaseRequest)(nil),     // 49: netsqlite.v1.DropDatabaseRequest
+	(*DropDatabaseResponse)(nil),    // 50: netsqlite.v1.DropDatabaseResponse
+	(*BackupRequest)(nil),           // 51: netsqlite.v1.BackupRequest
+	(*BackupSummary)(nil),           // 52: netsqlite.v1.BackupSummary
+	(*BackupResponse)(nil),          // 53: netsqlite.v1.BackupResponse
+	(*RestoreHeader)(nil),           // 54: netsqlite.v1.RestoreHeader
+	(*RestoreRequest)(nil),          // 55: netsqlite.v1.RestoreRequest
+	(*RestoreResponse)(nil),         // 56: netsqlite.v1.RestoreResponse
+	(*ListBackupsRequest)(nil),      // 57: netsqlite.v1.ListBackupsRequest
+	(*BackupInfo)(nil),              // 58: netsqlite.v1.BackupInfo
+	(*ListBackupsResponse)(nil),     // 59: netsqlite.v1.ListBackupsResponse
+	(structpb.NullValue)(0),         // 60: google.protobuf.NullValue
+	(*timestamppb.Timestamp)(nil),   // 61: google.protobuf.Timestamp
+}
+var file_proto_netsqlite_v1_netsqlite_proto_depIdxs = []int32{
+	13, // 0: netsqlite.v1.ExecRequest.args:type_name -> netsqlite.v1.SqlValue
+	13, // 1: netsqlite.v1.QueryRequest.args:type_name -> netsqlite.v1.SqlValue
+	7,  // 2: netsqlite.v1.QueryRequest.batch:type_name -> netsqlite.v1.BatchOptions
+	9,  // 3: netsqlite.v1.QueryResponse.columns:type_name -> netsqlite.v1.Columns
+	11, // 4: netsqlite.v1.QueryResponse.row:type_name -> netsqlite.v1.Row
+	12, // 5: netsqlite.v1.QueryResponse.batch:type_name -> netsqlite.v1.RowBatch
+	10, // 6: netsqlite.v1.Columns.types:type_name -> netsqlite.v1.ColumnType
+	0,  // 7: netsqlite.v1.ColumnType.scan_type:type_name -> netsqlite.v1.ScanType
+	13, // 8: netsqlite.v1.Row.values:type_name -> netsqlite.v1.SqlValue
+	11, // 9: netsqlite.v1.RowBatch.rows:type_name -> netsqlite.v1.Row
+	60, // 10: netsqlite.v1.SqlValue.null_value:type_name -> google.protobuf.NullValue
+	61, // 11: netsqlite.v1.SqlValue.timestamp_value:type_name -> google.protobuf.Timestamp
+	13, // 12: netsqlite.v1.BatchStatement.args:type_name -> netsqlite.v1.SqlValue
+	14, // 13: netsqlite.v1.ExecBatchRequest.statements:type_name -> netsqlite.v1.BatchStatement
+	16, // 14: netsqlite.v1.ExecBatchResponse.results:type_name -> netsqlite.v1.StatementResult
+	18, // 15: netsqlite.v1.BulkInsertRequest.header:type_name -> netsqlite.v1.BulkInsertHeader
+	12, // 16: netsqlite.v1.BulkInsertRequest.rows:type_name -> netsqlite.v1.RowBatch
+	13, // 17: netsqlite.v1.OpenCursorRequest.args:type_name -> netsqlite.v1.SqlValue
+	9,  // 18: netsqlite.v1.OpenCursorResponse.columns:type_name -> netsqlite.v1.Columns
+	12, // 19: netsqlite.v1.FetchCursorResponse.batch:type_name -> netsqlite.v1.RowBatch
+	1,  // 20: netsqlite.v1.BeginTxRequest.mode:type_name -> netsqlite.v1.TxMode
+	13, // 21: netsqlite.v1.ExecPreparedRequest.args:type_name -> netsqlite.v1.SqlValue
+	13, // 22: netsqlite.v1.QueryPreparedRequest.args:type_name -> netsqlite.v1.SqlValue
+	7,  // 23: netsqlite.v1.QueryPreparedRequest.batch:type_name -> netsqlite.v1.BatchOptions
+	40, // 24: netsqlite.v1.GetPragmasResponse.pragmas:type_name -> netsqlite.v1.Pragma
+	61, // 25: netsqlite.v1.DatabaseInfo.last_access:type_name -> google.protobuf.Timestamp
+	42, // 26: netsqlite.v1.ListDatabasesResponse.databases:type_name -> netsqlite.v1.DatabaseInfo
+	42, // 27: netsqlite.v1.GetDatabaseInfoResponse.database:type_name -> netsqlite.v1.DatabaseInfo
+	42, // 28: netsqlite.v1.CreateDatabaseResponse.database:type_name -> netsqlite.v1.DatabaseInfo
+	52, // 29: netsqlite.v1.BackupResponse.summary:type_name -> netsqlite.v1.BackupSummary
+	54, // 30: netsqlite.v1.RestoreRequest.header:type_name -> netsqlite.v1.RestoreHeader
+	42, // 31: netsqlite.v1.RestoreResponse.database:type_name -> netsqlite.v1.DatabaseInfo
+	61, // 32: netsqlite.v1.BackupInfo.taken_at:type_name -> google.protobuf.Timestamp
+	58, // 33: netsqlite.v1.ListBackupsResponse.backups:type_name -> netsqlite.v1.BackupInfo
+	2,  // 34: netsqlite.v1.NetsqliteService.Ping:input_type -> netsqlite.v1.PingRequest
+	4,  // 35: netsqlite.v1.NetsqliteService.Exec:input_type -> netsqlite.v1.ExecRequest
+	15, // 36: netsqlite.v1.NetsqliteService.ExecBatch:input_type -> netsqlite.v1.ExecBatchRequest
+	19, // 37: netsqlite.v1.NetsqliteService.BulkInsert:input_type -> netsqlite.v1.BulkInsertRequest
+	6,  // 38: netsqlite.v1.NetsqliteService.Query:input_type -> netsqlite.v1.QueryRequest
+	21, // 39: netsqlite.v1.NetsqliteService.OpenCursor:input_type -> netsqlite.v1.OpenCursorRequest
+	23, // 40: netsqlite.v1.NetsqliteService.FetchCursor:input_type -> netsqlite.v1.FetchCursorRequest
+	25, // 41: netsqlite.v1.NetsqliteService.CloseCursor:input_type -> netsqlite.v1.CloseCursorRequest
+	27, // 42: netsqlite.v1.NetsqliteService.BeginTx:input_type -> netsqlite.v1.BeginTxRequest
+	29, // 43: netsqlite.v1.NetsqliteService.Commit:input_type -> netsqlite.v1.CommitRequest
+	31, // 44: netsqlite.v1.NetsqliteService.Rollback:input_type -> netsqlite.v1.RollbackRequest
+	33, // 45: netsqlite.v1.NetsqliteService.Prepare:input_type -> netsqlite.v1.PrepareRequest
+	35, // 46: netsqlite.v1.NetsqliteService.ExecPrepared:input_type -> netsqlite.v1.ExecPreparedRequest
+	36, // 47: netsqlite.v1.NetsqliteService.QueryPrepared:input_type -> netsqlite.v1.QueryPreparedRequest
+	37, // 48: netsqlite.v1.NetsqliteService.CloseStmt:input_type -> netsqlite.v1.CloseStmtRequest
+	39, // 49: netsqlite.v1.AdminService.GetPragmas:input_type -> netsqlite.v1.GetPragmasRequest
+	43, // 50: netsqlite.v1.AdminService.ListDatabases:input_type -> netsqlite.v1.ListDatabasesRequest
+	45, // 51: netsqlite.v1.AdminService.GetDatabaseInfo:input_type -> netsqlite.v1.GetDatabaseInfoRequest
+	47, // 52: netsqlite.v1.AdminService.CreateDatabase:input_type -> netsqlite.v1.CreateDatabaseRequest
+	49, // 53: netsqlite.v1.AdminService.DropDatabase:input_type -> netsqlite.v1.DropDatabaseRequest
+	51, // 54: netsqlite.v1.AdminService.Backup:input_type -> netsqlite.v1.BackupRequest
+	55, // 55: netsqlite.v1.AdminService.Restore:input_type -> netsqlite.v1.RestoreRequest
+	57, // 56: netsqlite.v1.AdminService.ListBackups:input_type -> netsqlite.v1.ListBackupsRequest
+	3,  // 57: netsqlite.v1.NetsqliteService.Ping:output_type -> netsqlite.v1.PingResponse
+	5,  // 58: netsqlite.v1.NetsqliteService.Exec:output_type -> netsqlite.v1.ExecResponse
+	17, // 59: netsqlite.v1.NetsqliteService.ExecBatch:output_type -> netsqlite.v1.ExecBatchResponse
+	20, // 60: netsqlite.v1.NetsqliteService.BulkInsert:output_type -> netsqlite.v1.BulkInsertResponse
+	8,  // 61: netsqlite.v1.NetsqliteService.Query:output_type -> netsqlite.v1.QueryResponse
+	22, // 62: netsqlite.v1.NetsqliteService.OpenCursor:output_type -> netsqlite.v1.OpenCursorResponse
+	24, // 63: netsqlite.v1.NetsqliteService.FetchCursor:output_type -> netsqlite.v1.FetchCursorResponse
+	26, // 64: netsqlite.v1.NetsqliteService.CloseCursor:output_type -> netsqlite.v1.CloseCursorResponse
+	28, // 65: netsqlite.v1.NetsqliteService.BeginTx:output_type -> netsqlite.v1.BeginTxResponse
+	30, // 66: netsqlite.v1.NetsqliteService.Commit:output_type -> netsqlite.v1.CommitResponse
+	32, // 67: netsqlite.v1.NetsqliteService.Rollback:output_type -> netsqlite.v1.RollbackResponse
+	34, // 68: netsqlite.v1.NetsqliteService.Prepare:output_type -> netsqlite.v1.PrepareResponse
+	5,  // 69: netsqlite.v1.NetsqliteService.ExecPrepared:output_type -> netsqlite.v1.ExecResponse
+	8,  // 70: netsqlite.v1.NetsqliteService.QueryPrepared:output_type -> netsqlite.v1.QueryResponse
+	38, // 71: netsqlite.v1.NetsqliteService.CloseStmt:output_type -> netsqlite.v1.CloseStmtResponse
+	41, // 72: netsqlite.v1.AdminService.GetPragmas:output_type -> netsqlite.v1.GetPragmasResponse
+	44, // 73: netsqlite.v1.AdminService.ListDatabases:output_type -> netsqlite.v1.ListDatabasesResponse
+	46, // 74: netsqlite.v1.AdminService.GetDatabaseInfo:output_type -> netsqlite.v1.GetDatabaseInfoResponse
+	48, // 75: netsqlite.v1.AdminService.CreateDatabase:output_type -> netsqlite.v1.CreateDatabaseResponse
+	50, // 76: netsqlite.v1.AdminService.DropDatabase:output_type -> netsqlite.v1.DropDatabaseResponse
+	53, // 77: netsqlite.v1.AdminService.Backup:output_type -> netsqlite.v1.BackupResponse
+	56, // 78: netsqlite.v1.AdminService.Restore:output_type -> netsqlite.v1.RestoreResponse
+	59, // 79: netsqlite.v1.AdminService.ListBackups:output_type -> netsqlite.v1.ListBackupsResponse
+	57, // [57:80] is the sub-list for method output_type
+	34, // [34:57] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
+}
+
+func init() { file_proto_netsqlite_v1_netsqlite_proto_init() }
+func file_proto_netsqlite_v1_netsqlite_proto_init() {
+	if File_proto_netsqlite_v1_netsqlite_proto != nil {
+		return
+	}
+	file_proto_netsqlite_v1_netsqlite_proto_msgTypes[6].OneofWrappers = []any{
+		(*QueryResponse_Columns)(nil),
+		(*QueryResponse_Row)(nil),
+		(*QueryResponse_Batch)(nil),
+	}
+	file_proto_netsqlite_v1_netsqlite_proto_msgTypes[11].OneofWrappers = []any{
+		(*SqlValue_NullValue)(nil),
+		(*SqlValue_Int64Value)(nil),
+		(*SqlValue_DoubleValue)(nil),
+		(*SqlValue_TextValue)(nil),
+		(*SqlValue_BlobValue)(nil),
+		(*SqlValue_BoolValue)(nil),
+		(*SqlValue_TimestampValue)(nil),
+	}
+	file_proto_netsqlite_v1_netsqlite_proto_msgTypes[17].OneofWrappers = []any{
+		(*BulkInsertRequest_Header)(nil),
+		(*BulkInsertRequest_Rows)(nil),
+	}
+	file_proto_netsqlite_v1_netsqlite_proto_msgTypes[51].OneofWrappers = []any{
+		(*BackupResponse_Chunk)(nil),
+		(*BackupResponse_Summary)(nil),
+	}
+	file_proto_netsqlite_v1_netsqlite_proto_msgTypes[53].OneofWrappers = []any{
+		(*RestoreRequest_Header)(nil),
+		(*RestoreRequest_Chunk)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_netsqlite_v1_netsqlite_proto_rawDesc), len(file_proto_netsqlite_v1_netsqlite_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      2,
+			NumMessages:   58,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_proto_netsqlite_v1_netsqlite_proto_goTypes,
 		DependencyIndexes: file_proto_netsqlite_v1_netsqlite_proto_depIdxs,
+		EnumInfos:         file_proto_netsqlite_v1_netsqlite_proto_enumTypes,
 		MessageInfos:      file_proto_netsqlite_v1_netsqlite_proto_msgTypes,
 	}.Build()
 	File_proto_netsqlite_v1_netsqlite_proto = out.File
@@ -21,9 +21,21 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	NetsqliteService_Ping_FullMethodName  = "/netsqlite.v1.NetsqliteService/Ping"
-	NetsqliteService_Exec_FullMethodName  = "/netsqlite.v1.NetsqliteService/Exec"
-	NetsqliteService_Query_FullMethodName = "/netsqlite.v1.NetsqliteService/Query"
+	NetsqliteService_Ping_FullMethodName          = "/netsqlite.v1.NetsqliteService/Ping"
+	NetsqliteService_Exec_FullMethodName          = "/netsqlite.v1.NetsqliteService/Exec"
+	NetsqliteService_ExecBatch_FullMethodName     = "/netsqlite.v1.NetsqliteService/ExecBatch"
+	NetsqliteService_BulkInsert_FullMethodName    = "/netsqlite.v1.NetsqliteService/BulkInsert"
+	NetsqliteService_Query_FullMethodName         = "/netsqlite.v1.NetsqliteService/Query"
+	NetsqliteService_OpenCursor_FullMethodName    = "/netsqlite.v1.NetsqliteService/OpenCursor"
+	NetsqliteService_FetchCursor_FullMethodName   = "/netsqlite.v1.NetsqliteService/FetchCursor"
+	NetsqliteService_CloseCursor_FullMethodName   = "/netsqlite.v1.NetsqliteService/CloseCursor"
+	NetsqliteService_BeginTx_FullMethodName       = "/netsqlite.v1.NetsqliteService/BeginTx"
+	NetsqliteService_Commit_FullMethodName        = "/netsqlite.v1.NetsqliteService/Commit"
+	NetsqliteService_Rollback_FullMethodName      = "/netsqlite.v1.NetsqliteService/Rollback"
+	NetsqliteService_Prepare_FullMethodName       = "/netsqlite.v1.NetsqliteService/Prepare"
+	NetsqliteService_ExecPrepared_FullMethodName  = "/netsqlite.v1.NetsqliteService/ExecPrepared"
+	NetsqliteService_QueryPrepared_FullMethodName = "/netsqlite.v1.NetsqliteService/QueryPrepared"
+	NetsqliteService_CloseStmt_FullMethodName     = "/netsqlite.v1.NetsqliteService/CloseStmt"
 )
 
 // NetsqliteServiceClient is the client API for NetsqliteService service.
@@ -36,8 +48,42 @@ type NetsqliteServiceClient interface {
 	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
 	// Execute a non-query statement (INSERT, UPDATE, DELETE)
 	Exec(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (*ExecResponse, error)
+	// Execute an ordered list of statements on a single connection. By default
+	// they run atomically, the first failure rolls everything back.
+	ExecBatch(ctx context.Context, in *ExecBatchRequest, opts ...grpc.CallOption) (*ExecBatchResponse, error)
+	// Insert a stream of rows into a table with a single prepared INSERT, all
+	// in one transaction. The first message must be the header.
+	BulkInsert(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[BulkInsertRequest, BulkInsertResponse], error)
 	// Execute a query statement (SELECT) - streams results back
 	Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[QueryResponse], error)
+	// Open a server side cursor over a query, rows are pulled with FetchCursor.
+	// The cursor holds a pooled connection until it is exhausted, closed or expires.
+	// For a query that writes that's the database's single writer, and it
+	// expires after the server's write_tx_idle_timeout if shorter.
+	OpenCursor(ctx context.Context, in *OpenCursorRequest, opts ...grpc.CallOption) (*OpenCursorResponse, error)
+	// Fetch the next rows of a cursor
+	FetchCursor(ctx context.Context, in *FetchCursorRequest, opts ...grpc.CallOption) (*FetchCursorResponse, error)
+	// Close a cursor and release its connection
+	CloseCursor(ctx context.Context, in *CloseCursorRequest, opts ...grpc.CallOption) (*CloseCursorResponse, error)
+	// Start a transaction pinned to a single pooled connection. Subsequent
+	// Exec/Query calls carrying the returned transaction_id run inside it.
+	// Unless read_only, it holds the database's single writer connection until
+	// it ends, whatever its mode, so every other write waits for it; it's
+	// rolled back once idle for the server's write_tx_idle_timeout.
+	BeginTx(ctx context.Context, in *BeginTxRequest, opts ...grpc.CallOption) (*BeginTxResponse, error)
+	// Commit a transaction previously started with BeginTx
+	Commit(ctx context.Context, in *CommitRequest, opts ...grpc.CallOption) (*CommitResponse, error)
+	// Roll back a transaction previously started with BeginTx
+	Rollback(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*RollbackResponse, error)
+	// Prepare a statement once and run it many times by its statement_id.
+	// Statements are scoped to the client session that prepared them.
+	Prepare(ctx context.Context, in *PrepareRequest, opts ...grpc.CallOption) (*PrepareResponse, error)
+	// Execute a prepared non-query statement
+	ExecPrepared(ctx context.Context, in *ExecPreparedRequest, opts ...grpc.CallOption) (*ExecResponse, error)
+	// Execute a prepared query - streams results back like Query
+	QueryPrepared(ctx context.Context, in *QueryPreparedRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[QueryResponse], error)
+	// Release a prepared statement on the server
+	CloseStmt(ctx context.Context, in *CloseStmtRequest, opts ...grpc.CallOption) (*CloseStmtResponse, error)
 }
 
 type netsqliteServiceClient struct {
@@ -68,9 +114,32 @@ func (c *netsqliteServiceClient) Exec(ctx context.Context, in *ExecRequest, opts
 	return out, nil
 }
 
+func (c *netsqliteServiceClient) ExecBatch(ctx context.Context, in *ExecBatchRequest, opts ...grpc.CallOption) (*ExecBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecBatchResponse)
+	err := c.cc.Invoke(ctx, NetsqliteService_ExecBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netsqliteServiceClient) BulkInsert(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[BulkInsertRequest, BulkInsertResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NetsqliteService_ServiceDesc.Streams[0], NetsqliteService_BulkInsert_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BulkInsertRequest, BulkInsertResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NetsqliteService_BulkInsertClient = grpc.ClientStreamingClient[BulkInsertRequest, BulkInsertResponse]
+
 func (c *netsqliteServiceClient) Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[QueryResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &NetsqliteService_ServiceDesc.Streams[0], NetsqliteService_Query_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &NetsqliteService_ServiceDesc.Streams[1], NetsqliteService_Query_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -87,6 +156,115 @@ func (c *netsqliteServiceClient) Query(ctx context.Context, in *QueryRequest, op
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type NetsqliteService_QueryClient = grpc.ServerStreamingClient[QueryResponse]
 
+func (c *netsqliteServiceClient) OpenCursor(ctx context.Context, in *OpenCursorRequest, opts ...grpc.CallOption) (*OpenCursorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OpenCursorResponse)
+	err := c.cc.Invoke(ctx, NetsqliteService_OpenCursor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netsqliteServiceClient) FetchCursor(ctx context.Context, in *FetchCursorRequest, opts ...grpc.CallOption) (*FetchCursorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FetchCursorResponse)
+	err := c.cc.Invoke(ctx, NetsqliteService_FetchCursor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netsqliteServiceClient) CloseCursor(ctx context.Context, in *CloseCursorRequest, opts ...grpc.CallOption) (*CloseCursorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloseCursorResponse)
+	err := c.cc.Invoke(ctx, NetsqliteService_CloseCursor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netsqliteServiceClient) BeginTx(ctx context.Context, in *BeginTxRequest, opts ...grpc.CallOption) (*BeginTxResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BeginTxResponse)
+	err := c.cc.Invoke(ctx, NetsqliteService_BeginTx_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netsqliteServiceClient) Commit(ctx context.Context, in *CommitRequest, opts ...grpc.CallOption) (*CommitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommitResponse)
+	err := c.cc.Invoke(ctx, NetsqliteService_Commit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netsqliteServiceClient) Rollback(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*RollbackResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RollbackResponse)
+	err := c.cc.Invoke(ctx, NetsqliteService_Rollback_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netsqliteServiceClient) Prepare(ctx context.Context, in *PrepareRequest, opts ...grpc.CallOption) (*PrepareResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PrepareResponse)
+	err := c.cc.Invoke(ctx, NetsqliteService_Prepare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netsqliteServiceClient) ExecPrepared(ctx context.Context, in *ExecPreparedRequest, opts ...grpc.CallOption) (*ExecResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecResponse)
+	err := c.cc.Invoke(ctx, NetsqliteService_ExecPrepared_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netsqliteServiceClient) QueryPrepared(ctx context.Context, in *QueryPreparedRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[QueryResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NetsqliteService_ServiceDesc.Streams[2], NetsqliteService_QueryPrepared_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[QueryPreparedRequest, QueryResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NetsqliteService_QueryPreparedClient = grpc.ServerStreamingClient[QueryResponse]
+
+func (c *netsqliteServiceClient) CloseStmt(ctx context.Context, in *CloseStmtRequest, opts ...grpc.CallOption) (*CloseStmtResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloseStmtResponse)
+	err := c.cc.Invoke(ctx, NetsqliteService_CloseStmt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NetsqliteServiceServer is the server API for NetsqliteService service.
 // All implementations must embed UnimplementedNetsqliteServiceServer
 // for forward compatibility.
@@ -97,8 +275,42 @@ type NetsqliteServiceServer interface {
 	Ping(context.Context, *PingRequest) (*PingResponse, error)
 	// Execute a non-query statement (INSERT, UPDATE, DELETE)
 	Exec(context.Context, *ExecRequest) (*ExecResponse, error)
+	// Execute an ordered list of statements on a single connection. By default
+	// they run atomically, the first failure rolls everything back.
+	ExecBatch(context.Context, *ExecBatchRequest) (*ExecBatchResponse, error)
+	// Insert a stream of rows into a table with a single prepared INSERT, all
+	// in one transaction. The first message must be the header.
+	BulkInsert(grpc.ClientStreamingServer[BulkInsertRequest, BulkInsertResponse]) error
 	// Execute a query statement (SELECT) - streams results back
 	Query(*QueryRequest, grpc.ServerStreamingServer[QueryResponse]) error
+	// Open a server side cursor over a query, rows are pulled with FetchCursor.
+	// The cursor holds a pooled connection until it is exhausted, closed or expires.
+	// For a query that writes that's the database's single writer, and it
+	// expires after the server's write_tx_idle_timeout if shorter.
+	OpenCursor(context.Context, *OpenCursorRequest) (*OpenCursorResponse, error)
+	// Fetch the next rows of a cursor
+	FetchCursor(context.Context, *FetchCursorRequest) (*FetchCursorResponse, error)
+	// Close a cursor and release its connection
+	CloseCursor(context.Context, *CloseCursorRequest) (*CloseCursorResponse, error)
+	// Start a transaction pinned to a single pooled connection. Subsequent
+	// Exec/Query calls carrying the returned transaction_id run inside it.
+	// Unless read_only, it holds the database's single writer connection until
+	// it ends, whatever its mode, so every other write waits for it; it's
+	// rolled back once idle for the server's write_tx_idle_timeout.
+	BeginTx(context.Context, *BeginTxRequest) (*BeginTxResponse, error)
+	// Commit a transaction previously started with BeginTx
+	Commit(context.Context, *CommitRequest) (*CommitResponse, error)
+	// Roll back a transaction previously started with BeginTx
+	Rollback(context.Context, *RollbackRequest) (*RollbackResponse, error)
+	// Prepare a statement once and run it many times by its statement_id.
+	// Statements are scoped to the client session that prepared them.
+	Prepare(context.Context, *PrepareRequest) (*PrepareResponse, error)
+	// Execute a prepared non-query statement
+	ExecPrepared(context.Context, *ExecPreparedRequest) (*ExecResponse, error)
+	// Execute a prepared query - streams results back like Query
+	QueryPrepared(*QueryPreparedRequest, grpc.ServerStreamingServer[QueryResponse]) error
+	// Release a prepared statement on the server
+	CloseStmt(context.Context, *CloseStmtRequest) (*CloseStmtResponse, error)
 	mustEmbedUnimplementedNetsqliteServiceServer()
 }
 
@@ -115,9 +327,45 @@ func (UnimplementedNetsqliteServiceServer) Ping(context.Context, *PingRequest) (
 func (UnimplementedNetsqliteServiceServer) Exec(context.Context, *ExecRequest) (*ExecResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Exec not implemented")
 }
+func (UnimplementedNetsqliteServiceServer) ExecBatch(context.Context, *ExecBatchRequest) (*ExecBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecBatch not implemented")
+}
+func (UnimplementedNetsqliteServiceServer) BulkInsert(grpc.ClientStreamingServer[BulkInsertRequest, BulkInsertResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method BulkInsert not implemented")
+}
 func (UnimplementedNetsqliteServiceServer) Query(*QueryRequest, grpc.ServerStreamingServer[QueryResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method Query not implemented")
 }
+func (UnimplementedNetsqliteServiceServer) OpenCursor(context.Context, *OpenCursorRequest) (*OpenCursorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OpenCursor not implemented")
+}
+func (UnimplementedNetsqliteServiceServer) FetchCursor(context.Context, *FetchCursorRequest) (*FetchCursorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchCursor not implemented")
+}
+func (UnimplementedNetsqliteServiceServer) CloseCursor(context.Context, *CloseCursorRequest) (*CloseCursorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseCursor not implemented")
+}
+func (UnimplementedNetsqliteServiceServer) BeginTx(context.Context, *BeginTxRequest) (*BeginTxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BeginTx not implemented")
+}
+func (UnimplementedNetsqliteServiceServer) Commit(context.Context, *CommitRequest) (*CommitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Commit not implemented")
+}
+func (UnimplementedNetsqliteServiceServer) Rollback(context.Context, *RollbackRequest) (*RollbackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rollback not implemented")
+}
+func (UnimplementedNetsqliteServiceServer) Prepare(context.Context, *PrepareRequest) (*PrepareResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Prepare not implemented")
+}
+func (UnimplementedNetsqliteServiceServer) ExecPrepared(context.Context, *ExecPreparedRequest) (*ExecResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecPrepared not implemented")
+}
+func (UnimplementedNetsqliteServiceServer) QueryPrepared(*QueryPreparedRequest, grpc.ServerStreamingServer[QueryResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method QueryPrepared not implemented")
+}
+func (UnimplementedNetsqliteServiceServer) CloseStmt(context.Context, *CloseStmtRequest) (*CloseStmtResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseStmt not implemented")
+}
 func (UnimplementedNetsqliteServiceServer) mustEmbedUnimplementedNetsqliteServiceServer() {}
 func (UnimplementedNetsqliteServiceServer) testEmbeddedByValue()                          {}
 
@@ -175,6 +423,31 @@ func _NetsqliteService_Exec_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NetsqliteService_ExecBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetsqliteServiceServer).ExecBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetsqliteService_ExecBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetsqliteServiceServer).ExecBatch(ctx, req.(*ExecBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetsqliteService_BulkInsert_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(NetsqliteServiceServer).BulkInsert(&grpc.GenericServerStream[BulkInsertRequest, BulkInsertResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NetsqliteService_BulkInsertServer = grpc.ClientStreamingServer[BulkInsertRequest, BulkInsertResponse]
+
 func _NetsqliteService_Query_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(QueryRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -186,6 +459,179 @@ func _NetsqliteService_Query_Handler(srv interface{}, stream grpc.ServerStream)
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type NetsqliteService_QueryServer = grpc.ServerStreamingServer[QueryResponse]
 
+func _NetsqliteService_OpenCursor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenCursorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetsqliteServiceServer).OpenCursor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetsqliteService_OpenCursor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetsqliteServiceServer).OpenCursor(ctx, req.(*OpenCursorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetsqliteService_FetchCursor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchCursorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetsqliteServiceServer).FetchCursor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetsqliteService_FetchCursor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetsqliteServiceServer).FetchCursor(ctx, req.(*FetchCursorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetsqliteService_CloseCursor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseCursorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetsqliteServiceServer).CloseCursor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetsqliteService_CloseCursor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetsqliteServiceServer).CloseCursor(ctx, req.(*CloseCursorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetsqliteService_BeginTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BeginTxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetsqliteServiceServer).BeginTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetsqliteService_BeginTx_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetsqliteServiceServer).BeginTx(ctx, req.(*BeginTxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetsqliteService_Commit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetsqliteServiceServer).Commit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetsqliteService_Commit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetsqliteServiceServer).Commit(ctx, req.(*CommitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetsqliteService_Rollback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetsqliteServiceServer).Rollback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetsqliteService_Rollback_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetsqliteServiceServer).Rollback(ctx, req.(*RollbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetsqliteService_Prepare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrepareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetsqliteServiceServer).Prepare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetsqliteService_Prepare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetsqliteServiceServer).Prepare(ctx, req.(*PrepareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetsqliteService_ExecPrepared_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecPreparedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetsqliteServiceServer).ExecPrepared(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetsqliteService_ExecPrepared_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetsqliteServiceServer).ExecPrepared(ctx, req.(*ExecPreparedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetsqliteService_QueryPrepared_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QueryPreparedRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NetsqliteServiceServer).QueryPrepared(m, &grpc.GenericServerStream[QueryPreparedRequest, QueryResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NetsqliteService_QueryPreparedServer = grpc.ServerStreamingServer[QueryResponse]
+
+func _NetsqliteService_CloseStmt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseStmtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetsqliteServiceServer).CloseStmt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetsqliteService_CloseStmt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetsqliteServiceServer).CloseStmt(ctx, req.(*CloseStmtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // NetsqliteService_ServiceDesc is the grpc.ServiceDesc for NetsqliteService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -201,13 +647,468 @@ var NetsqliteService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Exec",
 			Handler:    _NetsqliteService_Exec_Handler,
 		},
+		{
+			MethodName: "ExecBatch",
+			Handler:    _NetsqliteService_ExecBatch_Handler,
+		},
+		{
+			MethodName: "OpenCursor",
+			Handler:    _NetsqliteService_OpenCursor_Handler,
+		},
+		{
+			MethodName: "FetchCursor",
+			Handler:    _NetsqliteService_FetchCursor_Handler,
+		},
+		{
+			MethodName: "CloseCursor",
+			Handler:    _NetsqliteService_CloseCursor_Handler,
+		},
+		{
+			MethodName: "BeginTx",
+			Handler:    _NetsqliteService_BeginTx_Handler,
+		},
+		{
+			MethodName: "Commit",
+			Handler:    _NetsqliteService_Commit_Handler,
+		},
+		{
+			MethodName: "Rollback",
+			Handler:    _NetsqliteService_Rollback_Handler,
+		},
+		{
+			MethodName: "Prepare",
+			Handler:    _NetsqliteService_Prepare_Handler,
+		},
+		{
+			MethodName: "ExecPrepared",
+			Handler:    _NetsqliteService_ExecPrepared_Handler,
+		},
+		{
+			MethodName: "CloseStmt",
+			Handler:    _NetsqliteService_CloseStmt_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BulkInsert",
+			Handler:       _NetsqliteService_BulkInsert_Handler,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "Query",
 			Handler:       _NetsqliteService_Query_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "QueryPrepared",
+			Handler:       _NetsqliteService_QueryPrepared_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/netsqlite/v1/netsqlite.proto",
+}
+
+const (
+	AdminService_GetPragmas_FullMethodName      = "/netsqlite.v1.AdminService/GetPragmas"
+	AdminService_ListDatabases_FullMethodName   = "/netsqlite.v1.AdminService/ListDatabases"
+	AdminService_GetDatabaseInfo_FullMethodName = "/netsqlite.v1.AdminService/GetDatabaseInfo"
+	AdminService_CreateDatabase_FullMethodName  = "/netsqlite.v1.AdminService/CreateDatabase"
+	AdminService_DropDatabase_FullMethodName    = "/netsqlite.v1.AdminService/DropDatabase"
+	AdminService_Backup_FullMethodName          = "/netsqlite.v1.AdminService/Backup"
+	AdminService_Restore_FullMethodName         = "/netsqlite.v1.AdminService/Restore"
+	AdminService_ListBackups_FullMethodName     = "/netsqlite.v1.AdminService/ListBackups"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Maintenance operations, restricted to admin tokens
+type AdminServiceClient interface {
+	// Report the pragmas new connections to a database end up with
+	GetPragmas(ctx context.Context, in *GetPragmasRequest, opts ...grpc.CallOption) (*GetPragmasResponse, error)
+	// List the databases of the data directory the token has access to. It
+	// doesn't open them, so it needs no x-database-name header.
+	ListDatabases(ctx context.Context, in *ListDatabasesRequest, opts ...grpc.CallOption) (*ListDatabasesResponse, error)
+	// Report the size and state of a database
+	GetDatabaseInfo(ctx context.Context, in *GetDatabaseInfoRequest, opts ...grpc.CallOption) (*GetDatabaseInfoResponse, error)
+	// Create an empty database, even if the server doesn't create them on
+	// first use. Fails with ALREADY_EXISTS if it does exist.
+	CreateDatabase(ctx context.Context, in *CreateDatabaseRequest, opts ...grpc.CallOption) (*CreateDatabaseResponse, error)
+	// Close a database and delete its files. Fails with FAILED_PRECONDITION
+	// while it is in use.
+	DropDatabase(ctx context.Context, in *DropDatabaseRequest, opts ...grpc.CallOption) (*DropDatabaseResponse, error)
+	// Stream a consistent snapshot of a database, taken with the SQLite online
+	// backup API while clients keep using it. The chunks are followed by a
+	// summary with the checksum of the whole file.
+	Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BackupResponse], error)
+	// Replace a database, or create it, with an uploaded SQLite file. The
+	// first message must be the header. The file is checked before it's
+	// swapped in, meanwhile requests for the database fail with UNAVAILABLE
+	// and can be retried. Fails with ABORTED if the database stays in use.
+	Restore(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[RestoreRequest, RestoreResponse], error)
+	// List the backups the server took on schedule of the databases the
+	// token has access to. Like ListDatabases, it needs no x-database-name
+	// header. Fails with FAILED_PRECONDITION if scheduled backups are off.
+	ListBackups(ctx context.Context, in *ListBackupsRequest, opts ...grpc.CallOption) (*ListBackupsResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) GetPragmas(ctx context.Context, in *GetPragmasRequest, opts ...grpc.CallOption) (*GetPragmasResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPragmasResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetPragmas_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListDatabases(ctx context.Context, in *ListDatabasesRequest, opts ...grpc.CallOption) (*ListDatabasesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDatabasesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListDatabases_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetDatabaseInfo(ctx context.Context, in *GetDatabaseInfoRequest, opts ...grpc.CallOption) (*GetDatabaseInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDatabaseInfoResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetDatabaseInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) CreateDatabase(ctx context.Context, in *CreateDatabaseRequest, opts ...grpc.CallOption) (*CreateDatabaseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateDatabaseResponse)
+	err := c.cc.Invoke(ctx, AdminService_CreateDatabase_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DropDatabase(ctx context.Context, in *DropDatabaseRequest, opts ...grpc.CallOption) (*DropDatabaseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DropDatabaseResponse)
+	err := c.cc.Invoke(ctx, AdminService_DropDatabase_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BackupResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AdminService_ServiceDesc.Streams[0], AdminService_Backup_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BackupRequest, BackupResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdminService_BackupClient = grpc.ServerStreamingClient[BackupResponse]
+
+func (c *adminServiceClient) Restore(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[RestoreRequest, RestoreResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AdminService_ServiceDesc.Streams[1], AdminService_Restore_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RestoreRequest, RestoreResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdminService_RestoreClient = grpc.ClientStreamingClient[RestoreRequest, RestoreResponse]
+
+func (c *adminServiceClient) ListBackups(ctx context.Context, in *ListBackupsRequest, opts ...grpc.CallOption) (*ListBackupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBackupsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListBackups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+//
+// Maintenance operations, restricted to admin tokens
+type AdminServiceServer interface {
+	// Report the pragmas new connections to a database end up with
+	GetPragmas(context.Context, *GetPragmasRequest) (*GetPragmasResponse, error)
+	// List the databases of the data directory the token has access to. It
+	// doesn't open them, so it needs no x-database-name header.
+	ListDatabases(context.Context, *ListDatabasesRequest) (*ListDatabasesResponse, error)
+	// Report the size and state of a database
+	GetDatabaseInfo(context.Context, *GetDatabaseInfoRequest) (*GetDatabaseInfoResponse, error)
+	// Create an empty database, even if the server doesn't create them on
+	// first use. Fails with ALREADY_EXISTS if it does exist.
+	CreateDatabase(context.Context, *CreateDatabaseRequest) (*CreateDatabaseResponse, error)
+	// Close a database and delete its files. Fails with FAILED_PRECONDITION
+	// while it is in use.
+	DropDatabase(context.Context, *DropDatabaseRequest) (*DropDatabaseResponse, error)
+	// Stream a consistent snapshot of a database, taken with the SQLite online
+	// backup API while clients keep using it. The chunks are followed by a
+	// summary with the checksum of the whole file.
+	Backup(*BackupRequest, grpc.ServerStreamingServer[BackupResponse]) error
+	// Replace a database, or create it, with an uploaded SQLite file. The
+	// first message must be the header. The file is checked before it's
+	// swapped in, meanwhile requests for the database fail with UNAVAILABLE
+	// and can be retried. Fails with ABORTED if the database stays in use.
+	Restore(grpc.ClientStreamingServer[RestoreRequest, RestoreResponse]) error
+	// List the backups the server took on schedule of the databases the
+	// token has access to. Like ListDatabases, it needs no x-database-name
+	// header. Fails with FAILED_PRECONDITION if scheduled backups are off.
+	ListBackups(context.Context, *ListBackupsRequest) (*ListBackupsResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) GetPragmas(context.Context, *GetPragmasRequest) (*GetPragmasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPragmas not implemented")
+}
+func (UnimplementedAdminServiceServer) ListDatabases(context.Context, *ListDatabasesRequest) (*ListDatabasesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDatabases not implemented")
+}
+func (UnimplementedAdminServiceServer) GetDatabaseInfo(context.Context, *GetDatabaseInfoRequest) (*GetDatabaseInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDatabaseInfo not implemented")
+}
+func (UnimplementedAdminServiceServer) CreateDatabase(context.Context, *CreateDatabaseRequest) (*CreateDatabaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDatabase not implemented")
+}
+func (UnimplementedAdminServiceServer) DropDatabase(context.Context, *DropDatabaseRequest) (*DropDatabaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropDatabase not implemented")
+}
+func (UnimplementedAdminServiceServer) Backup(*BackupRequest, grpc.ServerStreamingServer[BackupResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Backup not implemented")
+}
+func (UnimplementedAdminServiceServer) Restore(grpc.ClientStreamingServer[RestoreRequest, RestoreResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedAdminServiceServer) ListBackups(context.Context, *ListBackupsRequest) (*ListBackupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBackups not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_GetPragmas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPragmasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetPragmas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetPragmas_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetPragmas(ctx, req.(*GetPragmasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListDatabases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDatabasesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListDatabases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListDatabases_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListDatabases(ctx, req.(*ListDatabasesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetDatabaseInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDatabaseInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetDatabaseInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetDatabaseInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetDatabaseInfo(ctx, req.(*GetDatabaseInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_CreateDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDatabaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CreateDatabase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CreateDatabase_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CreateDatabase(ctx, req.(*CreateDatabaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DropDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DropDatabaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DropDatabase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DropDatabase_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DropDatabase(ctx, req.(*DropDatabaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_Backup_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BackupRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServiceServer).Backup(m, &grpc.GenericServerStream[BackupRequest, BackupResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdminService_BackupServer = grpc.ServerStreamingServer[BackupResponse]
+
+func _AdminService_Restore_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AdminServiceServer).Restore(&grpc.GenericServerStream[RestoreRequest, RestoreResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdminService_RestoreServer = grpc.ClientStreamingServer[RestoreRequest, RestoreResponse]
+
+func _AdminService_ListBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBackupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListBackups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListBackups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListBackups(ctx, req.(*ListBackupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "netsqlite.v1.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPragmas",
+			Handler:    _AdminService_GetPragmas_Handler,
+		},
+		{
+			MethodName: "ListDatabases",
+			Handler:    _AdminService_ListDatabases_Handler,
+		},
+		{
+			MethodName: "GetDatabaseInfo",
+			Handler:    _AdminService_GetDatabaseInfo_Handler,
+		},
+		{
+			MethodName: "CreateDatabase",
+			Handler:    _AdminService_CreateDatabase_Handler,
+		},
+		{
+			MethodName: "DropDatabase",
+			Handler:    _AdminService_DropDatabase_Handler,
+		},
+		{
+			MethodName: "ListBackups",
+			Handler:    _AdminService_ListBackups_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Backup",
+			Handler:       _AdminService_Backup_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Restore",
+			Handler:       _AdminService_Restore_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "proto/netsqlite/v1/netsqlite.proto",
 }